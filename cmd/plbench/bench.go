@@ -0,0 +1,175 @@
+package main
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/chain"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/dynamic"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/harness"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/walk"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+)
+
+// A builder builds a point locator over a DCEL, using typ
+// for any binary search trees it needs and seed for any
+// random choices it makes.
+type builder func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error)
+
+var builders = map[string]builder{
+	"slab": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return slab.Decompose(dc, typ)
+	},
+	"trapezoid": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		_, _, tr, err := trapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, seed)
+		return tr, err
+	},
+	"chain": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return chain.New(dc)
+	},
+	"rtree": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return rtree.DCELtoRtree(dc)
+	},
+	"walk": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return walk.NewSeeded(dc, seed), nil
+	},
+	"dynamic": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return dynamic.New(dc)
+	},
+	"plumbline": func(dc *dcel.DCEL, typ tree.Type, seed int64) (pointLoc.LocatesPoints, error) {
+		return bruteForce.PlumbLine(dc), nil
+	},
+}
+
+// treeLocators are the locators whose builders use the
+// tree type they are given. Results for other locators
+// report no tree.
+var treeLocators = map[string]bool{
+	"slab": true,
+}
+
+// treeTypes are the tree types which can be benchmarked.
+// tree.New builds a red black tree for AVL and Splay, so
+// those are left out rather than reported under a name
+// they do not measure.
+var treeTypes = map[string]tree.Type{
+	"redblack": tree.RedBlack,
+}
+
+// An input is a DCEL to benchmark locators over.
+type input struct {
+	name string
+	dc   *dcel.DCEL
+}
+
+// A Result is the measurements of one locator over one input.
+// Durations are in nanoseconds.
+type Result struct {
+	Input       string `json:"input"`
+	Vertices    int    `json:"vertices"`
+	Faces       int    `json:"faces"`
+	Locator     string `json:"locator"`
+	Tree        string `json:"tree,omitempty"`
+	Queries     int    `json:"queries"`
+	BuildNs     int64  `json:"build_ns"`
+	BuildBytes  uint64 `json:"build_bytes"`
+	BuildAllocs uint64 `json:"build_allocs"`
+	QueryBytes  uint64 `json:"query_bytes"`
+	MeanNs      int64  `json:"mean_ns"`
+	P50Ns       int64  `json:"p50_ns"`
+	P90Ns       int64  `json:"p90_ns"`
+	P99Ns       int64  `json:"p99_ns"`
+	MaxNs       int64  `json:"max_ns"`
+	Errors      int    `json:"errors"`
+	Err         string `json:"error,omitempty"`
+}
+
+// measure builds the named locator over in, seeded by seed,
+// and times each of queries on it. Allocation counts are taken across the
+// whole process, so measure should not run alongside other
+// work.
+func measure(in input, name, treeName string, seed int64, queries [][2]float64) Result {
+	r := Result{
+		Input:    in.name,
+		Vertices: len(in.dc.Vertices),
+		Faces:    len(in.dc.Faces),
+		Locator:  name,
+		Queries:  len(queries),
+	}
+	if treeLocators[name] {
+		r.Tree = treeName
+	}
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	lp, err := builders[name](in.dc, treeTypes[treeName], seed)
+	r.BuildNs = int64(time.Since(start))
+	runtime.ReadMemStats(&after)
+	r.BuildBytes = after.TotalAlloc - before.TotalAlloc
+	r.BuildAllocs = after.Mallocs - before.Mallocs
+	if err != nil {
+		r.Err = err.Error()
+		return r
+	}
+	if len(queries) == 0 {
+		return r
+	}
+
+	lats := make([]int64, len(queries))
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	for i, q := range queries {
+		start := time.Now()
+		_, err := lp.PointLocate(q[0], q[1])
+		lats[i] = int64(time.Since(start))
+		if err != nil {
+			r.Errors++
+		}
+	}
+	runtime.ReadMemStats(&after)
+	r.QueryBytes = (after.TotalAlloc - before.TotalAlloc) / uint64(len(queries))
+
+	sort.Slice(lats, func(i, j int) bool { return lats[i] < lats[j] })
+	var sum int64
+	for _, l := range lats {
+		sum += l
+	}
+	r.MeanNs = sum / int64(len(lats))
+	r.P50Ns = percentile(lats, 50)
+	r.P90Ns = percentile(lats, 90)
+	r.P99Ns = percentile(lats, 99)
+	r.MaxNs = lats[len(lats)-1]
+	return r
+}
+
+// percentile returns the pth percentile of sorted by the
+// nearest rank method.
+func percentile(sorted []int64, p int) int64 {
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// queryPoints returns n points drawn from seed across dc's
+// bounds, converted once so that each locator sees the same
+// queries.
+func queryPoints(dc *dcel.DCEL, n int, seed int64) [][2]float64 {
+	pts := harness.Queries(dc, n, rand.New(rand.NewSource(seed)))
+	qs := make([][2]float64, len(pts))
+	for i, p := range pts {
+		qs[i] = [2]float64{p.X(), p.Y()}
+	}
+	return qs
+}
@@ -0,0 +1,144 @@
+package main
+
+// plbench benchmarks point locators over OFF files or
+// generated DCELs, reporting build time, query latency
+// percentiles and allocated memory as CSV or JSON.
+//
+// Usage:
+//
+//	plbench [flags] [file.off ...]
+//
+// Each OFF file given is benchmarked, and a DCEL is generated
+// for each of -splits unless -splits is empty. For example,
+//
+//	plbench -locators slab,trapezoid -splits 16,64 -queries 10000 -format json
+//
+// Reports are written once every locator has run.
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+)
+
+var (
+	locatorList string
+	treeName    string
+	splitsList  string
+	size        float64
+	seed        int64
+	queries     int
+	runs        int
+	format      string
+	outputName  string
+)
+
+func init() {
+	flag.StringVar(&locatorList, "locators", "slab,trapezoid,chain,rtree,walk,dynamic",
+		"comma separated locators to benchmark, from "+strings.Join(locatorNames(), ","))
+	flag.StringVar(&treeName, "tree", "redblack", "search tree type for locators which use one: redblack")
+	flag.StringVar(&splitsList, "splits", "8,32,128", "comma separated split counts of DCELs to generate")
+	flag.Float64Var(&size, "size", 100, "width and height of generated DCELs")
+	flag.Int64Var(&seed, "seed", 1, "seed for generated DCELs, query points and randomized locators")
+	flag.IntVar(&queries, "queries", 1000, "query points per input")
+	flag.IntVar(&runs, "runs", 1, "times to build and query each locator on each input")
+	flag.StringVar(&format, "format", "csv", "report format: csv or json")
+	flag.StringVar(&outputName, "o", "", "report file, instead of the standard output")
+}
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "plbench:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	write, ok := writers[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	if _, ok := treeTypes[treeName]; !ok {
+		return fmt.Errorf("unknown tree type %q", treeName)
+	}
+	names, err := parseLocators(locatorList)
+	if err != nil {
+		return err
+	}
+	ins, err := inputs(flag.Args(), splitsList)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if outputName != "" {
+		f, err := os.Create(outputName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	results := []Result{}
+	for _, in := range ins {
+		qs := queryPoints(in.dc, queries, seed)
+		for _, name := range names {
+			for i := 0; i < runs; i++ {
+				results = append(results, measure(in, name, treeName, seed, qs))
+			}
+		}
+	}
+	return write(out, results)
+}
+
+func locatorNames() []string {
+	names := make([]string, 0, len(builders))
+	for name := range builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseLocators(list string) ([]string, error) {
+	names := strings.Split(list, ",")
+	for _, name := range names {
+		if _, ok := builders[name]; !ok {
+			return nil, fmt.Errorf("unknown locator %q", name)
+		}
+	}
+	return names, nil
+}
+
+// inputs loads each of files, then generates a DCEL for each
+// split count in splits.
+func inputs(files []string, splits string) ([]input, error) {
+	ins := []input{}
+	for _, file := range files {
+		dc, err := off.Load(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		ins = append(ins, input{file, dc})
+	}
+	if splits == "" {
+		return ins, nil
+	}
+	for _, s := range strings.Split(splits, ",") {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("bad split count %q", s)
+		}
+		name := fmt.Sprintf("random(size=%v,splits=%d,seed=%d)", size, n, seed)
+		ins = append(ins, input{name, dcel.Random2DDCELSeeded(size, n, seed)})
+	}
+	return ins, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	lats := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, int64(5), percentile(lats, 50))
+	assert.Equal(t, int64(9), percentile(lats, 90))
+	assert.Equal(t, int64(10), percentile(lats, 99))
+	assert.Equal(t, int64(1), percentile(lats, 0))
+}
+
+func TestMeasure(t *testing.T) {
+	in := input{"random", dcel.Random2DDCELSeeded(100, 8, 49)}
+	qs := queryPoints(in.dc, 200, 49)
+	for _, name := range locatorNames() {
+		r := measure(in, name, "redblack", 49, qs)
+		assert.Equal(t, name, r.Locator)
+		if name == "slab" {
+			assert.Equal(t, "redblack", r.Tree)
+		} else {
+			assert.Equal(t, "", r.Tree)
+		}
+		assert.Equal(t, 200, r.Queries)
+		assert.Equal(t, "", r.Err)
+		assert.Equal(t, 0, r.Errors)
+		assert.True(t, r.P50Ns <= r.P90Ns && r.P90Ns <= r.P99Ns && r.P99Ns <= r.MaxNs)
+	}
+}
+
+func TestReports(t *testing.T) {
+	results := []Result{
+		{Input: "a", Locator: "slab", Queries: 10, P50Ns: 5},
+		{Input: "b", Locator: "walk", BuildBytes: 7, Err: "bad"},
+	}
+	b := &bytes.Buffer{}
+	assert.Nil(t, writeCSV(b, results))
+	rows, err := csv.NewReader(b).ReadAll()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, "input", rows[0][0])
+	assert.Equal(t, "error", rows[0][len(rows[0])-1])
+	assert.Equal(t, []string{"b", "0", "0", "walk", "", "0", "0", "7"}, rows[2][:8])
+	assert.Equal(t, "bad", rows[2][len(rows[2])-1])
+
+	b.Reset()
+	assert.Nil(t, writeJSON(b, results))
+	results2 := []Result{}
+	assert.Nil(t, json.Unmarshal(b.Bytes(), &results2))
+	assert.Equal(t, results, results2)
+}
+
+func TestInputs(t *testing.T) {
+	ins, err := inputs(nil, "0,4")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ins))
+	assert.Equal(t, 4, len(ins[0].dc.Vertices))
+	_, err = inputs(nil, "4,x")
+	assert.NotNil(t, err)
+	_, err = inputs([]string{"missing.off"}, "")
+	assert.NotNil(t, err)
+	_, err = parseLocators("slab,nope")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+// writers write results in each report format.
+var writers = map[string]func(io.Writer, []Result) error{
+	"csv":  writeCSV,
+	"json": writeJSON,
+}
+
+// writeCSV writes a header row named by Result's json tags,
+// then a row for each result.
+func writeCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	typ := reflect.TypeOf(Result{})
+	row := make([]string, typ.NumField())
+	for i := range row {
+		row[i] = csvName(typ.Field(i))
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	for _, r := range results {
+		v := reflect.ValueOf(r)
+		for i := range row {
+			row[i] = csvValue(v.Field(i))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	for i, c := range tag {
+		if c == ',' {
+			return tag[:i]
+		}
+	}
+	return tag
+}
+
+func csvValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return v.String()
+}
+
+func writeJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(results)
+}
@@ -257,6 +257,7 @@ func (dc *DCEL) Copy() *DCEL {
 	for i, v := range dc.Vertices {
 		vPointerMap[v] = i
 		v2 := NewVertex(v.X(), v.Y(), v.Z())
+		v2.Exact = v.Exact
 		dc2.Vertices[i] = v2
 		if v.OutEdge != nil {
 			v2.OutEdge = dc2.HalfEdges[ePointerMap[v.OutEdge]]
@@ -276,6 +277,9 @@ func (dc *DCEL) Copy() *DCEL {
 		if e.Origin != nil {
 			e2.Origin = dc2.Vertices[vPointerMap[e.Origin]]
 		}
+		if fi, ok := fPointerMap[e.Face]; ok {
+			e2.Face = dc2.Faces[fi]
+		}
 	}
 
 	return dc2
@@ -62,11 +62,21 @@ func (e *Edge) At(i int) geom.Dimensional {
 // Set sets the value behind a point on e
 // to a given point
 func (e *Edge) Set(i int, d geom.Dimensional) geom.Spanning {
+	var v *Vertex
 	if i == 0 {
-		e.Origin.Point = d.(geom.Point)
+		v = e.Origin
 	} else if i == 1 {
-		e.Twin.Origin.Point = d.(geom.Point)
+		v = e.Twin.Origin
+	} else {
+		return e
 	}
+	if ed, ok := d.(geom.ExactDimensional); ok {
+		v.Point = geom.Point{ed.Val(0), ed.Val(1), ed.Val(2)}
+		v.Exact = ed
+		return e
+	}
+	v.Point = d.(geom.Point)
+	v.Exact = nil
 	return e
 }
 
@@ -132,6 +142,16 @@ func (e *Edge) FullEdge() (geom.FullEdge, error) {
 		e2.Origin.Point}, nil
 }
 
+// Segment returns this edge as a segment from its
+// origin to its twin's origin.
+func (e *Edge) Segment() (geom.Segment, error) {
+	fe, err := e.FullEdge()
+	if err != nil {
+		return geom.Segment{}, err
+	}
+	return fe.Segment(), nil
+}
+
 // High returns whichever point on e is higher
 // in dimension d
 func (e *Edge) High(d int) geom.Dimensional {
@@ -179,7 +199,7 @@ func (e *Edge) Mid2D() (geom.Point, error) {
 func (e *Edge) Compare(i interface{}) search.CompareResult {
 	switch c := i.(type) {
 	case geom.Point:
-		return geom.VerticalCompare(c, e)
+		return geom.VerticalComparePoints(c, e.Origin.Position(), e.Twin.Origin.Position())
 	default:
 		return search.Invalid
 	}
@@ -347,13 +367,19 @@ func (e *Edge) EdgeChain() []*Edge {
 // This function is deterministic. It does not return
 // a -random- value, if multiple values are valid.
 func (e *Edge) FindSharedPoint(e2 *Edge, d int) (float64, error) {
+	return e.FindSharedPointWithin(e2, d, geom.DefaultTolerance)
+}
+
+// FindSharedPointWithin acts as FindSharedPoint, treating
+// endpoints within tolerance t of one another as equal.
+func (e *Edge) FindSharedPointWithin(e2 *Edge, d int, t geom.Tolerance) (float64, error) {
 	eLow := e.Low(d)
 	e2Low := e2.Low(d)
 	eHigh := e.High(d)
 	e2High := e2.High(d)
 	// The two edges span the same distance case
-	if geom.F64eq(eLow.Val(d), e2Low.Val(d)) &&
-		geom.F64eq(eHigh.Val(d), e2High.Val(d)) {
+	if t.F64eq(eLow.Val(d), e2Low.Val(d)) &&
+		t.F64eq(eHigh.Val(d), e2High.Val(d)) {
 		return ((eHigh.Val(d) - eLow.Val(d)) / 2) + eLow.Val(d), nil
 	}
 
@@ -406,10 +432,7 @@ func (e *Edge) PointAlong(d int, pcnt float64) geom.D3 {
 	if pcnt >= 1 {
 		return p2
 	}
-	return geom.NewPoint(
-		p1.X()+(p2.X()-p1.X())*pcnt,
-		p1.Y()+(p2.Y()-p1.Y())*pcnt,
-		p1.Z()+(p2.Z()-p1.Z())*pcnt)
+	return geom.NewSegment(p1, p2).Eval(pcnt)
 }
 
 // Copy returns a copy of edge e
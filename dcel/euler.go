@@ -0,0 +1,332 @@
+package dcel
+
+import (
+	compgeo "github.com/200sc/go-compgeo"
+)
+
+// The functions in this file are Euler operators, each
+// making one small change to a DCEL which keeps it a valid
+// subdivision: splitting and joining edges at vertices and
+// splitting and merging faces across edges. They do not
+// check that the geometry of the result is planar.
+// Operators which remove elements take time linear in the
+// size of the DCEL, to remove them from its slices.
+
+// SplitEdge places v along e, so that e ends at v and a new
+// edge continues from v to where e used to end. The twins of
+// both are updated to match, and v is added to dc. It returns
+// the new half edge, which starts at v and bounds the same
+// face as e.
+func (dc *DCEL) SplitEdge(e *Edge, v *Vertex) (*Edge, error) {
+	if !linked(e) || !linked(e.Twin) || v == nil {
+		return nil, compgeo.BadEdgeError{}
+	}
+	d := e.Twin
+	dest := d.Origin
+	n := &Edge{Origin: v, Face: e.Face}
+	nt := &Edge{Origin: dest, Face: d.Face}
+	n.Twin = nt
+	nt.Twin = n
+
+	// e now runs to v, and n from v to dest
+	n.Next = e.Next
+	n.Next.Prev = n
+	e.Next = n
+	n.Prev = e
+
+	// d now runs from v, and nt from dest to v
+	nt.Prev = d.Prev
+	nt.Prev.Next = nt
+	nt.Next = d
+	d.Prev = nt
+	d.Origin = v
+
+	if dest.OutEdge == d {
+		dest.OutEdge = nt
+	}
+	v.OutEdge = n
+	dc.Vertices = append(dc.Vertices, v)
+	dc.HalfEdges = append(dc.HalfEdges, n, nt)
+	return n, nil
+}
+
+// RemoveVertex undoes SplitEdge, removing v, which must
+// have exactly two edges, and joining those edges into one.
+func (dc *DCEL) RemoveVertex(v *Vertex) error {
+	if v == nil || v.OutEdge == nil {
+		return compgeo.BadVertexError{}
+	}
+	if !linked(v.OutEdge) {
+		return compgeo.BadEdgeError{}
+	}
+	es := v.AllEdges()
+	if len(es) != 2 {
+		return compgeo.BadVertexError{}
+	}
+	// n runs from v to c, and m from v to a. We keep m
+	// and its twin, which will run between a and c.
+	n, m := es[0], es[1]
+	for _, e := range []*Edge{n, m, n.Twin, m.Twin} {
+		if !linked(e) {
+			return compgeo.BadEdgeError{}
+		}
+	}
+	if n.Twin.Origin == m.Twin.Origin {
+		return compgeo.BadVertexError{}
+	}
+	c := n.Twin.Origin
+	e := m.Twin
+
+	if n.Next == n.Twin {
+		// c is the end of a dangling chain of edges
+		e.Next = m
+		m.Prev = e
+	} else {
+		e.Next = n.Next
+		e.Next.Prev = e
+		m.Prev = n.Twin.Prev
+		m.Prev.Next = m
+	}
+	m.Origin = c
+
+	if c.OutEdge == n.Twin {
+		c.OutEdge = m
+	}
+	replaceBoundary(n.Face, n, e)
+	replaceBoundary(n.Twin.Face, n.Twin, m)
+	dc.removeEdges(n, n.Twin)
+	dc.removeVertex(v)
+	return nil
+}
+
+// SplitFace adds an edge from a to b across f, where a and b
+// are both on the same boundary chain of f, splitting f in two.
+// It returns the new half edge from a to b, which bounds
+// the new face. The half edge from b to a bounds f.
+func (dc *DCEL) SplitFace(a, b *Vertex, f *Face) (*Edge, error) {
+	if a == nil || b == nil || f == nil || a == b {
+		return nil, compgeo.BadVertexError{}
+	}
+	var ea, eb *Edge
+	inner := false
+	for i, chain := range []*Edge{f.Outer, f.Inner} {
+		if chain == nil {
+			continue
+		}
+		ea, eb = nil, nil
+		inner = i == 1
+		for _, e := range chain.EdgeChain() {
+			if e.Origin == a {
+				ea = e
+			} else if e.Origin == b {
+				eb = e
+			}
+		}
+		if ea != nil && eb != nil {
+			break
+		}
+	}
+	if ea == nil || eb == nil {
+		return nil, compgeo.BadVertexError{}
+	}
+	if !linked(ea) || !linked(eb) {
+		return nil, compgeo.BadEdgeError{}
+	}
+	g := NewFace()
+	h := &Edge{Origin: a, Face: g}
+	ht := &Edge{Origin: b, Face: f}
+	h.Twin = ht
+	ht.Twin = h
+
+	pa, pb := ea.Prev, eb.Prev
+	h.Prev = pa
+	pa.Next = h
+	h.Next = eb
+	eb.Prev = h
+	ht.Prev = pb
+	pb.Next = ht
+	ht.Next = ea
+	ea.Prev = ht
+
+	for _, e := range h.EdgeChain() {
+		e.Face = g
+	}
+	g.Outer = h
+	if inner {
+		f.Inner = ht
+	} else {
+		f.Outer = ht
+	}
+	dc.HalfEdges = append(dc.HalfEdges, h, ht)
+	dc.Faces = append(dc.Faces, g)
+	return h, nil
+}
+
+// MergeFaces undoes SplitFace, removing e and its twin and
+// joining the two faces they bound. The outer face is always
+// kept, and otherwise e's face is kept. The boundary chains
+// of the removed face which e's twin is not on are moved to
+// the kept face. As a face holds only one inner chain, if
+// the merged face would have more than one hole, an
+// UnsupportedError is returned and nothing is changed.
+func (dc *DCEL) MergeFaces(e *Edge) error {
+	if !linked(e) || !linked(e.Twin) || e.Face == e.Twin.Face {
+		return compgeo.BadEdgeError{}
+	}
+	if len(dc.Faces) > 0 && e.Twin.Face == dc.Faces[OUTER_FACE] {
+		e = e.Twin
+	}
+	et := e.Twin
+	keep, gone := e.Face, et.Face
+	eOuter := keep != nil && inChain(e, keep.Outer)
+	etOuter := gone != nil && inChain(et, gone.Outer)
+	var goneRest *Edge
+	if gone != nil {
+		goneRest = gone.Inner
+		if !etOuter {
+			goneRest = gone.Outer
+		}
+	}
+
+	// The chain e and et are on once they are removed, if
+	// anything is left of it
+	var merged *Edge
+	for _, e2 := range []*Edge{e.Prev, e.Next, et.Prev, et.Next} {
+		if e2 != e && e2 != et {
+			merged = e2
+			break
+		}
+	}
+	// Whichever face's shared chain was its outer boundary
+	// gives up its other chain as a hole. If both were outer
+	// boundaries, the merged chain is the new outer one.
+	var outer *Edge
+	holes := []*Edge{}
+	switch {
+	case eOuter && etOuter:
+		outer = merged
+		holes = append(holes, keep.Inner, goneRest)
+	case eOuter:
+		outer = goneRest
+		holes = append(holes, merged, keep.Inner)
+	default:
+		if keep != nil {
+			outer = keep.Outer
+		}
+		holes = append(holes, merged, goneRest)
+	}
+	var inner *Edge
+	for _, h := range holes {
+		if h == nil {
+			continue
+		}
+		if inner != nil {
+			return compgeo.UnsupportedError{}
+		}
+		inner = h
+	}
+
+	e.Prev.Next = et.Next
+	et.Next.Prev = e.Prev
+	et.Prev.Next = e.Next
+	e.Next.Prev = et.Prev
+
+	if e.Origin.OutEdge == e {
+		e.Origin.OutEdge = nextOut(et)
+	}
+	if et.Origin.OutEdge == et {
+		et.Origin.OutEdge = nextOut(e)
+	}
+	if keep != nil {
+		for _, chain := range []*Edge{outer, inner} {
+			if chain == nil {
+				continue
+			}
+			for _, e2 := range chain.EdgeChain() {
+				e2.Face = keep
+			}
+		}
+		keep.Outer = outer
+		keep.Inner = inner
+	}
+	dc.removeEdges(e, et)
+	dc.removeFace(gone)
+	return nil
+}
+
+// nextOut returns the edge after e, which leaves the vertex
+// e ends at, or nil if that is e's twin.
+func nextOut(e *Edge) *Edge {
+	if e.Next == e.Twin {
+		return nil
+	}
+	return e.Next
+}
+
+// linked returns whether e is part of a complete chain,
+// with its twin, next, and previous edges all set.
+func linked(e *Edge) bool {
+	return e != nil && e.Twin != nil && e.Next != nil && e.Prev != nil
+}
+
+// inChain returns whether e is on the boundary chain
+// starting at chain.
+func inChain(e, chain *Edge) bool {
+	if chain == nil {
+		return false
+	}
+	for _, e2 := range chain.EdgeChain() {
+		if e2 == e {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceBoundary points f at e2 instead of e, if f's
+// inner or outer boundary starts at e.
+func replaceBoundary(f *Face, e, e2 *Edge) {
+	if f == nil {
+		return
+	}
+	if f.Outer == e {
+		f.Outer = e2
+	}
+	if f.Inner == e {
+		f.Inner = e2
+	}
+}
+
+// removeEdges removes es from dc's half edges, keeping the
+// order of those that remain.
+func (dc *DCEL) removeEdges(es ...*Edge) {
+	out := dc.HalfEdges[:0]
+outer:
+	for _, e := range dc.HalfEdges {
+		for _, e2 := range es {
+			if e == e2 {
+				continue outer
+			}
+		}
+		out = append(out, e)
+	}
+	dc.HalfEdges = out
+}
+
+func (dc *DCEL) removeVertex(v *Vertex) {
+	for i, v2 := range dc.Vertices {
+		if v2 == v {
+			dc.Vertices = append(dc.Vertices[:i], dc.Vertices[i+1:]...)
+			return
+		}
+	}
+}
+
+func (dc *DCEL) removeFace(f *Face) {
+	for i, f2 := range dc.Faces {
+		if f2 == f {
+			dc.Faces = append(dc.Faces[:i], dc.Faces[i+1:]...)
+			return
+		}
+	}
+}
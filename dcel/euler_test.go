@@ -0,0 +1,85 @@
+package dcel
+
+import (
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+// holedRect returns a 20 by 10 rectangle split down its
+// middle into two faces, the left of which has a square hole.
+// It returns the left and right faces.
+func holedRect(t *testing.T) (*DCEL, *Face, *Face) {
+	dc := Rect(0, 0, 20, 10)
+	bot, top := NewVertex(10, 0, 0), NewVertex(10, 10, 0)
+	_, err := dc.SplitEdge(dc.Vertices[0].EdgeToward(dc.Vertices[1]), bot)
+	assert.Nil(t, err)
+	_, err = dc.SplitEdge(dc.Vertices[2].EdgeToward(dc.Vertices[3]), top)
+	assert.Nil(t, err)
+	right, err := dc.SplitFace(bot, top, dc.Faces[1])
+	assert.Nil(t, err)
+	left := dc.Faces[1]
+	assert.True(t, left.Contains(geom.NewPoint(5, 5, 0)))
+
+	// Splice in a square as the left face's hole
+	sq := Rect(4, 4, 2, 2)
+	for _, e := range sq.HalfEdges {
+		if e.Face == sq.Faces[OUTER_FACE] {
+			e.Face = left
+		}
+	}
+	left.Inner = sq.Faces[OUTER_FACE].Inner
+	dc.Vertices = append(dc.Vertices, sq.Vertices...)
+	dc.HalfEdges = append(dc.HalfEdges, sq.HalfEdges...)
+	dc.Faces = append(dc.Faces, sq.Faces[1])
+	return dc, left, right.Face
+}
+
+func TestMergeFacesHoles(t *testing.T) {
+	dc, left, right := holedRect(t)
+	hole := left.Inner
+	e := dc.Vertices[4].EdgeToward(dc.Vertices[5])
+	if e.Face != right {
+		e = e.Twin
+	}
+	assert.Nil(t, dc.MergeFaces(e))
+	assert.Equal(t, 3, len(dc.Faces))
+	assert.Equal(t, 6, len(right.Outer.EdgeChain()))
+	// The left face's hole is now the right face's
+	assert.True(t, right.Inner == hole)
+	for _, e := range dc.HalfEdges {
+		assert.True(t, e.Face != left)
+	}
+	for _, e := range hole.EdgeChain() {
+		assert.True(t, e.Face == right)
+	}
+	assert.True(t, right.Contains(geom.NewPoint(2, 5, 0)))
+
+	// Merging the result into the outer face would give the
+	// outer face two holes, which it cannot hold.
+	n := len(dc.HalfEdges)
+	e = dc.Vertices[0].EdgeToward(dc.Vertices[4])
+	assert.Equal(t, compgeo.UnsupportedError{}, dc.MergeFaces(e))
+	assert.Equal(t, n, len(dc.HalfEdges))
+	assert.Equal(t, 3, len(dc.Faces))
+	for _, e := range dc.HalfEdges {
+		assert.True(t, e.Next.Prev == e)
+	}
+}
+
+func TestEulerUnlinked(t *testing.T) {
+	// e has no next or previous edges, as edges bounding
+	// the outer face loaded from some sources used to.
+	dc := Rect(0, 0, 1, 1)
+	e := &Edge{Face: NewFace(), Twin: &Edge{Face: NewFace()}}
+	e.Twin.Twin = e
+	assert.Equal(t, compgeo.BadEdgeError{}, dc.MergeFaces(e))
+	_, err := dc.SplitEdge(e, NewVertex(0, 0, 0))
+	assert.Equal(t, compgeo.BadEdgeError{}, err)
+	v := NewVertex(0, 0, 0)
+	v.OutEdge = e
+	e.Origin = v
+	assert.Equal(t, compgeo.BadEdgeError{}, dc.RemoveVertex(v))
+}
@@ -43,6 +43,8 @@ func (f *Face) Contains(p geom.D2) bool {
 	x := p.X()
 	y := p.Y()
 	contains := false
+	// Float bounds are safe to compare against even for exact
+	// vertices, as rounding to the nearest float64 preserves order.
 	bounds := f.Bounds()
 	min := bounds.At(0).(geom.D2)
 	max := bounds.At(1).(geom.D2)
@@ -54,8 +56,15 @@ func (f *Face) Contains(p geom.D2) bool {
 	e1 := f.Outer.Prev
 	e2 := f.Outer
 	for {
-		if (e2.Y() > y) != (e1.Y() > y) {
-			if x < (e1.X()-e2.X())*(y-e2.Y())/(e1.Y()-e2.Y())+e2.X() {
+		p1 := e1.Origin.Position()
+		p2 := e2.Origin.Position()
+		above1 := geom.CmpVal(p1, p, 1) > 0
+		if (geom.CmpVal(p2, p, 1) > 0) != above1 {
+			// p is left of where e1->e2 crosses p's y value
+			// exactly when it is on the appropriate side of
+			// e2->e1, which depends on which point is higher.
+			o := geom.Orient2D(p2, p1, p)
+			if (above1 && o > 0) || (!above1 && o < 0) {
 				contains = !contains
 			}
 		}
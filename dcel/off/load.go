@@ -7,6 +7,7 @@ import (
 
 	compgeo "github.com/200sc/go-compgeo"
 	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
 )
 
 // Decode converts an OFF struct into a dcel.
@@ -110,6 +111,59 @@ func Load(file string) (*dcel.DCEL, error) {
 // Read peforms the underlying work to transform OFF data
 // into a dcel.DCEL.
 func Read(f io.Reader) (*dcel.DCEL, error) {
+	return read(f, readVertex)
+}
+
+// LoadExact acts as Load, but keeps each vertex at the exact
+// position written in the file. See ReadExact.
+func LoadExact(file string) (*dcel.DCEL, error) {
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadExact(f)
+}
+
+// ReadExact acts as Read, but keeps each vertex at the exact
+// position written in the file. Coordinates may be integers,
+// decimals or fractions such as 1/3. Vertices whose
+// coordinates are all integers within int64 are given
+// geom.IntPoint positions, and others geom.RatPoint positions.
+// Infinities and NaN are rejected as malformed.
+func ReadExact(f io.Reader) (*dcel.DCEL, error) {
+	return read(f, readExactVertex)
+}
+
+func readVertex(s *bufio.Scanner) (*dcel.Vertex, error) {
+	fs, err := readFloat64Line(s, 3)
+	if err != nil {
+		return nil, err
+	}
+	return dcel.NewVertex(fs[0], fs[1], fs[2]), nil
+}
+
+func readExactVertex(s *bufio.Scanner) (*dcel.Vertex, error) {
+	rs, err := readRatLine(s, 3)
+	if err != nil {
+		return nil, err
+	}
+	ints := true
+	for _, r := range rs {
+		ints = ints && r.IsInt() && r.Num().IsInt64()
+	}
+	if ints {
+		return dcel.NewExactVertex(geom.NewIntPoint(
+			rs[0].Num().Int64(), rs[1].Num().Int64(), rs[2].Num().Int64())), nil
+	}
+	return dcel.NewExactVertex(geom.NewRatPoint(rs[0], rs[1], rs[2])), nil
+}
+
+// read is shared by Read and ReadExact, reading each vertex
+// with vertex.
+func read(f io.Reader, vertex func(*bufio.Scanner) (*dcel.Vertex, error)) (*dcel.DCEL, error) {
 	scanner := bufio.NewScanner(f)
 
 	if scanner.Scan() {
@@ -142,11 +196,10 @@ func Read(f io.Reader) (*dcel.DCEL, error) {
 	// Each dcel.Vertex is represented as three numbers,
 	// x, y, z, in that order.
 	for i := 0; i < numVertices; i++ {
-		fs, err := readFloat64Line(scanner, 3)
+		dc.Vertices[i], err = vertex(scanner)
 		if err != nil {
 			return nil, err
 		}
-		dc.Vertices[i] = dcel.NewVertex(fs[0], fs[1], fs[2])
 	}
 
 	var vi int
@@ -277,6 +330,7 @@ func decode(dc *dcel.DCEL, edges []*dcel.Edge,
 			prev = prev.Next.Twin
 		}
 		prev.Next = edge
+		edge.Prev = prev
 	}
 	dc.HalfEdges = make([]*dcel.Edge, 0)
 	ei := 0
@@ -2,6 +2,7 @@ package off
 
 import (
 	"bufio"
+	"math/big"
 	"strconv"
 	"strings"
 
@@ -54,6 +55,29 @@ func readFloat64Line(s *bufio.Scanner, l int) ([]float64, error) {
 	return out, nil
 }
 
+func readRatLine(s *bufio.Scanner, l int) ([]*big.Rat, error) {
+	out := make([]*big.Rat, l)
+
+	if !s.Scan() {
+		return out, compgeo.TypeError{}
+	}
+
+	rats := strings.Split(s.Text(), " ")
+	if len(rats) < l {
+		return nil, compgeo.TypeError{}
+	}
+
+	for i := 0; i < l; i++ {
+		r, ok := new(big.Rat).SetString(rats[i])
+		if !ok {
+			return nil, compgeo.TypeError{}
+		}
+		out[i] = r
+	}
+
+	return out, nil
+}
+
 // The number of elements in this line is defined by the first value.
 func readIntsLineNoLength(s *bufio.Scanner) (int, []int, error) {
 	var err error
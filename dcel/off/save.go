@@ -1,11 +1,11 @@
 package off
 
 import (
+	"io"
 	"io/ioutil"
 	"strconv"
 
 	"github.com/200sc/go-compgeo/dcel"
-	"github.com/200sc/go-compgeo/printutil"
 )
 
 // Save converts a DCEL into an OFF structure.
@@ -36,6 +36,17 @@ func Save(dc *dcel.DCEL) OFF {
 // WriteFile takes an OFF structure and writes it to
 // the given relative path.
 func (of OFF) WriteFile(relPath string) error {
+	return ioutil.WriteFile(relPath, of.Bytes(), 0644)
+}
+
+// Write writes of to w in the OFF file format.
+func (of OFF) Write(w io.Writer) error {
+	_, err := w.Write(of.Bytes())
+	return err
+}
+
+// Bytes returns of in the OFF file format.
+func (of OFF) Bytes() []byte {
 	// This could be made much faster using the bufio package
 	bData := []byte("OFF\n")
 	bData = append(bData, strconv.Itoa(of.NumVertices)...)
@@ -46,7 +57,9 @@ func (of OFF) WriteFile(relPath string) error {
 	bData = append(bData, '\n')
 	for _, v := range of.Vertices {
 		for i := 0; i < 3; i++ {
-			bData = append(bData, printutil.Stringf64(v[i])...)
+			// Vertices are written exactly, so that
+			// loading a saved file gives the same DCEL.
+			bData = strconv.AppendFloat(bData, v[i], 'g', -1, 64)
 			if i != 2 {
 				bData = append(bData, ' ')
 			}
@@ -61,5 +74,5 @@ func (of OFF) WriteFile(relPath string) error {
 		}
 		bData = append(bData, '\n')
 	}
-	return ioutil.WriteFile(relPath, bData, 0644)
+	return bData
 }
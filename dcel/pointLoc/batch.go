@@ -0,0 +1,68 @@
+package pointLoc
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// BatchLocator types can point locate many points at once.
+type BatchLocator interface {
+	LocatesPoints
+	LocateAll(points []geom.D2) ([]*dcel.Face, []error)
+}
+
+// LocateAll point locates each of points on lp with a pool of
+// GOMAXPROCS workers. The face and error for points[i] are at
+// index i of the returned slices. lp must be safe for
+// concurrent queries.
+func LocateAll(lp LocatesPoints, points []geom.D2) ([]*dcel.Face, []error) {
+	return LocateAllWith(lp, points, runtime.GOMAXPROCS(0))
+}
+
+// LocateAllWith acts as LocateAll, but with the given number
+// of workers. Fewer than one worker is treated as one.
+func LocateAllWith(lp LocatesPoints, points []geom.D2, workers int) ([]*dcel.Face, []error) {
+	faces := make([]*dcel.Face, len(points))
+	errs := make([]error, len(points))
+	if len(points) == 0 {
+		return faces, errs
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(points) {
+		workers = len(points)
+	}
+	// Points are handed out in chunks, so workers spend less
+	// time waiting on the channel than locating.
+	chunk := len(points) / (workers * 8)
+	if chunk < 1 {
+		chunk = 1
+	}
+	starts := make(chan int, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for st := range starts {
+				end := st + chunk
+				if end > len(points) {
+					end = len(points)
+				}
+				for i := st; i < end; i++ {
+					faces[i], errs[i] = lp.PointLocate(points[i].X(), points[i].Y())
+				}
+			}
+		}()
+	}
+	for st := 0; st < len(points); st += chunk {
+		starts <- st
+	}
+	close(starts)
+	wg.Wait()
+	return faces, errs
+}
@@ -20,7 +20,9 @@ func PlumbLine(dc *dcel.DCEL) pointLoc.LocatesPoints {
 	return &Iterator{dc}
 }
 
-// Iterator is a simple dcel wrapper for the following pointLocate method
+// Iterator is a simple dcel wrapper for the following pointLocate method.
+// It is safe for concurrent queries, so long as the visualizer
+// is not running and its DCEL is not modified.
 type Iterator struct {
 	*dcel.DCEL
 }
@@ -45,6 +47,22 @@ func (i *Iterator) PointLocate(vs ...float64) (*dcel.Face, error) {
 	return nil, nil
 }
 
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of i's DCEL.
+func (i *Iterator) Locate(vs ...float64) (pointLoc.Location, error) {
+	if len(vs) < 2 {
+		return pointLoc.Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	return pointLoc.Resolve(p, i.Faces[1:], geom.DefaultTolerance), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (i *Iterator) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(i, points)
+}
+
 func contains(f *dcel.Face, p geom.D2) bool {
 	return f.Contains(p)
 }
@@ -0,0 +1,121 @@
+package chain
+
+import "sort"
+
+// A separator is a monotone chain of edges running from the
+// bottom of the bounding box to the top, with the regions
+// before it in order on its left and the rest on its right.
+// Separators form a balanced binary search tree by index,
+// and each edge is kept only by the highest separator in the
+// tree which holds it, with edges ordered from bottom to top.
+type separator struct {
+	edges       []int
+	left, right int
+	// cascade merges the low ends of edges with every other
+	// entry of each child's cascade, so that finding where a
+	// query lies among one separator's entries finds where it
+	// lies among its children's in a few steps.
+	cascade []entry
+}
+
+// An entry is the low end of a separator's edge, or one passed
+// up from a child. own is the index in edges of the last edge
+// starting no higher than key, and left and right the index in
+// each child's cascade of the last entry no higher than key.
+// Each is -1 if there is no such edge or entry.
+type entry struct {
+	key              [2]float64
+	own, left, right int32
+}
+
+// sepEdge is an edge as held by separators: running up from
+// low to high, and held by the separators from first to last.
+type sepEdge struct {
+	low, high   int
+	first, last int
+}
+
+// buildSeparators returns a tree of count separators over
+// edges, and its root.
+func buildSeparators(count int, edges []sepEdge, xy [][2]float64) ([]separator, int) {
+	seps := make([]separator, count)
+	var build func(lo, hi int) int
+	build = func(lo, hi int) int {
+		if lo > hi {
+			return -1
+		}
+		mid := (lo + hi) / 2
+		seps[mid].left = build(lo, mid-1)
+		seps[mid].right = build(mid+1, hi)
+		return mid
+	}
+	root := build(0, count-1)
+	for i, e := range edges {
+		if e.first > e.last {
+			continue
+		}
+		s := root
+		for s < e.first || s > e.last {
+			if e.last < s {
+				s = seps[s].left
+			} else {
+				s = seps[s].right
+			}
+		}
+		seps[s].edges = append(seps[s].edges, i)
+	}
+	for i := range seps {
+		es := seps[i].edges
+		sort.Slice(es, func(a, b int) bool {
+			return lexLess(xy[edges[es[a]].low], xy[edges[es[b]].low])
+		})
+	}
+	cascade(seps, root, edges, xy)
+	return seps, root
+}
+
+// cascade builds the cascades of s and its descendants.
+func cascade(seps []separator, s int, edges []sepEdge, xy [][2]float64) []entry {
+	if s == -1 {
+		return nil
+	}
+	sep := &seps[s]
+	left := cascade(seps, sep.left, edges, xy)
+	right := cascade(seps, sep.right, edges, xy)
+	keys := make([][2]float64, 0, len(sep.edges)+(len(left)+len(right)+1)/2)
+	for _, e := range sep.edges {
+		keys = append(keys, xy[edges[e].low])
+	}
+	for _, es := range [][]entry{left, right} {
+		for i := 0; i < len(es); i += 2 {
+			keys = append(keys, es[i].key)
+		}
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return lexLess(keys[a], keys[b])
+	})
+	sep.cascade = make([]entry, len(keys))
+	own, l, r := int32(-1), int32(-1), int32(-1)
+	for i, k := range keys {
+		for int(own)+1 < len(sep.edges) && !lexLess(k, xy[edges[sep.edges[own+1]].low]) {
+			own++
+		}
+		for int(l)+1 < len(left) && !lexLess(k, left[l+1].key) {
+			l++
+		}
+		for int(r)+1 < len(right) && !lexLess(k, right[r+1].key) {
+			r++
+		}
+		sep.cascade[i] = entry{k, own, l, r}
+	}
+	return sep.cascade
+}
+
+// seek returns the index of the last entry in c no higher
+// than q, starting from i, which must be no later than that.
+func seek(c []entry, i int32, q [2]float64) int32 {
+	for int(i)+1 < len(c) && !lexLess(q, c[i+1].key) {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,204 @@
+// chain implements point location by the chain method of
+// Lee and Preparata, with the improvements of Edelsbrunner,
+// Guibas and Stolfi: a binary search over monotone chains
+// separating the regions of a subdivision.
+
+package chain
+
+import (
+	"sort"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Locator locates points by binary search over separators:
+// chains of edges running from the bottom of a DCEL to its
+// top, each with more of the DCEL's regions on its left than
+// the last. Which face a query lies in follows from the last
+// separator it is right of.
+//
+// Separators only exist when every region of the DCEL is
+// y monotone, so the DCEL's edges are first joined, within
+// a box around them, by extra edges which split its faces
+// into monotone regions. Each edge is kept only by the first
+// separator holding it which the search visits, and the rest
+// of the separators on the search path holding that edge
+// know that it was already checked. Fractional cascading
+// finds the edge of each separator beside the query in
+// constant time after the first, so that queries take
+// O(log n) time and the Locator takes O(n) space.
+//
+// A Locator is safe for concurrent queries.
+type Locator struct {
+	dc  *dcel.DCEL
+	tol geom.Tolerance
+	pts []geom.D2
+	xy  [][2]float64
+	// min and max are corners of the box around dc.
+	min, max [2]float64
+	edges    []sepEdge
+	seps     []separator
+	root     int
+	// faces holds the DCEL face of each region, in order.
+	faces []*dcel.Face
+}
+
+// New returns a Locator over the faces of dc. Its edges must
+// not cross one another. Building takes O(n log n) time for
+// most subdivisions, but splitting faces into monotone regions
+// keeps its sweep's edges in a slice, which can take O(n^2)
+// time when many edges span the DCEL at once.
+func New(dc *dcel.DCEL) (*Locator, error) {
+	return NewWithin(dc, geom.DefaultTolerance)
+}
+
+// NewWithin acts as New, but its Locate treats query points
+// within tolerance tol of a vertex or edge as lying on it.
+func NewWithin(dc *dcel.DCEL, tol geom.Tolerance) (*Locator, error) {
+	if dc == nil || len(dc.Faces) == 0 {
+		return nil, compgeo.BadDCELError{}
+	}
+	g, err := newGraph(dc)
+	if err != nil {
+		return nil, err
+	}
+	idx, byIdx, err := g.order()
+	if err != nil {
+		return nil, err
+	}
+	fs := g.faces()
+	l := &Locator{
+		dc:    dc,
+		tol:   tol,
+		pts:   g.pts,
+		xy:    g.xy,
+		min:   g.xy[0],
+		max:   g.xy[2],
+		root:  -1,
+		faces: make([]*dcel.Face, len(byIdx)),
+	}
+	for i, r := range byIdx {
+		l.faces[i] = fs[r]
+	}
+	// Separator i has the first i+1 regions on its left, so
+	// an edge is held by every separator from the one after
+	// its left region to the one before its right region.
+	count := len(byIdx) - 1
+	for h := 0; h < len(g.from); h += 2 {
+		up := g.up(h)
+		lr, rr := g.region[up], g.region[up^1]
+		first, last := 0, count-1
+		if lr != g.outside {
+			first = idx[lr]
+		}
+		if rr != g.outside {
+			last = idx[rr] - 1
+		}
+		l.edges = append(l.edges, sepEdge{g.from[up], g.to(up), first, last})
+	}
+	if count > 0 {
+		l.seps, l.root = buildSeparators(count, l.edges, l.xy)
+	}
+	return l, nil
+}
+
+// PointLocate returns which face of l's DCEL contains the
+// query point, if any.
+func (l *Locator) PointLocate(vs ...float64) (*dcel.Face, error) {
+	if len(vs) < 2 {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	q := [2]float64{vs[0], vs[1]}
+	// The box around the DCEL is wider than it, so points on
+	// or beyond the box are outside of every face.
+	if q[0] <= l.min[0] || q[0] >= l.max[0] || q[1] <= l.min[1] || q[1] >= l.max[1] {
+		return nil, nil
+	}
+	return l.faces[l.region(q)], nil
+}
+
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of l's DCEL.
+func (l *Locator) Locate(vs ...float64) (pointLoc.Location, error) {
+	f, err := l.PointLocate(vs...)
+	if err != nil {
+		return pointLoc.Location{}, err
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	cands := []*dcel.Face{f}
+	if f != nil {
+		// Points on f's boundary may also be reported on
+		// the boundary of a neighbor.
+		for _, e := range f.Outer.EdgeChain() {
+			cands = append(cands, e.Face, e.Twin.Face)
+		}
+	} else {
+		cands = l.dc.Faces[1:]
+	}
+	return pointLoc.Resolve(p, cands, l.tol), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (l *Locator) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(l, points)
+}
+
+// region returns the index of the region containing q.
+func (l *Locator) region(q [2]float64) int {
+	hi := len(l.faces) - 1
+	if l.root == -1 {
+		return hi
+	}
+	qp := geom.D2(geom.NewPoint(q[0], q[1], 0))
+	// eLo and eHi are the last edges q was found right and
+	// left of.
+	eLo, eHi := -1, -1
+	s := l.root
+	c := l.seps[s].cascade
+	i := int32(sort.Search(len(c), func(k int) bool {
+		return lexLess(q, c[k].key)
+	}) - 1)
+	for s != -1 {
+		sep := &l.seps[s]
+		e := -1
+		if i >= 0 {
+			if own := sep.cascade[i].own; own >= 0 {
+				e = sep.edges[own]
+			}
+		}
+		var left bool
+		if e != -1 && lexLess(q, l.xy[l.edges[e].high]) {
+			left = geom.Orient2D(l.pts[l.edges[e].low], l.pts[l.edges[e].high], qp) >= 0
+		} else {
+			// s's edge beside q is held by a separator above
+			// s, so it is the last edge q was found left of,
+			// if s holds that, or else right of.
+			e = eHi
+			left = e != -1 && l.edges[e].first <= s && s <= l.edges[e].last
+			if !left {
+				e = eLo
+			}
+		}
+		next, j := sep.right, int32(-1)
+		if left {
+			hi, eHi, next = s, e, sep.left
+			if i >= 0 {
+				j = sep.cascade[i].left
+			}
+		} else {
+			eLo = e
+			if i >= 0 {
+				j = sep.cascade[i].right
+			}
+		}
+		if next != -1 {
+			i = seek(l.seps[next].cascade, j, q)
+		}
+		s = next
+	}
+	return hi
+}
@@ -0,0 +1,357 @@
+package chain
+
+import (
+	"math"
+	"sort"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A graph is the planar straight line graph of a DCEL's edges
+// inside a bounding box. Half edges come in pairs, so the twin
+// of half edge h is h^1.
+type graph struct {
+	pts []geom.D2
+	xy  [][2]float64
+	// out holds the half edges leaving each vertex.
+	out  [][]int
+	from []int
+	// face is the DCEL face left of each half edge, if it
+	// came from the DCEL and that face is not the outer face.
+	face []*dcel.Face
+	// added is whether each half edge was added to regularize
+	// the graph, and so lies within a single DCEL face.
+	added  []bool
+	next   []int
+	region []int
+	// outside is the region beyond the bounding box.
+	outside int
+	regions int
+}
+
+func (g *graph) to(h int) int {
+	return g.from[h^1]
+}
+
+func (g *graph) addVertex(x, y float64) int {
+	g.pts = append(g.pts, geom.NewPoint(x, y, 0))
+	g.xy = append(g.xy, [2]float64{x, y})
+	g.out = append(g.out, nil)
+	return len(g.pts) - 1
+}
+
+// addEdge adds an edge from u to v, returning the half
+// edge from u to v.
+func (g *graph) addEdge(u, v int, left, right *dcel.Face, added bool) int {
+	h := len(g.from)
+	g.from = append(g.from, u, v)
+	g.face = append(g.face, left, right)
+	g.added = append(g.added, added, added)
+	g.out[u] = append(g.out[u], h)
+	g.out[v] = append(g.out[v], h+1)
+	return h
+}
+
+// lexLess returns whether a is lower than b, taking points
+// with equal y values to be lower the further left they are.
+// Ordering points this way acts as rotating the plane very
+// slightly, so that no edge is horizontal.
+func lexLess(a, b [2]float64) bool {
+	return a[1] < b[1] || (a[1] == b[1] && a[0] < b[0])
+}
+
+// newGraph returns the graph of dc's edges within a box
+// around them. The first four vertices are the box's corners,
+// counterclockwise from the bottom left.
+func newGraph(dc *dcel.DCEL) (*graph, error) {
+	g := &graph{}
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, v := range dc.Vertices {
+		minX, minY = math.Min(minX, v.X()), math.Min(minY, v.Y())
+		maxX, maxY = math.Max(maxX, v.X()), math.Max(maxY, v.Y())
+	}
+	if len(dc.Vertices) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+	margin := 1 + math.Max(maxX-minX, maxY-minY)/10
+	minX, minY, maxX, maxY = minX-margin, minY-margin, maxX+margin, maxY+margin
+	corners := [4]int{
+		g.addVertex(minX, minY),
+		g.addVertex(maxX, minY),
+		g.addVertex(maxX, maxY),
+		g.addVertex(minX, maxY),
+	}
+	var outside int
+	for i, c := range corners {
+		h := g.addEdge(c, corners[(i+1)%4], nil, nil, false)
+		if i == 0 {
+			outside = h + 1
+		}
+	}
+
+	// Each face's chain is taken to run counterclockwise, with
+	// the face on its left, unless its area says otherwise.
+	left := make(map[*dcel.Edge]*dcel.Face, len(dc.HalfEdges))
+	for _, f := range dc.Faces[1:] {
+		if f.Outer == nil {
+			continue
+		}
+		chain := f.Outer.EdgeChain()
+		area := 0.0
+		for _, e := range chain {
+			if e.Twin == nil {
+				return nil, compgeo.BadEdgeError{}
+			}
+			a, b := e.Origin, e.Twin.Origin
+			area += a.X()*b.Y() - b.X()*a.Y()
+		}
+		for _, e := range chain {
+			if area < 0 {
+				e = e.Twin
+			}
+			left[e] = f
+		}
+	}
+
+	vs := make(map[*dcel.Vertex]int, len(dc.Vertices))
+	for _, v := range dc.Vertices {
+		vs[v] = g.addVertex(v.X(), v.Y())
+	}
+	seen := make(map[*dcel.Edge]bool, len(dc.HalfEdges))
+	for _, e := range dc.HalfEdges {
+		if seen[e] {
+			continue
+		}
+		if e.Twin == nil || e.Origin == nil || e.Twin.Origin == nil {
+			return nil, compgeo.BadEdgeError{}
+		}
+		seen[e], seen[e.Twin] = true, true
+		u, ok1 := vs[e.Origin]
+		v, ok2 := vs[e.Twin.Origin]
+		if !ok1 || !ok2 {
+			return nil, compgeo.BadVertexError{}
+		}
+		if g.xy[u] == g.xy[v] {
+			return nil, compgeo.BadEdgeError{}
+		}
+		g.addEdge(u, v, left[e], left[e.Twin], false)
+	}
+
+	if err := g.regularize(g.pts, g.xy); err != nil {
+		return nil, err
+	}
+	// Turning the plane half way around swaps which vertices
+	// are higher and lower, but keeps orientations the same.
+	pts := make([]geom.D2, len(g.pts))
+	xy := make([][2]float64, len(g.xy))
+	for i, p := range g.xy {
+		xy[i] = [2]float64{-p[0], -p[1]}
+		pts[i] = geom.NewPoint(-p[0], -p[1], 0)
+	}
+	if err := g.regularize(pts, xy); err != nil {
+		return nil, err
+	}
+	g.findRegions()
+	g.outside = g.region[outside]
+	return g, nil
+}
+
+// regularize adds edges so that every vertex but the highest
+// has an edge to a higher vertex, by sweeping down through the
+// vertices as located at pts and xy. Each vertex without an edge
+// up is joined to the lowest vertex seen so far between the
+// edges directly left and right of it, which is the helper of
+// the edge to its left.
+func (g *graph) regularize(pts []geom.D2, xy [][2]float64) error {
+	order := make([]int, len(pts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return lexLess(xy[order[j]], xy[order[i]])
+	})
+	// active holds the edges crossing the sweep line from left
+	// to right, as half edges running down.
+	type active struct {
+		h, helper int
+	}
+	act := []active{}
+	for n, v := range order {
+		ups, downs := 0, []int{}
+		for _, h := range g.out[v] {
+			if lexLess(xy[v], xy[g.to(h)]) {
+				ups++
+			} else {
+				downs = append(downs, h)
+			}
+		}
+		i := sort.Search(len(act), func(k int) bool {
+			h := act[k].h
+			return geom.Orient2D(pts[g.from[h]], pts[g.to(h)], pts[v]) <= 0
+		})
+		j := i
+		for j < len(act) && g.to(act[j].h) == v {
+			j++
+		}
+		act = append(act[:i], act[j:]...)
+		if ups == 0 && n > 0 {
+			if i == 0 {
+				// Only the box's corners have no edge left
+				// of them, and each has an edge up but the
+				// first.
+				return compgeo.BadDCELError{}
+			}
+			g.addEdge(v, act[i-1].helper, nil, nil, true)
+		}
+		if i > 0 {
+			act[i-1].helper = v
+		}
+		sort.Slice(downs, func(a, b int) bool {
+			return geom.Orient2D(pts[v], pts[g.to(downs[a])], pts[g.to(downs[b])]) > 0
+		})
+		ins := make([]active, len(downs))
+		for k, h := range downs {
+			ins[k] = active{h, v}
+		}
+		act = append(act[:i], append(ins, act[i:]...)...)
+	}
+	return nil
+}
+
+// findRegions sorts the half edges around each vertex by angle,
+// and walks the cycles they form to find the regions of g. Each
+// region lies left of the half edges bounding it.
+func (g *graph) findRegions() {
+	pos := make([]int, len(g.from))
+	for v, hs := range g.out {
+		p := g.pts[v]
+		half := func(h int) int {
+			d := g.xy[g.to(h)]
+			if d[1] > g.xy[v][1] || (d[1] == g.xy[v][1] && d[0] > g.xy[v][0]) {
+				return 0
+			}
+			return 1
+		}
+		sort.Slice(hs, func(a, b int) bool {
+			ha, hb := half(hs[a]), half(hs[b])
+			if ha != hb {
+				return ha < hb
+			}
+			return geom.Orient2D(p, g.pts[g.to(hs[a])], g.pts[g.to(hs[b])]) > 0
+		})
+		for i, h := range hs {
+			pos[h] = i
+		}
+	}
+	// The half edge after h in its region turns as far left
+	// as it can, leaving h's end clockwise of h's twin.
+	g.next = make([]int, len(g.from))
+	for h := range g.from {
+		hs := g.out[g.to(h)]
+		g.next[h] = hs[(pos[h^1]+len(hs)-1)%len(hs)]
+	}
+	g.region = make([]int, len(g.from))
+	for h := range g.region {
+		g.region[h] = -1
+	}
+	for h := range g.from {
+		if g.region[h] != -1 {
+			continue
+		}
+		for h2 := h; g.region[h2] == -1; h2 = g.next[h2] {
+			g.region[h2] = g.regions
+		}
+		g.regions++
+	}
+}
+
+// faces returns the DCEL face each region lies in, or nil for
+// regions outside of every inner face. Regions bounded only by
+// added edges take the face of their neighbors across them.
+func (g *graph) faces() []*dcel.Face {
+	fs := make([]*dcel.Face, g.regions)
+	known := make([]bool, g.regions)
+	queue := []int{}
+	for h, f := range g.face {
+		if r := g.region[h]; f != nil && !known[r] {
+			fs[r], known[r] = f, true
+			queue = append(queue, r)
+		}
+	}
+	across := make([][]int, g.regions)
+	for h, added := range g.added {
+		if added {
+			across[g.region[h]] = append(across[g.region[h]], g.region[h^1])
+		}
+	}
+	for len(queue) > 0 {
+		r := queue[0]
+		queue = queue[1:]
+		for _, r2 := range across[r] {
+			if !known[r2] {
+				fs[r2], known[r2] = fs[r], true
+				queue = append(queue, r2)
+			}
+		}
+	}
+	return fs
+}
+
+// order returns each region's place in an order where every
+// edge's left region comes before its right region, looking
+// up the edge, and the regions in that order. The outside
+// region is left out of both. Such an order exists because
+// every region of a regular graph is monotone.
+func (g *graph) order() ([]int, []int, error) {
+	after := make([][]int, g.regions)
+	before := make([]int, g.regions)
+	for h := 0; h < len(g.from); h += 2 {
+		l, r := g.sides(h)
+		if l == r || l == g.outside || r == g.outside {
+			continue
+		}
+		after[l] = append(after[l], r)
+		before[r]++
+	}
+	idx := make([]int, g.regions)
+	byIdx := make([]int, 0, g.regions)
+	for r := range before {
+		if before[r] == 0 && r != g.outside {
+			byIdx = append(byIdx, r)
+		}
+	}
+	for i := 0; i < len(byIdx); i++ {
+		r := byIdx[i]
+		idx[r] = i
+		for _, r2 := range after[r] {
+			before[r2]--
+			if before[r2] == 0 {
+				byIdx = append(byIdx, r2)
+			}
+		}
+	}
+	if len(byIdx) != g.regions-1 {
+		// The graph's edges cross, so it has no such order.
+		return nil, nil, compgeo.BadDCELError{}
+	}
+	idx[g.outside] = -1
+	return idx, byIdx, nil
+}
+
+// up returns whichever of h and its twin runs up.
+func (g *graph) up(h int) int {
+	if lexLess(g.xy[g.from[h]], g.xy[g.to(h)]) {
+		return h
+	}
+	return h ^ 1
+}
+
+// sides returns the regions left and right of h's edge,
+// looking up the edge.
+func (g *graph) sides(h int) (int, int) {
+	h = g.up(h)
+	return g.region[h], g.region[h^1]
+}
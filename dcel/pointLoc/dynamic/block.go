@@ -0,0 +1,197 @@
+package dynamic
+
+import (
+	"sort"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree"
+)
+
+// A seg is a non-vertical edge of the located DCEL, held
+// by its half edge which runs from left to right.
+type seg struct {
+	e    *dcel.Edge
+	l, r geom.D3
+	blk  *block
+	// i is this seg's index in blk.segs
+	i int
+}
+
+func newSeg(e *dcel.Edge) *seg {
+	s := &seg{e: e, l: e.Origin.Position(), r: e.Twin.Origin.Position()}
+	if geom.CmpVal(s.l, s.r, 0) > 0 {
+		s.e = e.Twin
+		s.l, s.r = s.r, s.l
+	}
+	return s
+}
+
+func (s *seg) vertical() bool {
+	return geom.CmpVal(s.l, s.r, 0) == 0
+}
+
+// Compare orders segs by which lies above the other over
+// the x values they share, and orders a seg against a point
+// by whether the seg lies above the point.
+func (s *seg) Compare(i interface{}) search.CompareResult {
+	switch c := i.(type) {
+	case *seg:
+		if s == c {
+			return search.Equal
+		}
+		switch verticalOrder(s, c) {
+		case -1:
+			return search.Less
+		case 1:
+			return search.Greater
+		}
+		return search.Equal
+	case geom.D2:
+		switch geom.Orient2D(s.l, s.r, c) {
+		case 1:
+			// c is to the left of s, looking right: above it
+			return search.Less
+		case -1:
+			return search.Greater
+		}
+		return search.Equal
+	}
+	return search.Invalid
+}
+
+func (s *seg) Equals(e search.Equalable) bool {
+	s2, ok := e.(*seg)
+	return ok && s == s2
+}
+
+func (s *seg) Key() search.Comparable {
+	return s
+}
+
+func (s *seg) Val() search.Equalable {
+	return s
+}
+
+// verticalOrder orders s1 against s2 as geom.VerticalOrder.
+func verticalOrder(s1, s2 *seg) int {
+	return geom.VerticalOrder(s1.l, s1.r, s2.l, s2.r)
+}
+
+// A block is a segment tree over a fixed set of segs. Its
+// slabs are the spaces between the x values of the segs'
+// endpoints when it was built. Each node of the segment tree
+// holds, in a red black tree, the segs which cross all of that
+// node's slabs but not all of its parent's, and which are
+// therefore all ordered from bottom to top. segs can be
+// removed from a block, but not added.
+type block struct {
+	xs    []float64
+	nodes []search.Dynamic
+	segs  []*seg
+}
+
+func newBlock(segs []*seg) *block {
+	b := &block{segs: segs}
+	xs := make([]float64, 0, 2*len(segs))
+	for _, s := range segs {
+		xs = append(xs, s.l.X(), s.r.X())
+	}
+	sort.Float64s(xs)
+	for _, x := range xs {
+		if len(b.xs) == 0 || b.xs[len(b.xs)-1] != x {
+			b.xs = append(b.xs, x)
+		}
+	}
+	b.nodes = make([]search.Dynamic, 4*len(b.xs))
+	for i, s := range segs {
+		s.blk = b
+		s.i = i
+		b.update(s, 0, 0, len(b.xs)-2, true)
+	}
+	return b
+}
+
+// update inserts or removes s at node n, which covers
+// the slabs from lo to hi, and at its children.
+func (b *block) update(s *seg, n, lo, hi int, insert bool) {
+	if s.l.X() <= b.xs[lo] && b.xs[hi+1] <= s.r.X() {
+		if insert {
+			if b.nodes[n] == nil {
+				b.nodes[n] = tree.New(tree.RedBlack)
+			}
+			b.nodes[n].Insert(s)
+		} else {
+			b.nodes[n].Delete(s)
+		}
+		return
+	}
+	mid := (lo + hi) / 2
+	if s.l.X() < b.xs[mid+1] {
+		b.update(s, 2*n+1, lo, mid, insert)
+	}
+	if s.r.X() > b.xs[mid+1] {
+		b.update(s, 2*n+2, mid+1, hi, insert)
+	}
+}
+
+func (b *block) remove(s *seg) {
+	b.update(s, 0, 0, len(b.xs)-2, false)
+	last := b.segs[len(b.segs)-1]
+	b.segs[s.i] = last
+	last.i = s.i
+	b.segs = b.segs[:len(b.segs)-1]
+	s.blk = nil
+}
+
+// around returns the lowest seg in b on or above p,
+// and the highest seg on or below p, if any.
+func (b *block) around(p geom.D2) (above, below *seg) {
+	x := p.X()
+	if x < b.xs[0] || x > b.xs[len(b.xs)-1] {
+		return nil, nil
+	}
+	n, lo, hi := 0, 0, len(b.xs)-2
+	for {
+		if b.nodes[n] != nil {
+			if k, _ := b.nodes[n].SearchUp(p, 0); k != nil {
+				if s := k.(*seg); s.Compare(p) != search.Less {
+					above = lower(above, s)
+				}
+			}
+			if k, _ := b.nodes[n].SearchDown(p, 0); k != nil {
+				if s := k.(*seg); s.Compare(p) != search.Greater {
+					below = higher(below, s)
+				}
+			}
+		}
+		if lo == hi {
+			return above, below
+		}
+		mid := (lo + hi) / 2
+		if x < b.xs[mid+1] {
+			n, hi = 2*n+1, mid
+		} else {
+			n, lo = 2*n+2, mid+1
+		}
+	}
+}
+
+// lower returns whichever of s1 and s2, which both cross
+// some vertical line, is lower along it. nil segs are
+// ignored.
+func lower(s1, s2 *seg) *seg {
+	if s1 == nil || (s2 != nil && verticalOrder(s2, s1) < 0) {
+		return s2
+	}
+	return s1
+}
+
+// higher acts as lower, but returns the higher seg.
+func higher(s1, s2 *seg) *seg {
+	if s1 == nil || (s2 != nil && verticalOrder(s2, s1) > 0) {
+		return s2
+	}
+	return s1
+}
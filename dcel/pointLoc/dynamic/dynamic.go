@@ -0,0 +1,282 @@
+// dynamic implements point location on a DCEL which can
+// change after the structure is built.
+
+package dynamic
+
+import (
+	"fmt"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Locator answers point location queries on a DCEL while
+// edges are added to and removed from it. It finds the edges
+// directly above and below a query point, then checks which of
+// the faces beside them contain the point.
+//
+// Edges are kept in blocks of segment trees by the logarithmic
+// method: a new edge joins a block with all of the blocks
+// smaller than the next empty size, and each block holds up to
+// twice as many edges as the last. Removals take edges out of
+// their block directly, and all blocks are rebuilt once as many
+// edges have been removed as remain. Queries take O(log^3 n)
+// time, insertions O(log^3 n) amortized time, and removals
+// O(log^2 n) amortized time.
+//
+// A Locator is safe for concurrent queries, but not for
+// queries concurrent with edits.
+type Locator struct {
+	dc     *dcel.DCEL
+	tol    geom.Tolerance
+	blocks []*block
+	// segs holds every edge in the Locator, by both of
+	// its half edges.
+	segs    map[*dcel.Edge]*seg
+	removed int
+}
+
+// New returns a Locator over the edges of dc.
+func New(dc *dcel.DCEL) (*Locator, error) {
+	return NewWithin(dc, geom.DefaultTolerance)
+}
+
+// NewWithin acts as New, but points within tol of a vertex
+// or edge are located on that vertex or edge by Locate.
+func NewWithin(dc *dcel.DCEL, tol geom.Tolerance) (*Locator, error) {
+	if dc == nil || len(dc.Faces) == 0 {
+		return nil, compgeo.BadDCELError{}
+	}
+	l := &Locator{
+		dc:   dc,
+		tol:  tol,
+		segs: make(map[*dcel.Edge]*seg),
+	}
+	for _, e := range dc.HalfEdges {
+		if e.Twin == nil {
+			return nil, compgeo.BadEdgeError{}
+		}
+		if _, ok := l.segs[e]; !ok {
+			s := newSeg(e)
+			l.segs[e] = s
+			l.segs[e.Twin] = s
+		}
+	}
+	l.rebuild()
+	return l, nil
+}
+
+// Size returns the number of edges in l.
+func (l *Locator) Size() int {
+	return len(l.segs) / 2
+}
+
+// AddEdge adds e, a half edge with a twin, to l. Adding an
+// edge l already holds does nothing.
+func (l *Locator) AddEdge(e *dcel.Edge) error {
+	if e == nil || e.Twin == nil {
+		return compgeo.BadEdgeError{}
+	}
+	if _, ok := l.segs[e]; ok {
+		return nil
+	}
+	s := newSeg(e)
+	l.segs[e] = s
+	l.segs[e.Twin] = s
+	if s.vertical() {
+		// Vertical edges are never directly above a point
+		// which is not on them, so we do not need them
+		// to find faces.
+		return nil
+	}
+	segs := []*seg{s}
+	i := 0
+	for ; i < len(l.blocks) && l.blocks[i] != nil; i++ {
+		segs = append(segs, l.blocks[i].segs...)
+		l.blocks[i] = nil
+	}
+	if i == len(l.blocks) {
+		l.blocks = append(l.blocks, nil)
+	}
+	l.blocks[i] = newBlock(segs)
+	return nil
+}
+
+// RemoveEdge removes e, either half edge of an edge in l,
+// from l.
+func (l *Locator) RemoveEdge(e *dcel.Edge) error {
+	s, ok := l.segs[e]
+	if !ok {
+		return compgeo.BadEdgeError{}
+	}
+	delete(l.segs, s.e)
+	delete(l.segs, s.e.Twin)
+	if s.blk == nil {
+		return nil
+	}
+	s.blk.remove(s)
+	l.removed++
+	if l.removed > l.Size() {
+		l.rebuild()
+	}
+	return nil
+}
+
+// rebuild replaces l's blocks with new blocks for each
+// set bit in the number of edges l holds.
+func (l *Locator) rebuild() {
+	segs := []*seg{}
+	for e, s := range l.segs {
+		if e == s.e && !s.vertical() {
+			segs = append(segs, s)
+		}
+	}
+	l.blocks = nil
+	l.removed = 0
+	for i := 0; len(segs) > 0; i++ {
+		l.blocks = append(l.blocks, nil)
+		if len(segs)&(1<<uint(i)) != 0 {
+			l.blocks[i] = newBlock(segs[:1<<uint(i)])
+			segs = segs[1<<uint(i):]
+		}
+	}
+}
+
+// around returns the lowest edge on or above p and the
+// highest edge on or below p, as segs.
+func (l *Locator) around(p geom.D2) (above, below *seg) {
+	for _, b := range l.blocks {
+		if b == nil || len(b.segs) == 0 {
+			continue
+		}
+		a, bl := b.around(p)
+		above = lower(above, a)
+		below = higher(below, bl)
+	}
+	return above, below
+}
+
+// candidates returns the inner faces beside the edges
+// directly above and below p.
+func (l *Locator) candidates(p geom.D2) []*dcel.Face {
+	above, below := l.around(p)
+	cands := []*dcel.Face{}
+	for _, s := range []*seg{above, below} {
+		if s == nil {
+			continue
+		}
+		for _, f := range []*dcel.Face{s.e.Face, s.e.Twin.Face} {
+			if f != l.dc.Faces[dcel.OUTER_FACE] {
+				cands = append(cands, f)
+			}
+		}
+	}
+	return cands
+}
+
+// PointLocate returns which face of l's DCEL contains the
+// query point, if any.
+func (l *Locator) PointLocate(vs ...float64) (*dcel.Face, error) {
+	if len(vs) < 2 {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	for _, f := range l.candidates(p) {
+		if f.Contains(p) {
+			return f, nil
+		}
+	}
+	return nil, nil
+}
+
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of l's DCEL.
+func (l *Locator) Locate(vs ...float64) (pointLoc.Location, error) {
+	if len(vs) < 2 {
+		return pointLoc.Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	return pointLoc.Resolve(p, l.candidates(p), l.tol), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (l *Locator) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(l, points)
+}
+
+// SplitEdge splits e at v in l's DCEL, as dcel.SplitEdge,
+// and updates l to match.
+func (l *Locator) SplitEdge(e *dcel.Edge, v *dcel.Vertex) (*dcel.Edge, error) {
+	if err := l.RemoveEdge(e); err != nil {
+		return nil, err
+	}
+	n, err := l.dc.SplitEdge(e, v)
+	if err != nil {
+		l.AddEdge(e)
+		return nil, err
+	}
+	l.AddEdge(e)
+	l.AddEdge(n)
+	return n, nil
+}
+
+// RemoveVertex removes v from l's DCEL, as dcel.RemoveVertex,
+// and updates l to match.
+func (l *Locator) RemoveVertex(v *dcel.Vertex) error {
+	if v == nil || v.OutEdge == nil {
+		return compgeo.BadVertexError{}
+	}
+	es := v.AllEdges()
+	if len(es) != 2 {
+		return compgeo.BadVertexError{}
+	}
+	for _, e := range es {
+		if err := l.RemoveEdge(e); err != nil {
+			return err
+		}
+	}
+	if err := l.dc.RemoveVertex(v); err != nil {
+		for _, e := range es {
+			l.AddEdge(e)
+		}
+		return err
+	}
+	// The second edge out of v is kept by RemoveVertex
+	return l.AddEdge(es[1])
+}
+
+// SplitFace connects a and b across f in l's DCEL, as
+// dcel.SplitFace, and adds the new edge to l.
+func (l *Locator) SplitFace(a, b *dcel.Vertex, f *dcel.Face) (*dcel.Edge, error) {
+	e, err := l.dc.SplitFace(a, b, f)
+	if err != nil {
+		return nil, err
+	}
+	return e, l.AddEdge(e)
+}
+
+// MergeFaces removes e from l's DCEL, as dcel.MergeFaces,
+// and from l.
+func (l *Locator) MergeFaces(e *dcel.Edge) error {
+	if err := l.RemoveEdge(e); err != nil {
+		return err
+	}
+	if err := l.dc.MergeFaces(e); err != nil {
+		l.AddEdge(e)
+		return err
+	}
+	return nil
+}
+
+func (l *Locator) String() string {
+	s := ""
+	for i, b := range l.blocks {
+		if b != nil {
+			s += fmt.Sprintf("%d: %d edges over %d slabs\n", i, len(b.segs), len(b.xs)-1)
+		}
+	}
+	return s
+}
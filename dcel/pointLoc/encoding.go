@@ -0,0 +1,181 @@
+package pointLoc
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"io"
+	"math"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+)
+
+// EncodingVersion is the version of the binary format written
+// by the Encode methods of point locators. Decoding any other
+// version fails with a compgeo.UnsupportedError.
+const EncodingVersion uint16 = 1
+
+// Byteorder is the byte order of encoded point locators.
+var Byteorder = binary.LittleEndian
+
+var magic = [4]byte{'P', 'L', 'O', 'C'}
+
+// An encoded point locator begins with a header of:
+//
+//	magic   [4]byte "PLOC"
+//	kind    [4]byte, naming the type of locator
+//	version uint16
+//	counts  [3]uint32, of the vertices, half edges, and faces
+//	        of the DCEL the locator was built from
+//	hash    uint64, a fingerprint of that DCEL
+//
+// and is followed by the locator's own encoding, in which
+// DCEL elements are written as their index in the DCEL's
+// slices, or -1 for nil.
+type header struct {
+	Magic   [4]byte
+	Kind    [4]byte
+	Version uint16
+	Counts  [3]uint32
+	Hash    uint64
+}
+
+// WriteHeader writes the header of a locator of the given
+// kind, built from dc, to w.
+func WriteHeader(w io.Writer, kind string, dc *dcel.DCEL) error {
+	h := header{
+		Magic:   magic,
+		Version: EncodingVersion,
+		Counts:  [3]uint32{uint32(len(dc.Vertices)), uint32(len(dc.HalfEdges)), uint32(len(dc.Faces))},
+		Hash:    fingerprint(dc),
+	}
+	copy(h.Kind[:], kind)
+	return binary.Write(w, Byteorder, h)
+}
+
+// ReadHeader reads the header of a locator from r, and
+// checks that it is of the given kind and version and was
+// built from dc.
+func ReadHeader(r io.Reader, kind string, dc *dcel.DCEL) error {
+	h := header{}
+	if err := binary.Read(r, Byteorder, &h); err != nil {
+		return err
+	}
+	var k [4]byte
+	copy(k[:], kind)
+	if h.Magic != magic || h.Kind != k {
+		return compgeo.TypeError{}
+	}
+	if h.Version != EncodingVersion {
+		return compgeo.UnsupportedError{}
+	}
+	if dc == nil ||
+		h.Counts != [3]uint32{uint32(len(dc.Vertices)), uint32(len(dc.HalfEdges)), uint32(len(dc.Faces))} ||
+		h.Hash != fingerprint(dc) {
+		return compgeo.BadDCELError{}
+	}
+	return nil
+}
+
+// fingerprint hashes the positions of dc's vertices and
+// how its half edges connect them and its faces.
+func fingerprint(dc *dcel.DCEL) uint64 {
+	ix := NewIndex(dc)
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+	put := func(v uint64) {
+		Byteorder.PutUint64(buf, v)
+		h.Write(buf)
+	}
+	for _, v := range dc.Vertices {
+		for i := 0; i < 3; i++ {
+			put(math.Float64bits(v.Val(i)))
+		}
+	}
+	for _, e := range dc.HalfEdges {
+		put(uint64(ix.Vertex(e.Origin)))
+		put(uint64(ix.Edge(e.Twin)))
+		put(uint64(ix.Face(e.Face)))
+	}
+	return h.Sum64()
+}
+
+// An Index finds the positions of a DCEL's elements in
+// its slices, for encoding references to them.
+type Index struct {
+	dc       *dcel.DCEL
+	vertices map[*dcel.Vertex]int32
+	edges    map[*dcel.Edge]int32
+	faces    map[*dcel.Face]int32
+}
+
+// NewIndex returns an Index over dc.
+func NewIndex(dc *dcel.DCEL) *Index {
+	ix := &Index{
+		dc:       dc,
+		vertices: make(map[*dcel.Vertex]int32, len(dc.Vertices)),
+		edges:    make(map[*dcel.Edge]int32, len(dc.HalfEdges)),
+		faces:    make(map[*dcel.Face]int32, len(dc.Faces)),
+	}
+	for i, v := range dc.Vertices {
+		ix.vertices[v] = int32(i)
+	}
+	for i, e := range dc.HalfEdges {
+		ix.edges[e] = int32(i)
+	}
+	for i, f := range dc.Faces {
+		ix.faces[f] = int32(i)
+	}
+	return ix
+}
+
+// Vertex returns the index of v, or -1 if v is not in
+// the DCEL.
+func (ix *Index) Vertex(v *dcel.Vertex) int32 {
+	if i, ok := ix.vertices[v]; ok {
+		return i
+	}
+	return -1
+}
+
+// Edge returns the index of e, or -1 if e is not in
+// the DCEL.
+func (ix *Index) Edge(e *dcel.Edge) int32 {
+	if i, ok := ix.edges[e]; ok {
+		return i
+	}
+	return -1
+}
+
+// Face returns the index of f, or -1 if f is not in
+// the DCEL.
+func (ix *Index) Face(f *dcel.Face) int32 {
+	if i, ok := ix.faces[f]; ok {
+		return i
+	}
+	return -1
+}
+
+// EdgeAt returns the half edge at index i of dc, or an
+// error if i is out of range. -1 gives a nil edge.
+func EdgeAt(dc *dcel.DCEL, i int32) (*dcel.Edge, error) {
+	if i == -1 {
+		return nil, nil
+	}
+	if i < -1 || int(i) >= len(dc.HalfEdges) {
+		return nil, compgeo.RangeError{}
+	}
+	return dc.HalfEdges[i], nil
+}
+
+// FaceAt returns the face at index i of dc, or an
+// error if i is out of range. -1 gives a nil face.
+func FaceAt(dc *dcel.DCEL, i int32) (*dcel.Face, error) {
+	if i == -1 {
+		return nil, nil
+	}
+	if i < -1 || int(i) >= len(dc.Faces) {
+		return nil, compgeo.RangeError{}
+	}
+	return dc.Faces[i], nil
+}
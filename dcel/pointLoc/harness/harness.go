@@ -0,0 +1,329 @@
+// harness cross validates point locators against one another,
+// reporting each disagreement with a minimal reproducer.
+
+package harness
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Builder builds a point locator over a DCEL.
+type Builder func(*dcel.DCEL) (pointLoc.LocatesPoints, error)
+
+// PlumbLine is a Builder for the plumb line, which checks
+// every face in turn and so is used as the oracle that
+// other locators are checked against.
+func PlumbLine(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+	return bruteForce.PlumbLine(dc), nil
+}
+
+// A Harness runs point locators over DCELs and queries,
+// checking each locator's answers against an oracle's.
+type Harness struct {
+	// Builders are the locators to check, by name.
+	Builders map[string]Builder
+	// Oracle builds the locator whose answers are taken
+	// to be correct. If nil, PlumbLine is used.
+	Oracle Builder
+	// Size and Splits are passed to Random2DDCELSeeded
+	// when Run generates DCELs. If Size is zero, 100 is
+	// used.
+	Size   float64
+	Splits int
+}
+
+// New returns a Harness checking builders against the
+// plumb line.
+func New(builders map[string]Builder) *Harness {
+	return &Harness{
+		Builders: builders,
+		Oracle:   PlumbLine,
+		Size:     100,
+		Splits:   8,
+	}
+}
+
+// A Mismatch is a query on which a locator disagrees with
+// the oracle, or a DCEL on which it failed to build.
+type Mismatch struct {
+	// Locator is the name of the disagreeing Builder.
+	Locator string
+	// DCEL is the smallest DCEL found on which the
+	// locator still disagrees.
+	DCEL *dcel.DCEL
+	// Query is the point located.
+	Query geom.D2
+	// Want and Got are the indices in DCEL.Faces of the
+	// faces the oracle and the locator returned, or -1
+	// for no face.
+	Want, Got int
+	// Err is any error the locator returned, or the
+	// panic it raised, while building or locating.
+	Err error
+	// Reproduces is whether saving DCEL as OFF and
+	// loading it again gives a DCEL with the same
+	// mismatch.
+	Reproduces bool
+}
+
+// OFF returns m's DCEL as an OFF structure.
+func (m Mismatch) OFF() off.OFF {
+	return off.Save(m.DCEL)
+}
+
+// WriteOFF writes m's DCEL to path as an OFF file.
+func (m Mismatch) WriteOFF(path string) error {
+	return m.OFF().WriteFile(path)
+}
+
+func (m Mismatch) String() string {
+	s := fmt.Sprintf("%s: ", m.Locator)
+	if m.Query == nil {
+		s += fmt.Sprintf("failed to build: %v", m.Err)
+	} else {
+		s += fmt.Sprintf("query (%v, %v): want face %d, got face %d",
+			m.Query.X(), m.Query.Y(), m.Want, m.Got)
+		if m.Err != nil {
+			s += fmt.Sprintf(", error %v", m.Err)
+		}
+	}
+	if !m.Reproduces {
+		s += " (does not reproduce from OFF)"
+	}
+	return s + "\n" + string(m.OFF().Bytes())
+}
+
+// Run checks h's locators on n DCELs and queries per DCEL
+// generated from seed, returning every mismatch found.
+func (h *Harness) Run(seed int64, n, queries int) []Mismatch {
+	rng := rand.New(rand.NewSource(seed))
+	ms := []Mismatch{}
+	for i := 0; i < n; i++ {
+		size := h.Size
+		if size == 0 {
+			size = 100
+		}
+		dc := dcel.Random2DDCELSeeded(size, h.Splits, rng.Int63())
+		ms = append(ms, h.Check(dc, Queries(dc, queries, rng))...)
+	}
+	return ms
+}
+
+// Queries returns n points drawn from rng, spread across
+// dc's bounds and a margin outside them.
+func Queries(dc *dcel.DCEL, n int, rng *rand.Rand) []geom.D2 {
+	min, max := dc.Bounds().Left(), dc.Bounds().Right()
+	w, h := max.X()-min.X(), max.Y()-min.Y()
+	pts := make([]geom.D2, n)
+	for i := range pts {
+		pts[i] = geom.NewPoint(
+			min.X()+w*(rng.Float64()*1.1-.05),
+			min.Y()+h*(rng.Float64()*1.1-.05), 0)
+	}
+	return pts
+}
+
+// Check checks h's locators on dc against queries, returning
+// at most one mismatch per locator, each shrunk to a minimal
+// reproducer. Queries on an edge or vertex of dc are skipped,
+// as locators may place them in any of the adjacent faces.
+func (h *Harness) Check(dc *dcel.DCEL, queries []geom.D2) []Mismatch {
+	names := make([]string, 0, len(h.Builders))
+	for name := range h.Builders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	oracle, err := h.oracle()(dc)
+	if err != nil {
+		return nil
+	}
+	ms := []Mismatch{}
+	for _, name := range names {
+		b := h.Builders[name]
+		lp, err := build(b, dc)
+		if err != nil {
+			ms = append(ms, Mismatch{Locator: name, DCEL: dc, Err: err,
+				Want: -1, Got: -1, Reproduces: true})
+			continue
+		}
+		for _, p := range queries {
+			if ambiguous(dc, p) {
+				continue
+			}
+			want, got, err := locate(oracle, lp, p)
+			if want == got && err == nil {
+				continue
+			}
+			ms = append(ms, h.shrink(name, b, dc, p))
+			break
+		}
+	}
+	return ms
+}
+
+func (h *Harness) oracle() Builder {
+	if h.Oracle == nil {
+		return PlumbLine
+	}
+	return h.Oracle
+}
+
+// differs returns whether b's locator over dc disagrees with
+// the oracle at p, and how.
+func (h *Harness) differs(b Builder, dc *dcel.DCEL, p geom.D2) (bool, Mismatch) {
+	m := Mismatch{DCEL: dc, Query: p, Want: -1, Got: -1}
+	if ambiguous(dc, p) {
+		return false, m
+	}
+	oracle, err := h.oracle()(dc)
+	if err != nil {
+		return false, m
+	}
+	lp, err := build(b, dc)
+	if err != nil {
+		m.Err = err
+		return true, m
+	}
+	want, got, err := locate(oracle, lp, p)
+	m.Want, m.Got, m.Err = faceIndex(dc, want), faceIndex(dc, got), err
+	return want != got || err != nil, m
+}
+
+// shrink repeatedly removes vertices and edges from dc while
+// b's locator still disagrees with the oracle at p, and returns
+// the resulting mismatch.
+func (h *Harness) shrink(name string, b Builder, dc *dcel.DCEL, p geom.D2) Mismatch {
+	_, m := h.differs(b, dc, p)
+	m.Locator = name
+	// Shrinking edits copies of dc, which need consistent
+	// face pointers for Euler operators to apply.
+	norm := normalized(dc)
+	if ok, m2 := h.differs(b, norm, p); ok {
+		m2.Locator = name
+		m = m2
+		for shrunk := true; shrunk; {
+			shrunk = false
+			for _, c := range candidates(m.DCEL) {
+				if ok, m2 := h.differs(b, c, p); ok {
+					m2.Locator = name
+					m = m2
+					shrunk = true
+					break
+				}
+			}
+		}
+	}
+	m.Reproduces = h.reproduces(b, m.DCEL, p)
+	return m
+}
+
+// reproduces returns whether dc, saved as OFF and loaded
+// again, still gives a mismatch at p.
+func (h *Harness) reproduces(b Builder, dc *dcel.DCEL, p geom.D2) bool {
+	buf := &bytes.Buffer{}
+	if err := off.Save(dc).Write(buf); err != nil {
+		return false
+	}
+	dc2, err := off.Read(buf)
+	if err != nil {
+		return false
+	}
+	ok, _ := h.differs(b, dc2, p)
+	return ok
+}
+
+// candidates returns copies of dc which each have one
+// vertex or edge fewer: with a vertex between two edges
+// joined into one, or two inner faces merged into one.
+func candidates(dc *dcel.DCEL) []*dcel.DCEL {
+	cs := []*dcel.DCEL{}
+	try := func(edit func(c *dcel.DCEL) error) {
+		c := dc.Copy()
+		if edit(c) == nil {
+			cs = append(cs, c)
+		}
+	}
+	for i := range dc.Vertices {
+		if len(dc.Vertices[i].AllEdges()) != 2 {
+			continue
+		}
+		try(func(c *dcel.DCEL) error {
+			return c.RemoveVertex(c.Vertices[i])
+		})
+	}
+	for i := 0; i+1 < len(dc.HalfEdges); i += 2 {
+		e := dc.HalfEdges[i]
+		if e.Twin == nil || e.Face == e.Twin.Face || e.Face == dc.Faces[dcel.OUTER_FACE] ||
+			e.Twin.Face == dc.Faces[dcel.OUTER_FACE] {
+			continue
+		}
+		try(func(c *dcel.DCEL) error {
+			return c.MergeFaces(c.HalfEdges[i])
+		})
+	}
+	return cs
+}
+
+// normalized returns a copy of dc in which every half edge
+// points to the face whose boundary chain it is part of.
+func normalized(dc *dcel.DCEL) *dcel.DCEL {
+	c := dc.Copy()
+	for _, f := range c.Faces {
+		for _, chain := range []*dcel.Edge{f.Outer, f.Inner} {
+			if chain == nil {
+				continue
+			}
+			for _, e := range chain.EdgeChain() {
+				e.Face = f
+			}
+		}
+	}
+	return c
+}
+
+// ambiguous returns whether p lies on an edge or vertex of dc.
+func ambiguous(dc *dcel.DCEL, p geom.D2) bool {
+	loc := pointLoc.Resolve(p, dc.Faces[1:], geom.DefaultTolerance)
+	return loc.Feature == pointLoc.OnEdge || loc.Feature == pointLoc.OnVertex
+}
+
+// build calls b, turning panics into errors.
+func build(b Builder, dc *dcel.DCEL) (lp pointLoc.LocatesPoints, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			lp, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return b(dc)
+}
+
+// locate returns the faces oracle and lp locate p in, and
+// any error or panic from lp.
+func locate(oracle, lp pointLoc.LocatesPoints, p geom.D2) (want, got *dcel.Face, err error) {
+	want, _ = oracle.PointLocate(p.X(), p.Y())
+	defer func() {
+		if r := recover(); r != nil {
+			got, err = nil, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	got, err = lp.PointLocate(p.X(), p.Y())
+	return want, got, err
+}
+
+func faceIndex(dc *dcel.DCEL, f *dcel.Face) int {
+	for i, f2 := range dc.Faces {
+		if f2 == f && f != nil {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,137 @@
+package pointLoc
+
+import (
+	"math"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Feature is the kind of DCEL feature a query point lies on.
+type Feature int
+
+// Feature constants
+const (
+	// Outside queries lie in no inner face.
+	Outside Feature = iota
+	// OnVertex queries lie on a vertex.
+	OnVertex
+	// OnEdge queries lie on an edge, but not its endpoints.
+	OnEdge
+	// InFace queries lie strictly inside a face.
+	InFace
+)
+
+func (f Feature) String() string {
+	switch f {
+	case OnVertex:
+		return "Vertex"
+	case OnEdge:
+		return "Edge"
+	case InFace:
+		return "Face"
+	}
+	return "Outside"
+}
+
+// A Location describes where a query point lies in a DCEL.
+// Face is set for every feature but Outside. For OnVertex,
+// Vertex is set, and Edge is a half edge bounding Face out
+// of Vertex. For OnEdge, Edge is the half edge bounding Face
+// which the query lies on.
+//
+// Distance is the distance from the query to Face's
+// boundary: zero on a vertex or edge, and infinite outside.
+type Location struct {
+	Feature
+	Vertex   *dcel.Vertex
+	Edge     *dcel.Edge
+	Face     *dcel.Face
+	Distance float64
+}
+
+// LocatesFeatures types can report which vertex, edge,
+// or face a query point lies on, not just which face.
+type LocatesFeatures interface {
+	LocatesPoints
+	Locate(vs ...float64) (Location, error)
+}
+
+// Resolve returns the Location of p among candidate faces
+// which may contain p or have it on their boundary, such as
+// those found by a point locator's search structure. Points
+// within tol of a vertex or edge lie on that feature, and
+// boundaries are checked before interiors, so that a query
+// on an edge shared by two candidates is always reported
+// as being on that edge. nil candidates are skipped.
+func Resolve(p geom.D2, candidates []*dcel.Face, tol geom.Tolerance) Location {
+	q := geom.NewPoint(p.X(), p.Y(), 0)
+	for _, f := range candidates {
+		if f == nil {
+			continue
+		}
+		if loc, ok := onBoundary(q, f, tol); ok {
+			return loc
+		}
+	}
+	for _, f := range candidates {
+		if f != nil && f.Contains(q) {
+			return Location{
+				Feature:  InFace,
+				Face:     f,
+				Distance: boundaryDistance(q, f),
+			}
+		}
+	}
+	return Location{Distance: math.Inf(1)}
+}
+
+// faceEdges returns the half edges of f's outer and
+// inner boundaries.
+func faceEdges(f *dcel.Face) []*dcel.Edge {
+	es := []*dcel.Edge{}
+	if f.Outer != nil {
+		es = append(es, f.Outer.EdgeChain()...)
+	}
+	if f.Inner != nil {
+		es = append(es, f.Inner.EdgeChain()...)
+	}
+	return es
+}
+
+func segment2D(e *dcel.Edge) geom.Segment {
+	return geom.NewSegment(
+		geom.NewPoint(e.Origin.X(), e.Origin.Y(), 0),
+		geom.NewPoint(e.Twin.Origin.X(), e.Twin.Origin.Y(), 0))
+}
+
+func onBoundary(q geom.Point, f *dcel.Face, tol geom.Tolerance) (Location, bool) {
+	es := faceEdges(f)
+	for _, e := range es {
+		if tol.F64eq(e.Origin.X(), q.X()) && tol.F64eq(e.Origin.Y(), q.Y()) {
+			return Location{Feature: OnVertex, Vertex: e.Origin, Edge: e, Face: f}, true
+		}
+	}
+	// Distances are compared at the magnitude of q, as a
+	// relative tolerance would otherwise find nothing near 0.
+	eps := math.Max(tol.Epsilon(q.X()), tol.Epsilon(q.Y()))
+	for _, e := range es {
+		if e.Twin != nil && segment2D(e).Distance(q) <= eps {
+			return Location{Feature: OnEdge, Edge: e, Face: f}, true
+		}
+	}
+	return Location{}, false
+}
+
+func boundaryDistance(q geom.Point, f *dcel.Face) float64 {
+	d := math.Inf(1)
+	for _, e := range faceEdges(f) {
+		if e.Twin == nil {
+			continue
+		}
+		if d2 := segment2D(e).Distance(q); d2 < d {
+			d = d2
+		}
+	}
+	return d
+}
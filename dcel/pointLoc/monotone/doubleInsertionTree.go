@@ -5,37 +5,38 @@ import (
 	"github.com/200sc/go-compgeo/dcel"
 	"github.com/200sc/go-compgeo/dcel/pointLoc"
 	"github.com/200sc/go-compgeo/geom"
-	"github.com/200sc/go-compgeo/search"
-	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/200sc/go-compgeo/search/interval"
 )
 
 // DoubleIntervalTree converts a monotonized f into a
 // structure that can be pointlocated on to determine if
 // a given point exists inside or outside the face.
-// Because this is a monotone polygon we don't actually
-// make an interval tree, we just make bsts. The intervals
-// we use will be non-overlapping except at vertices
+// Each of the polygon's two chains is stored in an interval
+// tree over the y extents of its edges. The intervals in
+// each tree will be non-overlapping except at vertices
 func NewDoubleIntervalTree(f *dcel.Face, dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
 	var e *dcel.Edge
 	for e = f.Outer; VertexType(e.Origin, dc) != START; e = e.Next {
 	}
 	// e is now the edge whose origin is the start vertex.
-	leftTree := tree.New(tree.RedBlack)
-	rightTree := tree.New(tree.RedBlack)
+	leftTree := interval.New()
+	rightTree := interval.New()
 	st := e
-	leftTree.Insert(interval{st})
+	leftTree.Insert(yInterval{st})
 	tree := leftTree
 	for e := st.Next; e != st; e = e.Next {
 		if VertexType(e.Origin, dc) == END {
 			tree = rightTree
 		}
-		tree.Insert(interval{e})
+		tree.Insert(yInterval{e})
 	}
 	return DblIntervalTree{leftTree, rightTree, f}, nil
 }
 
+// A DblIntervalTree locates points in a single monotone face.
+// It is safe for concurrent queries.
 type DblIntervalTree struct {
-	leftTree, rightTree search.Dynamic
+	leftTree, rightTree *interval.Tree
 	f                   *dcel.Face
 }
 
@@ -43,16 +44,14 @@ func (dit DblIntervalTree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
 	}
-	found, i1 := dit.leftTree.Search(yVal(vs[1]))
-	if !found {
+	e1 := stab(dit.leftTree, vs[1])
+	if e1 == nil {
 		return nil, nil
 	}
-	found2, i2 := dit.rightTree.Search(yVal(vs[1]))
-	if !found2 {
+	e2 := stab(dit.rightTree, vs[1])
+	if e2 == nil {
 		return nil, nil
 	}
-	e1 := i1.(interval).Edge
-	e2 := i2.(interval).Edge
 	pt := geom.NewPoint(vs[0], vs[1], 0)
 	// Consider-- this could probably be a direct comparison
 	// to pointAt instead of a cross product
@@ -73,67 +72,38 @@ func (dit DblIntervalTree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	return nil, nil
 }
 
-type interval struct {
-	*dcel.Edge
-}
-
-func (i interval) Key() search.Comparable {
-	return i
-}
-
-func (i interval) Val() search.Equalable {
-	return i
-}
-
-func (i interval) Equals(e search.Equalable) bool {
-	switch i2 := e.(type) {
-	case interval:
-		return i == i2
+// stab returns an edge of t whose y extent contains y, or
+// failing that one within the default tolerance of y. At a
+// vertex, either adjacent edge gives the same answer.
+func stab(t *interval.Tree, y float64) *dcel.Edge {
+	eps := geom.DefaultTolerance.Epsilon(y)
+	ivs := t.Overlap(y-eps, y+eps)
+	if len(ivs) == 0 {
+		return nil
 	}
-	return false
-}
-
-func (i interval) Compare(s interface{}) search.CompareResult {
-	switch i2 := s.(type) {
-	case interval:
-		if geom.F64eq(i.Origin.Y(), i2.Origin.Y()) {
-			if geom.F64eq(i.Twin.Origin.Y(), i2.Twin.Origin.Y()) {
-				return search.Equal
-			} else if i.Twin.Origin.Y() < i2.Twin.Origin.Y() {
-				return search.Less
-			}
-			return search.Greater
-		} else if i.Origin.Y() < i2.Origin.Y() {
-			return search.Less
+	for _, iv := range ivs {
+		if lo, hi := iv.Extent(); lo <= y && y <= hi {
+			return iv.(yInterval).Edge
 		}
-		return search.Greater
-	case yVal:
-		return i2.Compare(i)
 	}
-	return search.Invalid
+	return ivs[0].(yInterval).Edge
 }
 
-type yVal float64
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (dit DblIntervalTree) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(dit, points)
+}
 
-func (yv yVal) Compare(s interface{}) search.CompareResult {
-	switch i := s.(type) {
-	case interval:
-		y := float64(yv)
-		if geom.F64eq(y, i.Origin.Y()) ||
-			geom.F64eq(y, i.Twin.Origin.Y()) {
-			return search.Equal
-		}
-		c1 := y < i.Origin.Y()
-		c2 := y > i.Twin.Origin.Y()
-		c3 := y > i.Origin.Y()
-		c4 := y < i.Twin.Origin.Y()
-		if (c1 && c2) || (c3 && c4) {
-			return search.Equal
-		}
-		if c1 && c4 {
-			return search.Less
-		}
-		return search.Greater
+// yInterval is an edge as an interval over its y extent.
+type yInterval struct {
+	*dcel.Edge
+}
+
+func (i yInterval) Extent() (float64, float64) {
+	y1, y2 := i.Origin.Y(), i.Twin.Origin.Y()
+	if y1 > y2 {
+		return y2, y1
 	}
-	return search.Invalid
+	return y1, y2
 }
@@ -0,0 +1,200 @@
+package polyhedron
+
+import (
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// candidates is how many polygons are considered as the
+// splitting plane of each BSP node.
+const candidates = 8
+
+// A BSP locates points among cells through a solid binary
+// space partition of each cell, built by recursively splitting
+// the cell's faces by the plane of one of them. Each leaf of
+// a partition lies entirely inside or outside its cell, so a
+// query only compares against the planes on one path from the
+// root, rather than casting rays through every face. The
+// partition of a convex cell is a single path through each of
+// its faces' planes, so BSPs help most with cells which are not.
+//
+// A BSP is safe for concurrent queries.
+type BSP struct {
+	cells []*Cell
+	roots []*bspNode
+	tol   geom.Tolerance
+}
+
+// A bspNode splits space by the plane of its polygons. Space
+// in front of the plane is partitioned by front, or is outside
+// the cell if front is nil, and space behind is partitioned by
+// back, or is inside the cell if back is nil.
+type bspNode struct {
+	plane       *polygon
+	on          []*polygon
+	front, back *bspNode
+}
+
+// NewBSP returns a BSP over the cells of dc.
+func NewBSP(dc *dcel.DCEL) (*BSP, error) {
+	return NewBSPWithin(dc, geom.DefaultTolerance)
+}
+
+// NewBSPWithin acts as NewBSP, but treats queries within
+// tol of a cell's boundary as on it.
+func NewBSPWithin(dc *dcel.DCEL, tol geom.Tolerance) (*BSP, error) {
+	cells, err := Cells(dc)
+	if err != nil {
+		return nil, err
+	}
+	b := &BSP{cells: cells, tol: tol}
+	for _, c := range cells {
+		polys := make([]*polygon, len(c.polys))
+		copy(polys, c.polys)
+		b.roots = append(b.roots, buildBSP(polys, tol.Abs))
+	}
+	return b, nil
+}
+
+// Cells returns the cells b locates within.
+func (b *BSP) Cells() []*Cell {
+	return b.cells
+}
+
+// Locate returns the innermost cell containing the query
+// point, which must have three dimensions.
+func (b *BSP) Locate(vs ...float64) (Location, error) {
+	if len(vs) < 3 {
+		return Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	q := vec{vs[0], vs[1], vs[2]}
+	return innermost(b.cells, func(i int) Side {
+		if !b.cells[i].inBounds(q, b.tol.Abs) {
+			return Outside
+		}
+		return b.roots[i].side(q, b.tol.Abs)
+	}), nil
+}
+
+// Depth returns the greatest depth of any cell's partition.
+func (b *BSP) Depth() int {
+	d := 0
+	for _, n := range b.roots {
+		if d2 := n.depth(); d2 > d {
+			d = d2
+		}
+	}
+	return d
+}
+
+func (n *bspNode) depth() int {
+	if n == nil {
+		return 0
+	}
+	d := n.front.depth()
+	if d2 := n.back.depth(); d2 > d {
+		d = d2
+	}
+	return d + 1
+}
+
+func buildBSP(polys []*polygon, tol float64) *bspNode {
+	if len(polys) == 0 {
+		return nil
+	}
+	n := &bspNode{plane: chooseSplit(polys, tol)}
+	front, back := []*polygon{}, []*polygon{}
+	for _, p := range polys {
+		c := p.classify(n.plane, tol)
+		if p == n.plane {
+			// Faces which are not quite planar may not lie
+			// within tol of their own plane.
+			c = 0
+		}
+		switch c {
+		case 0:
+			n.on = append(n.on, p)
+		case 1:
+			front = append(front, p)
+		case -1:
+			back = append(back, p)
+		case 2:
+			f, b := p.split(n.plane, tol)
+			if f != nil {
+				front = append(front, f)
+			}
+			if b != nil {
+				back = append(back, b)
+			}
+		}
+	}
+	n.front = buildBSP(front, tol)
+	n.back = buildBSP(back, tol)
+	return n
+}
+
+// chooseSplit returns the polygon, among a few spread through
+// polys, whose plane splits the fewest others and divides
+// them most evenly.
+func chooseSplit(polys []*polygon, tol float64) *polygon {
+	step := len(polys) / candidates
+	if step < 1 {
+		step = 1
+	}
+	var best *polygon
+	bestCost := -1
+	for i := 0; i < len(polys); i += step {
+		s := polys[i]
+		splits, front, back := 0, 0, 0
+		for _, p := range polys {
+			switch p.classify(s, tol) {
+			case 1:
+				front++
+			case -1:
+				back++
+			case 2:
+				splits++
+			}
+		}
+		imbalance := front - back
+		if imbalance < 0 {
+			imbalance = -imbalance
+		}
+		if cost := 3*splits + imbalance; best == nil || cost < bestCost {
+			best, bestCost = s, cost
+		}
+	}
+	return best
+}
+
+// side returns where q lies relative to the cell n partitions.
+func (n *bspNode) side(q vec, tol float64) Side {
+	d := n.plane.dist(q)
+	if d > tol {
+		return n.front.sideOf(q, tol, Outside)
+	}
+	if d < -tol {
+		return n.back.sideOf(q, tol, Inside)
+	}
+	for _, p := range n.on {
+		if p.contains(q, tol) != Outside {
+			return OnBoundary
+		}
+	}
+	// q is on the plane but not on any face in it, so both
+	// sides agree on it unless it is on another face.
+	s := n.front.sideOf(q, tol, Outside)
+	if s != n.back.sideOf(q, tol, Inside) {
+		return OnBoundary
+	}
+	return s
+}
+
+// sideOf acts as side, but returns leaf for a nil n.
+func (n *bspNode) sideOf(q vec, tol float64, leaf Side) Side {
+	if n == nil {
+		return leaf
+	}
+	return n.side(q, tol)
+}
@@ -0,0 +1,214 @@
+// polyhedron implements point location among the closed
+// polyhedra of a three dimensional DCEL, such as one loaded
+// from an OFF file.
+
+package polyhedron
+
+import (
+	"math"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Cell is a closed polyhedron: one connected component
+// of the faces of a DCEL, connected through their edges.
+type Cell struct {
+	Faces  []*dcel.Face
+	polys  []*polygon
+	min    vec
+	max    vec
+	volume float64
+	convex bool
+}
+
+// Cells splits the inner faces of dc into the closed
+// polyhedra they bound. Every edge of each face must be
+// shared with another inner face, or a NotManifoldError
+// is returned.
+func Cells(dc *dcel.DCEL) ([]*Cell, error) {
+	if dc == nil || len(dc.Faces) < 2 {
+		return nil, compgeo.BadDCELError{}
+	}
+	inner := make(map[*dcel.Face]bool, len(dc.Faces))
+	for _, f := range dc.Faces[1:] {
+		if f.Outer == nil {
+			return nil, compgeo.BadDCELError{}
+		}
+		inner[f] = true
+	}
+	seen := make(map[*dcel.Face]bool, len(dc.Faces))
+	cells := []*Cell{}
+	for _, f := range dc.Faces[1:] {
+		if seen[f] {
+			continue
+		}
+		c := &Cell{}
+		seen[f] = true
+		queue := []*dcel.Face{f}
+		for len(queue) > 0 {
+			f2 := queue[0]
+			queue = queue[1:]
+			c.Faces = append(c.Faces, f2)
+			for _, e := range f2.Outer.EdgeChain() {
+				if e.Twin == nil || !inner[e.Twin.Face] {
+					return nil, compgeo.NotManifoldError{}
+				}
+				if f3 := e.Twin.Face; !seen[f3] {
+					seen[f3] = true
+					queue = append(queue, f3)
+				}
+			}
+		}
+		if err := c.init(); err != nil {
+			return nil, err
+		}
+		cells = append(cells, c)
+	}
+	return cells, nil
+}
+
+// init computes c's polygons, bounds and volume, and orients
+// its polygons' normals outward.
+func (c *Cell) init() error {
+	c.min = vec{math.Inf(1), math.Inf(1), math.Inf(1)}
+	c.max = vec{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+	for _, f := range c.Faces {
+		p, err := newPolygon(f)
+		if err != nil {
+			return err
+		}
+		c.polys = append(c.polys, p)
+		for _, v := range p.pts {
+			for i := 0; i < 3; i++ {
+				c.min[i] = math.Min(c.min[i], v[i])
+				c.max[i] = math.Max(c.max[i], v[i])
+			}
+		}
+		// Each triangle of the polygon's fan forms a
+		// tetrahedron with the origin, whose signed
+		// volumes sum to the cell's.
+		for i := 1; i+1 < len(p.pts); i++ {
+			c.volume += p.pts[0].dot(p.pts[i].cross(p.pts[i+1])) / 6
+		}
+	}
+	if c.volume < 0 {
+		// The faces wind clockwise seen from outside, so
+		// their normals point in.
+		c.volume = -c.volume
+		for _, p := range c.polys {
+			p.n = p.n.scale(-1)
+			p.d = -p.d
+		}
+	}
+	c.convex = true
+	tol := geom.DefaultTolerance.Abs
+	for _, p := range c.polys {
+		for _, p2 := range c.polys {
+			for _, v := range p2.pts {
+				if p.dist(v) > tol {
+					c.convex = false
+					return nil
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Volume returns the volume c encloses.
+func (c *Cell) Volume() float64 {
+	return c.volume
+}
+
+// Convex returns whether c is a convex polyhedron.
+func (c *Cell) Convex() bool {
+	return c.convex
+}
+
+// Side returns where p lies relative to c, treating points
+// within tol of its boundary as on it. Convex cells are
+// checked against each of their faces' planes, and others
+// by casting rays from p.
+func (c *Cell) Side(p geom.D3, tol geom.Tolerance) Side {
+	q := vec{p.X(), p.Y(), p.Z()}
+	if !c.inBounds(q, tol.Abs) {
+		return Outside
+	}
+	if c.convex {
+		s := Inside
+		for _, poly := range c.polys {
+			d := poly.dist(q)
+			if d > tol.Abs {
+				return Outside
+			}
+			if d >= -tol.Abs {
+				s = OnBoundary
+			}
+		}
+		return s
+	}
+	return rayCast(c.polys, q, tol.Abs)
+}
+
+func (c *Cell) inBounds(q vec, tol float64) bool {
+	for i := 0; i < 3; i++ {
+		if q[i] < c.min[i]-tol || q[i] > c.max[i]+tol {
+			return false
+		}
+	}
+	return true
+}
+
+// rayDirs are the directions rays are cast in, chosen to be
+// unlikely to run along the edges or faces of real meshes.
+var rayDirs = []vec{
+	vec{0.5773, 0.5774, 0.5773}.unit(),
+	vec{-0.3166, 0.7416, 0.5913}.unit(),
+	vec{0.8017, -0.2692, 0.5337}.unit(),
+	vec{-0.6172, -0.5401, -0.5722}.unit(),
+}
+
+// rayCast returns where q lies relative to the closed
+// surface of polys, by counting how many of them a ray
+// from q crosses. Rays which graze an edge of a polygon
+// are cast again in another direction.
+func rayCast(polys []*polygon, q vec, tol float64) Side {
+	for _, p := range polys {
+		if math.Abs(p.dist(q)) <= tol && p.contains(q, tol) != Outside {
+			return OnBoundary
+		}
+	}
+	side := Outside
+	for _, dir := range rayDirs {
+		crossings := 0
+		grazed := false
+		for _, p := range polys {
+			denom := p.n.dot(dir)
+			if math.Abs(denom) < 1e-12 {
+				continue
+			}
+			t := -p.dist(q) / denom
+			if t <= 0 {
+				continue
+			}
+			s := p.contains(q.add(dir.scale(t)), tol)
+			if s == OnBoundary {
+				grazed = true
+				break
+			}
+			if s == Inside {
+				crossings++
+			}
+		}
+		side = Outside
+		if crossings%2 == 1 {
+			side = Inside
+		}
+		if !grazed {
+			break
+		}
+	}
+	return side
+}
@@ -0,0 +1,90 @@
+package polyhedron
+
+import (
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Location describes where a query point lies among the
+// cells of a DCEL. Cell is the innermost cell containing the
+// query, or nil if Side is Outside, and Side is where the
+// query lies relative to Cell.
+type Location struct {
+	Side
+	Cell *Cell
+}
+
+// LocatesCells types can report which closed polyhedron
+// of a three dimensional DCEL a query point lies in.
+type LocatesCells interface {
+	Locate(vs ...float64) (Location, error)
+}
+
+// Contains returns whether the query point lies inside or
+// on the boundary of any cell that lc locates within.
+func Contains(lc LocatesCells, vs ...float64) (bool, error) {
+	loc, err := lc.Locate(vs...)
+	return loc.Side != Outside, err
+}
+
+// A RayCaster locates points among cells by checking
+// each cell in turn, by casting rays from the query
+// through the faces of cells which are not convex. It
+// needs no preprocessing beyond finding the cells, and
+// takes time linear in the number of faces per query.
+//
+// A RayCaster is safe for concurrent queries.
+type RayCaster struct {
+	cells []*Cell
+	tol   geom.Tolerance
+}
+
+// NewRayCaster returns a RayCaster over the cells of dc.
+func NewRayCaster(dc *dcel.DCEL) (*RayCaster, error) {
+	return NewRayCasterWithin(dc, geom.DefaultTolerance)
+}
+
+// NewRayCasterWithin acts as NewRayCaster, but treats
+// queries within tol of a cell's boundary as on it.
+func NewRayCasterWithin(dc *dcel.DCEL, tol geom.Tolerance) (*RayCaster, error) {
+	cells, err := Cells(dc)
+	if err != nil {
+		return nil, err
+	}
+	return &RayCaster{cells, tol}, nil
+}
+
+// Cells returns the cells rc locates within.
+func (rc *RayCaster) Cells() []*Cell {
+	return rc.cells
+}
+
+// Locate returns the innermost cell containing the query
+// point, which must have three dimensions.
+func (rc *RayCaster) Locate(vs ...float64) (Location, error) {
+	if len(vs) < 3 {
+		return Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], vs[2])
+	return innermost(rc.cells, func(i int) Side {
+		return rc.cells[i].Side(p, rc.tol)
+	}), nil
+}
+
+// innermost returns the Location of the smallest of cells
+// for whose index side does not report Outside. Cells do not
+// cross one another, so when cells nest the smallest is
+// innermost.
+func innermost(cells []*Cell, side func(int) Side) Location {
+	loc := Location{}
+	for i, c := range cells {
+		if loc.Cell != nil && c.volume >= loc.Cell.volume {
+			continue
+		}
+		if s := side(i); s != Outside {
+			loc = Location{s, c}
+		}
+	}
+	return loc
+}
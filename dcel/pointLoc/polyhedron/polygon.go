@@ -0,0 +1,206 @@
+package polyhedron
+
+import (
+	"math"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+)
+
+// A Side is where a point lies relative to a closed polyhedron.
+type Side int
+
+// Side constants
+const (
+	Outside Side = iota
+	OnBoundary
+	Inside
+)
+
+func (s Side) String() string {
+	switch s {
+	case OnBoundary:
+		return "Boundary"
+	case Inside:
+		return "Inside"
+	}
+	return "Outside"
+}
+
+type vec [3]float64
+
+func (a vec) add(b vec) vec {
+	return vec{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func (a vec) sub(b vec) vec {
+	return vec{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func (a vec) scale(f float64) vec {
+	return vec{a[0] * f, a[1] * f, a[2] * f}
+}
+
+func (a vec) dot(b vec) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func (a vec) cross(b vec) vec {
+	return vec{
+		a[1]*b[2] - a[2]*b[1],
+		a[2]*b[0] - a[0]*b[2],
+		a[0]*b[1] - a[1]*b[0],
+	}
+}
+
+func (a vec) unit() vec {
+	return a.scale(1 / math.Sqrt(a.dot(a)))
+}
+
+// A polygon is a planar face of a cell. Its plane is all
+// points x where n·x = d, with n pointing out of the cell.
+type polygon struct {
+	face *dcel.Face
+	pts  []vec
+	n    vec
+	d    float64
+	// u and v are the axes of the plane polygons are
+	// projected onto for containment checks, dropping
+	// the axis n is most aligned with.
+	u, v int
+}
+
+func newPolygon(f *dcel.Face) (*polygon, error) {
+	p := &polygon{face: f}
+	for _, e := range f.Outer.EdgeChain() {
+		o := e.Origin
+		p.pts = append(p.pts, vec{o.X(), o.Y(), o.Z()})
+	}
+	if len(p.pts) < 3 {
+		return nil, compgeo.BadDCELError{}
+	}
+	// Newell's method finds the normal of a planar polygon,
+	// following its winding, robustly even when some of its
+	// vertices are colinear.
+	for i, a := range p.pts {
+		b := p.pts[(i+1)%len(p.pts)]
+		p.n[0] += (a[1] - b[1]) * (a[2] + b[2])
+		p.n[1] += (a[2] - b[2]) * (a[0] + b[0])
+		p.n[2] += (a[0] - b[0]) * (a[1] + b[1])
+	}
+	if p.n.dot(p.n) == 0 {
+		return nil, compgeo.BadDCELError{}
+	}
+	p.n = p.n.unit()
+	p.setPlane()
+	return p, nil
+}
+
+// setPlane sets p's offset and projection axes from
+// its normal and points.
+func (p *polygon) setPlane() {
+	p.d = 0
+	for _, v := range p.pts {
+		p.d += p.n.dot(v)
+	}
+	p.d /= float64(len(p.pts))
+	drop := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(p.n[i]) > math.Abs(p.n[drop]) {
+			drop = i
+		}
+	}
+	p.u, p.v = (drop+1)%3, (drop+2)%3
+}
+
+// dist returns the signed distance of q from p's plane,
+// which is positive outside the cell.
+func (p *polygon) dist(q vec) float64 {
+	return p.n.dot(q) - p.d
+}
+
+// contains returns whether q, assumed to be on p's plane,
+// is inside p, outside it, or within tol of its boundary.
+func (p *polygon) contains(q vec, tol float64) Side {
+	qu, qv := q[p.u], q[p.v]
+	in := false
+	for i, a := range p.pts {
+		b := p.pts[(i+1)%len(p.pts)]
+		au, av, bu, bv := a[p.u], a[p.v], b[p.u], b[p.v]
+		if segDist(qu, qv, au, av, bu, bv) <= tol {
+			return OnBoundary
+		}
+		if (av > qv) != (bv > qv) &&
+			qu < au+(qv-av)*(bu-au)/(bv-av) {
+			in = !in
+		}
+	}
+	if in {
+		return Inside
+	}
+	return Outside
+}
+
+// segDist returns the distance from (x, y) to the segment
+// from (ax, ay) to (bx, by).
+func segDist(x, y, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((x-ax)*dx+(y-ay)*dy)/l))
+	}
+	return math.Hypot(x-ax-t*dx, y-ay-t*dy)
+}
+
+// split cuts p by the plane of s into the pieces in front of
+// and behind that plane, either of which may be nil. Points
+// within tol of the plane are kept in both pieces.
+func (p *polygon) split(s *polygon, tol float64) (*polygon, *polygon) {
+	front := &polygon{face: p.face, n: p.n, d: p.d, u: p.u, v: p.v}
+	back := &polygon{face: p.face, n: p.n, d: p.d, u: p.u, v: p.v}
+	for i, a := range p.pts {
+		b := p.pts[(i+1)%len(p.pts)]
+		da, db := s.dist(a), s.dist(b)
+		if da >= -tol {
+			front.pts = append(front.pts, a)
+		}
+		if da <= tol {
+			back.pts = append(back.pts, a)
+		}
+		if (da > tol && db < -tol) || (da < -tol && db > tol) {
+			c := a.add(b.sub(a).scale(da / (da - db)))
+			front.pts = append(front.pts, c)
+			back.pts = append(back.pts, c)
+		}
+	}
+	if len(front.pts) < 3 {
+		front = nil
+	}
+	if len(back.pts) < 3 {
+		back = nil
+	}
+	return front, back
+}
+
+// classify returns 1 if p is in front of s's plane, -1 if
+// behind it, 0 if on it, and 2 if p crosses it.
+func (p *polygon) classify(s *polygon, tol float64) int {
+	front, back := false, false
+	for _, v := range p.pts {
+		d := s.dist(v)
+		if d > tol {
+			front = true
+		} else if d < -tol {
+			back = true
+		}
+	}
+	switch {
+	case front && back:
+		return 2
+	case front:
+		return 1
+	case back:
+		return -1
+	}
+	return 0
+}
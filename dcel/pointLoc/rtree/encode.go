@@ -0,0 +1,77 @@
+package rtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/rtree"
+)
+
+const encodingKind = "RTRE"
+
+// Encode writes rt to w in a versioned binary format,
+// from which Decode can rebuild it without loading its
+// DCEL's faces again. After the pointLoc header and rt's
+// tolerance, the tree is written by rtree.RTree's Encode,
+// with each face written as its index in the DCEL.
+func (rt *Rtree) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := pointLoc.WriteHeader(bw, encodingKind, rt.dc); err != nil {
+		return err
+	}
+	if err := binary.Write(bw, pointLoc.Byteorder, [2]float64{rt.tol.Abs, rt.tol.Rel}); err != nil {
+		return err
+	}
+	ix := pointLoc.NewIndex(rt.dc)
+	err := rt.RTree.Encode(bw, func(s rtree.Spatial) (uint32, error) {
+		sf, ok := s.(*SpatialFace)
+		if !ok {
+			return 0, compgeo.TypeError{}
+		}
+		i := ix.Face(sf.Face)
+		if i < 0 {
+			return 0, compgeo.BadDCELError{}
+		}
+		return uint32(i), nil
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Decode reads an Rtree over dc from r, as written by
+// Encode. It fails if r was not encoded from an Rtree
+// over a DCEL identical to dc.
+func Decode(r io.Reader, dc *dcel.DCEL) (*Rtree, error) {
+	if err := pointLoc.ReadHeader(r, encodingKind, dc); err != nil {
+		return nil, err
+	}
+	var tol [2]float64
+	if err := binary.Read(r, pointLoc.Byteorder, &tol); err != nil {
+		return nil, err
+	}
+	// Each face is held by one SpatialFace, so that
+	// faces can be deleted from the result.
+	sfs := make(map[uint32]*SpatialFace)
+	tree, err := rtree.Decode(r, func(i uint32) (rtree.Spatial, error) {
+		// The outer face is never indexed
+		if i == dcel.OUTER_FACE || int(i) >= len(dc.Faces) {
+			return nil, compgeo.RangeError{}
+		}
+		if _, ok := sfs[i]; ok {
+			return nil, compgeo.TypeError{}
+		}
+		sfs[i] = &SpatialFace{dc.Faces[i]}
+		return sfs[i], nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Rtree{tree, dc, geom.NewTolerance(tol[0], tol[1])}, nil
+}
@@ -1,51 +1,77 @@
 package rtree
 
 import (
-	"fmt"
-
 	compgeo "github.com/200sc/go-compgeo"
 	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
 	"github.com/200sc/go-compgeo/geom"
-	"github.com/sythe2o0/rtreego"
+	"github.com/200sc/go-compgeo/search/rtree"
 )
 
-func DCELtoRtree(dc *dcel.DCEL) *Rtree {
-	//tree := rtreego.NewTree(20, 40)
-	tree := rtreego.NewTree(3, 9)
+// DCELtoRtree bulk loads the bounds of each of dc's
+// inner faces into an R*-tree, for point location.
+func DCELtoRtree(dc *dcel.DCEL) (*Rtree, error) {
+	return DCELtoRtreeWith(dc, rtree.DefaultMinChildren, rtree.DefaultMaxChildren)
+}
+
+// DCELtoRtreeWith acts as DCELtoRtree, with the given
+// fan-out on the underlying tree.
+func DCELtoRtreeWith(dc *dcel.DCEL, minChildren, maxChildren int) (*Rtree, error) {
+	return build(dc, minChildren, maxChildren, geom.DefaultTolerance)
+}
 
+// DCELtoRtreeWithin acts as DCELtoRtree, but Locate treats
+// queries within tol of a vertex or edge as on it.
+func DCELtoRtreeWithin(dc *dcel.DCEL, tol geom.Tolerance) (*Rtree, error) {
+	return build(dc, rtree.DefaultMinChildren, rtree.DefaultMaxChildren, tol)
+}
+
+func build(dc *dcel.DCEL, minChildren, maxChildren int, tol geom.Tolerance) (*Rtree, error) {
+	if dc == nil || len(dc.Faces) < 2 {
+		return nil, compgeo.BadDCELError{}
+	}
+	items := make([]rtree.Spatial, len(dc.Faces)-1)
 	for i := 1; i < len(dc.Faces); i++ {
-		tree.Insert(&SpatialFace{dc.Faces[i]})
+		if dc.Faces[i].Outer == nil {
+			return nil, compgeo.BadDCELError{}
+		}
+		items[i-1] = &SpatialFace{dc.Faces[i]}
 	}
-
-	return &Rtree{tree}
+	tree, err := rtree.BulkLoad(2, minChildren, maxChildren, items)
+	if err != nil {
+		return nil, err
+	}
+	return &Rtree{tree, dc, tol}, nil
 }
 
+// A SpatialFace is a face which can be stored in an R-tree.
 type SpatialFace struct {
 	*dcel.Face
 }
 
-func (sf *SpatialFace) Bounds() *rtreego.Rect {
+// Bounds returns the two dimensional bounds of sf.
+func (sf *SpatialFace) Bounds() geom.SpanN {
 	span := sf.Face.Bounds()
 	min := span.Left()
-	diff := span.Diff()
-	p := rtreego.Point{min.X(), min.Y(), min.Z()}
-	dist := [3]float64{diff.X(), diff.Y(), diff.Z()}
-	for i, v := range dist {
-		if v <= 0 {
-			dist[i] = 0.1
-		}
-	}
-	rect, err := rtreego.NewRect(p, dist)
-	if err != nil {
-		fmt.Println("bounds error:", err)
+	max := span.Right()
+	return geom.SpanN{
+		Min: geom.NewPointN(min.X(), min.Y()),
+		Max: geom.NewPointN(max.X(), max.Y()),
 	}
-	return &rect
 }
 
+// Rtree is a point locator which finds candidate faces
+// through an R*-tree over their bounds, then checks them
+// with Face.Contains. It is safe for concurrent queries,
+// but not for queries concurrent with insertions.
 type Rtree struct {
-	*rtreego.Rtree
+	*rtree.RTree
+	dc  *dcel.DCEL
+	tol geom.Tolerance
 }
 
+// PointLocate returns the face containing the given
+// x and y values, or nil if no face contains them.
 func (rt *Rtree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
@@ -54,22 +80,54 @@ func (rt *Rtree) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) > 2 {
 		pt = pt.Set(2, vs[2]).(geom.Point)
 	}
-	fs := SearchIntersect(rt.Rtree, pt)
+	fs, err := SearchIntersect(rt.RTree, pt)
+	if err != nil {
+		return nil, err
+	}
 	if len(fs) > 0 {
 		return fs[0], nil
 	}
 	return nil, nil
 }
 
-// SearchIntersect filters the output of rtree.SearchIntersect
-// on plumb line contains for the tree's faces
-func SearchIntersect(tree *rtreego.Rtree, p geom.D3) []*dcel.Face {
-	pt := rtreego.Point{p.X(), p.Y(), p.Z()}
-	rect, err := rtreego.NewRect(pt, [3]float64{0.1, 0.1, 0.1})
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of the indexed DCEL.
+func (rt *Rtree) Locate(vs ...float64) (pointLoc.Location, error) {
+	if len(vs) < 2 {
+		return pointLoc.Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	tol := rt.tol
+	// Faces which only touch the query within tolerance
+	// are candidates too.
+	ex, ey := tol.Epsilon(vs[0]), tol.Epsilon(vs[1])
+	box := geom.SpanN{
+		Min: geom.NewPointN(vs[0]-ex, vs[1]-ey),
+		Max: geom.NewPointN(vs[0]+ex, vs[1]+ey),
+	}
+	spts, err := rt.RTree.SearchIntersect(box)
+	if err != nil {
+		return pointLoc.Location{}, err
+	}
+	cands := make([]*dcel.Face, len(spts))
+	for i, s := range spts {
+		cands[i] = s.(*SpatialFace).Face
+	}
+	return pointLoc.Resolve(geom.NewPoint(vs[0], vs[1], 0), cands, tol), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (rt *Rtree) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(rt, points)
+}
+
+// SearchIntersect filters the faces whose bounds contain p
+// on whether they actually contain p.
+func SearchIntersect(tree *rtree.RTree, p geom.D3) ([]*dcel.Face, error) {
+	spts, err := tree.SearchPoint(p)
 	if err != nil {
-		fmt.Println("bounds error:", err)
+		return nil, err
 	}
-	spts := tree.SearchIntersect(&rect)
 	out := make([]*dcel.Face, 0)
 	for _, s := range spts {
 		sf := s.(*SpatialFace)
@@ -77,5 +135,5 @@ func SearchIntersect(tree *rtreego.Rtree, p geom.D3) []*dcel.Face {
 			out = append(out, sf.Face)
 		}
 	}
-	return out
+	return out, nil
 }
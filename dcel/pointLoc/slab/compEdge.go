@@ -1,8 +1,6 @@
 package slab
 
 import (
-	"fmt"
-
 	"github.com/200sc/go-compgeo/dcel"
 	"github.com/200sc/go-compgeo/dcel/pointLoc/visualize"
 	"github.com/200sc/go-compgeo/geom"
@@ -48,6 +46,7 @@ func (sn shellNode) Val() search.Equalable {
 
 type compEdge struct {
 	*dcel.Edge
+	tol geom.Tolerance
 }
 
 func (ce compEdge) Compare(i interface{}) search.CompareResult {
@@ -59,30 +58,36 @@ func (ce compEdge) Compare(i interface{}) search.CompareResult {
 			return search.Equal
 		}
 
-		if geom.F64eq(ce.X(), c.X()) && geom.F64eq(ce.Y(), c.Y()) &&
-			geom.F64eq(ce.Twin.X(), c.Twin.X()) && geom.F64eq(ce.Twin.Y(), c.Twin.Y()) {
+		if ce.tol.F64eq(ce.X(), c.X()) && ce.tol.F64eq(ce.Y(), c.Y()) &&
+			ce.tol.F64eq(ce.Twin.X(), c.Twin.X()) && ce.tol.F64eq(ce.Twin.Y(), c.Twin.Y()) {
 			return search.Equal
 		}
-		compX, err := ce.FindSharedPoint(c.Edge, 0)
-		if err != nil {
-			fmt.Println("Edges share no point on x axis")
-		}
-		p1, err := ce.PointAt(0, compX)
-		if err != nil {
-			fmt.Println(ce, " Did not have a value at ", compX)
-		}
-		p2, err := c.PointAt(0, compX)
-		if err != nil {
-			fmt.Println(c, " Did not have a value at ", compX)
-		}
-		if p1[1] < p2[1] {
+		switch verticalOrder(ce.Edge, c.Edge) {
+		case -1:
 			return search.Less
-		}
-		if p1[1] > p2[1] {
+		case 1:
 			return search.Greater
 		}
-		fmt.Println("Something went wrong")
 		return search.Greater
 	}
 	return ce.Edge.Compare(i)
 }
+
+// verticalOrder returns -1 if e1 lies below e2 over the x
+// range they share, 1 if it lies above e2, and 0 if the two
+// are colinear, as geom.VerticalOrder.
+func verticalOrder(e1, e2 *dcel.Edge) int {
+	l1, r1 := leftRight(e1)
+	l2, r2 := leftRight(e2)
+	return geom.VerticalOrder(l1, r1, l2, r2)
+}
+
+func leftRight(e *dcel.Edge) (geom.D3, geom.D3) {
+	p1 := e.Origin.Position()
+	p2 := e.Twin.Origin.Position()
+	c := geom.CmpVal(p1, p2, 0)
+	if c > 0 || (c == 0 && geom.CmpVal(p1, p2, 1) > 0) {
+		return p2, p1
+	}
+	return p1, p2
+}
@@ -0,0 +1,145 @@
+package slab
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/200sc/go-compgeo/search/tree/fullCopy"
+)
+
+const encodingKind = "SLAB"
+
+// Encode writes spl to w in a versioned binary format,
+// from which Decode can rebuild it without sweeping
+// its DCEL again. After the pointLoc header, spl is
+// written as its tolerance and outer face, then, for
+// each slab, its left boundary and the edges in its
+// tree from bottom to top, each with the faces above
+// and below it.
+func (spl *PointLocator) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if err := pointLoc.WriteHeader(bw, encodingKind, spl.dc); err != nil {
+		return err
+	}
+	ix := pointLoc.NewIndex(spl.dc)
+	write := func(v interface{}) error {
+		return binary.Write(bw, pointLoc.Byteorder, v)
+	}
+	if err := write([2]float64{spl.tol.Abs, spl.tol.Rel}); err != nil {
+		return err
+	}
+	if err := write(ix.Face(spl.outerFace)); err != nil {
+		return err
+	}
+	if err := write(uint32(len(spl.slabs))); err != nil {
+		return err
+	}
+	for _, s := range spl.slabs {
+		ns := s.tree.InOrderTraverse()
+		if err := write(s.x); err != nil {
+			return err
+		}
+		if err := write(uint32(len(ns))); err != nil {
+			return err
+		}
+		entries := make([]int32, 0, 3*len(ns))
+		for _, n := range ns {
+			fs := n.Val().(faces)
+			entries = append(entries,
+				ix.Edge(n.Key().(compEdge).Edge), ix.Face(fs.f1), ix.Face(fs.f2))
+		}
+		if err := write(entries); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// Decode reads a PointLocator over dc from r, as written
+// by Encode. It fails if r was not encoded from a locator
+// over a DCEL identical to dc. The slabs of the result
+// are red black trees, whatever they were when encoded.
+func Decode(r io.Reader, dc *dcel.DCEL) (*PointLocator, error) {
+	if err := pointLoc.ReadHeader(r, encodingKind, dc); err != nil {
+		return nil, err
+	}
+	read := func(v interface{}) error {
+		return binary.Read(r, pointLoc.Byteorder, v)
+	}
+	var tol [2]float64
+	if err := read(&tol); err != nil {
+		return nil, err
+	}
+	var outer int32
+	if err := read(&outer); err != nil {
+		return nil, err
+	}
+	outerFace, err := pointLoc.FaceAt(dc, outer)
+	if err != nil {
+		return nil, err
+	}
+	var n uint32
+	if err := read(&n); err != nil {
+		return nil, err
+	}
+	// There is at most one slab per vertex
+	if int(n) > len(dc.Vertices) {
+		return nil, compgeo.TypeError{}
+	}
+	spl := &PointLocator{
+		dc:        dc,
+		outerFace: outerFace,
+		tol:       geom.NewTolerance(tol[0], tol[1]),
+		slabs:     make([]slab, n),
+	}
+	pbst := fullCopy.NewFullPersistentBST(tree.New(tree.RedBlack)).(*fullCopy.FullPersistentBST)
+	for i := range spl.slabs {
+		var x float64
+		if err := read(&x); err != nil {
+			return nil, err
+		}
+		if i > 0 && x <= spl.slabs[i-1].x {
+			return nil, compgeo.TypeError{}
+		}
+		var m uint32
+		if err := read(&m); err != nil {
+			return nil, err
+		}
+		if int(m) > len(dc.HalfEdges) {
+			return nil, compgeo.TypeError{}
+		}
+		entries := make([]int32, 3*m)
+		if err := read(entries); err != nil {
+			return nil, err
+		}
+		t := tree.New(tree.RedBlack)
+		for j := 0; j < len(entries); j += 3 {
+			e, err := pointLoc.EdgeAt(dc, entries[j])
+			if err != nil {
+				return nil, err
+			}
+			if e == nil {
+				return nil, compgeo.BadEdgeError{}
+			}
+			f1, err := pointLoc.FaceAt(dc, entries[j+1])
+			if err != nil {
+				return nil, err
+			}
+			f2, err := pointLoc.FaceAt(dc, entries[j+2])
+			if err != nil {
+				return nil, err
+			}
+			t.Insert(shellNode{compEdge{e, spl.tol}, faces{f1, f2}})
+		}
+		pbst.AppendInstant(x, t)
+		spl.slabs[i] = slab{x, pbst.ThisInstant()}
+	}
+	spl.dp = pbst
+	return spl, nil
+}
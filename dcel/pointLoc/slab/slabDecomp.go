@@ -4,6 +4,7 @@ package slab
 
 import (
 	"fmt"
+	"sort"
 
 	compgeo "github.com/200sc/go-compgeo"
 	"github.com/200sc/go-compgeo/dcel"
@@ -19,6 +20,19 @@ import (
 // The real difficulties in Slab Decomposition are all in the
 // persistent bst itself, so this is a fairly simple function.
 func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error) {
+	return DecomposeWithin(dc, bstType, geom.DefaultTolerance)
+}
+
+// DecomposeWithin acts as Decompose, but treats vertices and
+// edges within tolerance tol of one another as equal.
+func DecomposeWithin(dc *dcel.DCEL, bstType tree.Type, tol geom.Tolerance) (pointLoc.LocatesPoints, error) {
+	return DecomposeWithStatus(dc, tree.New(bstType), tol)
+}
+
+// DecomposeWithStatus acts as DecomposeWithin, but sweeps
+// with the given empty structure as its status, such as a
+// skiplist.SkipList, instead of a binary search tree.
+func DecomposeWithStatus(dc *dcel.DCEL, status search.Persistable, tol geom.Tolerance) (pointLoc.LocatesPoints, error) {
 	if dc == nil || len(dc.Vertices) < 3 {
 		return nil, compgeo.BadDCELError{}
 	}
@@ -28,8 +42,9 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 		// applications so we don't reject that idea offhand.
 		return nil, compgeo.BadDimensionError{}
 	}
-	t := tree.New(bstType).ToPersistent()
+	t := status.ToPersistent()
 	pts := dc.VerticesSorted(0)
+	slabs := []slab{}
 
 	i := 0
 	for i < len(pts) {
@@ -40,12 +55,13 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 		visualize.DrawVerticalLine(v)
 		t.SetInstant(v.X())
 		ct := t.ThisInstant()
+		slabs = append(slabs, slab{v.X(), ct})
 
 		// Aggregate all points at this x value so we do not
 		// attempt to add edges to a tree which contains edges
 		// point to the left of v[0]
 		vs := []*dcel.Vertex{v}
-		for (i+1) < len(pts) && geom.F64eq(dc.Vertices[pts[i+1]].X(), v.X()) {
+		for (i+1) < len(pts) && tol.F64eq(dc.Vertices[pts[i+1]].X(), v.X()) {
 			i++
 			p = pts[i]
 			vs = append(vs, dc.Vertices[p])
@@ -60,20 +76,15 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 			// contains points where some points have a different
 			// dimension than others that will cause further problems,
 			// but this is too expensive to check here.
-			leftEdges, rightEdges, _, _ := v.PartitionEdges(0)
+			leftEdges, rightEdges, _, _ := v.PartitionEdgesWithin(0, tol)
 			le = append(le, leftEdges...)
 			re = append(re, rightEdges...)
 		}
-		fmt.Println("Left Edges", le)
-		fmt.Println("Right Edges", re)
 		// Remove all edges from the PersistentBST connecting to the left
 		// of the points
 		visualize.HighlightColor = visualize.RemoveColor
 		for _, e := range le {
-			fmt.Println("Removing", e.Twin)
-			err := ct.Delete(shellNode{compEdge{e.Twin}, search.Nil{}})
-			fmt.Println("Remove result", err)
-			fmt.Println(ct)
+			ct.Delete(shellNode{compEdge{e.Twin, tol}, search.Nil{}})
 		}
 		// Add all edges to the PersistentBST connecting to the right
 		// of the point
@@ -85,22 +96,33 @@ func Decompose(dc *dcel.DCEL, bstType tree.Type) (pointLoc.LocatesPoints, error)
 			// locate to the edge above the query point. Returning an
 			// edge for a query represents that the query is below
 			// the edge,
-			fmt.Println("Adding", e)
-			ct.Insert(shellNode{compEdge{e}, faces{e.Face, e.Twin.Face}})
-			fmt.Println(ct)
+			ct.Insert(shellNode{compEdge{e, tol}, faces{e.Face, e.Twin.Face}})
 		}
 
 		i++
 	}
 	visualize.HighlightColor = visualize.CheckLineColor
-	return &PointLocator{t, dc.Faces[dcel.OUTER_FACE]}, nil
+	return &PointLocator{t, dc, dc.Faces[dcel.OUTER_FACE], tol, slabs}, nil
 }
 
 // PointLocator is a construct that uses slab
 // decomposition for point location.
+//
+// Once built, a PointLocator is safe for concurrent queries,
+// so long as the visualizer is not running.
 type PointLocator struct {
 	dp        search.DynamicPersistent
+	dc        *dcel.DCEL
 	outerFace *dcel.Face
+	tol       geom.Tolerance
+	// slabs holds each slab's search tree in order of
+	// its left boundary, for sweeping through queries.
+	slabs []slab
+}
+
+type slab struct {
+	x    float64
+	tree search.Dynamic
 }
 
 func (spl *PointLocator) String() string {
@@ -113,27 +135,38 @@ func (spl *PointLocator) PointLocate(vs ...float64) (*dcel.Face, error) {
 	if len(vs) < 2 {
 		return nil, compgeo.InsufficientDimensionsError{}
 	}
-	fmt.Println("Querying", vs)
-	tree := spl.dp.AtInstant(vs[0])
-	fmt.Println("Tree found:")
-	fmt.Println(tree)
-	p := geom.Point{vs[0], vs[1], 0}
+	return spl.locateIn(spl.slabAt(vs[0]), geom.Point{vs[0], vs[1], 0}), nil
+}
 
+// slabAt returns the tree of the last slab whose left
+// boundary is at or before x, within spl's tolerance, or nil
+// if x is left of every slab.
+func (spl *PointLocator) slabAt(x float64) search.Dynamic {
+	i := sort.Search(len(spl.slabs), func(i int) bool {
+		return spl.slabs[i].x > x && !spl.tol.F64eq(spl.slabs[i].x, x)
+	})
+	if i == 0 {
+		return nil
+	}
+	return spl.slabs[i-1].tree
+}
+
+// locateIn returns the face containing p, given the
+// tree of the slab p lies in.
+func (spl *PointLocator) locateIn(tree search.Dynamic, p geom.Point) *dcel.Face {
+	if tree == nil {
+		return nil
+	}
 	e, f := tree.SearchDown(p, 0)
 	if e == nil {
-		fmt.Println("Location on empty tree")
-		return nil, nil
+		return nil
 	}
 	e2, f2 := tree.SearchUp(p, 0)
-	fmt.Println("Edges found", e, e2)
-	if geom.VerticalCompare(p, e.(compEdge)) == search.Greater {
-		fmt.Println(p, "is above edge", e)
-		return nil, nil
+	if e.(compEdge).Edge.Compare(p) == search.Greater {
+		return nil
 	}
-
-	if geom.VerticalCompare(p, e2.(compEdge)) == search.Less {
-		fmt.Println(p, "is below edge", e2)
-		return nil, nil
+	if e2.(compEdge).Edge.Compare(p) == search.Less {
+		return nil
 	}
 
 	// We then do PIP on each face, and return
@@ -144,15 +177,93 @@ func (spl *PointLocator) PointLocate(vs ...float64) (*dcel.Face, error) {
 
 	for _, f5 := range faces {
 		if f5 != spl.outerFace {
-			fmt.Println("Checking if face contains", p)
-			visualize.HighlightColor = visualize.CheckFaceColor
-			visualize.DrawFace(f5)
+			if visualize.VisualCh != nil {
+				visualize.HighlightColor = visualize.CheckFaceColor
+				visualize.DrawFace(f5)
+			}
 			if f5.Contains(p) {
-				fmt.Println("P was contained")
-				return f5, nil
+				return f5
 			}
 		}
 	}
+	return nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (spl *PointLocator) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(spl, points)
+}
+
+// LocateSorted point locates each of points in a single sweep
+// across spl's slabs, rather than searching for each point's
+// slab. It is fastest when points are sorted by x, but will
+// sort a copy of them otherwise. The face for points[i] is at
+// index i of the result.
+func (spl *PointLocator) LocateSorted(points []geom.D2) ([]*dcel.Face, []error) {
+	faces := make([]*dcel.Face, len(points))
+	errs := make([]error, len(points))
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	if !sort.SliceIsSorted(points, func(i, j int) bool {
+		return points[i].X() < points[j].X()
+	}) {
+		sort.Slice(order, func(i, j int) bool {
+			return points[order[i]].X() < points[order[j]].X()
+		})
+	}
+	j := -1
+	for _, i := range order {
+		x := points[i].X()
+		// Advance to the last slab whose left boundary is
+		// at or before x, as slabAt would find.
+		for j+1 < len(spl.slabs) &&
+			(spl.slabs[j+1].x < x || spl.tol.F64eq(spl.slabs[j+1].x, x)) {
+			j++
+		}
+		if j < 0 {
+			continue
+		}
+		faces[i] = spl.locateIn(spl.slabs[j].tree, geom.Point{x, points[i].Y(), 0})
+	}
+	return faces, errs
+}
 
-	return nil, nil
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of the decomposed DCEL.
+func (spl *PointLocator) Locate(vs ...float64) (pointLoc.Location, error) {
+	if len(vs) < 2 {
+		return pointLoc.Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.Point{vs[0], vs[1], 0}
+	// Edges ending on a slab's left boundary have already been
+	// removed from its tree, so queries on the boundary also
+	// check the slab to the left.
+	cands := spl.candidates(spl.slabAt(vs[0]), p)
+	cands = append(cands, spl.candidates(spl.slabAt(vs[0]-2*spl.tol.Epsilon(vs[0])), p)...)
+	return pointLoc.Resolve(p, cands, spl.tol), nil
+}
+
+// candidates returns the inner faces bordering the edges
+// directly above and below p in tree.
+func (spl *PointLocator) candidates(tree search.Dynamic, p geom.Point) []*dcel.Face {
+	cands := []*dcel.Face{}
+	if tree == nil {
+		return cands
+	}
+	_, f := tree.SearchDown(p, 0)
+	_, f2 := tree.SearchUp(p, 0)
+	for _, fs := range []interface{}{f, f2} {
+		if fs == nil {
+			continue
+		}
+		for _, f3 := range []*dcel.Face{fs.(faces).f1, fs.(faces).f2} {
+			if f3 != spl.outerFace {
+				cands = append(cands, f3)
+			}
+		}
+	}
+	return cands
 }
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	fullSlab "github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	fullTrapezoid "github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/walk"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomQueries(n int) []geom.D2 {
+	pts := make([]geom.D2, n)
+	for i := range pts {
+		pts[i] = geom.NewPoint(rand.Float64()*110-5, rand.Float64()*110-5, 0)
+	}
+	return pts
+}
+
+func TestLocateAll(t *testing.T) {
+	rand.Seed(11)
+	dc := dcel.Random2DDCEL(100, 4)
+	bls := make(map[string]pointLoc.BatchLocator)
+	sl, err := fullSlab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	bls["slab"] = sl.(pointLoc.BatchLocator)
+	_, _, tr, err := fullTrapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	bls["trapezoid"] = tr
+	rt, err := rtree.DCELtoRtree(dc)
+	assert.Nil(t, err)
+	bls["rtree"] = rt
+	bls["plumb line"] = bruteForce.PlumbLine(dc).(pointLoc.BatchLocator)
+	bls["walk"] = walk.NewSeeded(dc, 11)
+
+	pts := randomQueries(2000)
+	for name, bl := range bls {
+		fs, errs := bl.LocateAll(pts)
+		assert.Equal(t, len(pts), len(fs))
+		for i, p := range pts {
+			f, err := bl.PointLocate(p.X(), p.Y())
+			assert.Equal(t, err, errs[i], name)
+			assert.True(t, f == fs[i], name)
+		}
+	}
+}
+
+func TestLocateAllWith(t *testing.T) {
+	rand.Seed(12)
+	dc := dcel.Random2DDCEL(50, 2)
+	lp := bruteForce.PlumbLine(dc)
+	pts := randomQueries(100)
+	want, _ := pointLoc.LocateAllWith(lp, pts, 1)
+	for _, workers := range []int{-1, 0, 3, 64, 1000} {
+		fs, errs := pointLoc.LocateAllWith(lp, pts, workers)
+		assert.Equal(t, want, fs)
+		for _, err := range errs {
+			assert.Nil(t, err)
+		}
+	}
+	fs, errs := pointLoc.LocateAll(lp, []geom.D2{})
+	assert.Empty(t, fs)
+	assert.Empty(t, errs)
+}
+
+func TestLocateSorted(t *testing.T) {
+	rand.Seed(13)
+	dc := dcel.Random2DDCEL(100, 4)
+	lp, err := fullSlab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	// A loose tolerance moves queries just left of a slab
+	// into it.
+	lp2, err := fullSlab.DecomposeWithin(dc, tree.RedBlack, geom.NewTolerance(.01, 0))
+	assert.Nil(t, err)
+
+	pts := randomQueries(1000)
+	// Include queries at each vertex's x value, where
+	// slabs begin, and just left of it.
+	for _, v := range dc.Vertices {
+		pts = append(pts, geom.NewPoint(v.X(), rand.Float64()*100, 0))
+		pts = append(pts, geom.NewPoint(v.X()-.005, rand.Float64()*100, 0))
+	}
+	for _, lp := range []pointLoc.LocatesPoints{lp, lp2} {
+		sl := lp.(*fullSlab.PointLocator)
+		check := func(pts []geom.D2) {
+			fs, errs := sl.LocateSorted(pts)
+			for i, p := range pts {
+				f, err := sl.PointLocate(p.X(), p.Y())
+				assert.Equal(t, err, errs[i])
+				assert.True(t, f == fs[i], p)
+			}
+		}
+		check(pts)
+		sorted := append([]geom.D2{}, pts...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].X() < sorted[j].X()
+		})
+		check(sorted)
+	}
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/chain"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/harness"
+	"github.com/stretchr/testify/assert"
+)
+
+// combOFF is a comb with three teeth pointing up, a face
+// filling the notch between the right two, and a triangle
+// on its right. The comb has several vertices with no edge
+// up or down, which the chain method must join to others.
+const combOFF = `OFF
+13 3 0
+0 0 0
+10 0 0
+10 10 0
+8 10 0
+8 2 0
+6 2 0
+6 10 0
+4 10 0
+4 2 0
+2 2 0
+2 10 0
+0 10 0
+20 5 0
+12 0 1 2 3 4 5 6 7 8 9 10 11
+3 1 12 2
+4 5 4 3 6
+`
+
+func TestChainLocate(t *testing.T) {
+	comb, err := off.Read(strings.NewReader(combOFF))
+	assert.Nil(t, err)
+	dcs := []*dcel.DCEL{
+		comb,
+		dcel.Random2DDCELSeeded(100, 0, 50),
+		dcel.Random2DDCELSeeded(100, 64, 50),
+	}
+	rng := rand.New(rand.NewSource(50))
+	for _, dc := range dcs {
+		l, err := chain.New(dc)
+		assert.Nil(t, err)
+		pl := bruteForce.PlumbLine(dc)
+		for _, q := range harness.Queries(dc, 2000, rng) {
+			f, err := l.PointLocate(q.X(), q.Y())
+			assert.Nil(t, err)
+			f2, _ := pl.PointLocate(q.X(), q.Y())
+			assert.True(t, f == f2)
+		}
+	}
+
+	l, _ := chain.New(comb)
+	// In the notch between the left two teeth, which is
+	// outside of every face
+	f, _ := l.PointLocate(3, 8)
+	assert.Nil(t, f)
+	f, _ = l.PointLocate(7, 8)
+	assert.Equal(t, comb.Faces[3], f)
+	f, _ = l.PointLocate(12, 5)
+	assert.Equal(t, comb.Faces[2], f)
+	f, _ = l.PointLocate(-50, 5)
+	assert.Nil(t, f)
+	loc, err := l.Locate(8, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "Edge", loc.Feature.String())
+
+	_, err = l.PointLocate(1)
+	assert.Equal(t, compgeo.InsufficientDimensionsError{}, err)
+	_, err = chain.New(nil)
+	assert.Equal(t, compgeo.BadDCELError{}, err)
+}
@@ -0,0 +1,196 @@
+package test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/dynamic"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamicLocate(t *testing.T) {
+	rand.Seed(21)
+	dc := dcel.Random2DDCEL(100, 4)
+	dl, err := dynamic.New(dc)
+	assert.Nil(t, err)
+	pl := bruteForce.PlumbLine(dc)
+	for i := 0; i < 2000; i++ {
+		x, y := 1+rand.Float64()*98, 1+rand.Float64()*98
+		f, err := dl.PointLocate(x, y)
+		assert.Nil(t, err)
+		if assert.NotNil(t, f) {
+			assert.True(t, f.Contains(geom.NewPoint(x, y, 0)))
+		}
+		f2, _ := pl.PointLocate(x, y)
+		assert.True(t, f == f2)
+	}
+	f, err := dl.PointLocate(-10, 50)
+	assert.Nil(t, err)
+	assert.Nil(t, f)
+	_, err = dl.PointLocate(1)
+	assert.NotNil(t, err)
+	_, err = dynamic.New(nil)
+	assert.NotNil(t, err)
+}
+
+func TestDynamicChurn(t *testing.T) {
+	rand.Seed(22)
+	dc := dcel.Random2DDCEL(100, 4)
+	dl, err := dynamic.New(dc)
+	assert.Nil(t, err)
+	pl := bruteForce.PlumbLine(dc)
+	check := func() {
+		for i := 0; i < 200; i++ {
+			x, y := 1+rand.Float64()*98, 1+rand.Float64()*98
+			f, _ := dl.PointLocate(x, y)
+			f2, _ := pl.PointLocate(x, y)
+			assert.True(t, f == f2)
+		}
+	}
+	edges := []*dcel.Edge{}
+	for i := 0; i < len(dc.HalfEdges); i += 2 {
+		edges = append(edges, dc.HalfEdges[i])
+	}
+	assert.Equal(t, len(edges), dl.Size())
+	// Remove and reinsert a random half of the edges at a
+	// time, so that blocks are both merged and rebuilt.
+	for round := 0; round < 4; round++ {
+		rand.Shuffle(len(edges), func(i, j int) {
+			edges[i], edges[j] = edges[j], edges[i]
+		})
+		half := edges[:len(edges)/2]
+		for _, e := range half {
+			assert.Nil(t, dl.RemoveEdge(e))
+		}
+		assert.Equal(t, len(edges)-len(half), dl.Size())
+		for _, e := range half {
+			assert.Nil(t, dl.AddEdge(e.Twin))
+		}
+		assert.Equal(t, len(edges), dl.Size())
+		check()
+	}
+	for _, e := range edges {
+		assert.Nil(t, dl.RemoveEdge(e))
+	}
+	assert.Equal(t, 0, dl.Size())
+	f, _ := dl.PointLocate(50, 50)
+	assert.Nil(t, f)
+	assert.Equal(t, compgeo.BadEdgeError{}, dl.RemoveEdge(edges[0]))
+	for _, e := range edges {
+		assert.Nil(t, dl.AddEdge(e))
+	}
+	check()
+}
+
+func TestDynamicEuler(t *testing.T) {
+	dc := dcel.Rect(0, 0, 100, 100)
+	dl, err := dynamic.New(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, dl.Size())
+	sq := dc.Faces[1]
+	lowRight := geom.NewPoint(75, 25, 0)
+	upLeft := geom.NewPoint(25, 75, 0)
+	f, _ := dl.PointLocate(lowRight.X(), lowRight.Y())
+	assert.True(t, f == sq)
+
+	// Cut the square along its diagonal
+	v0, v2 := dc.Vertices[0], dc.Vertices[2]
+	diag, err := dl.SplitFace(v0, v2, sq)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, dl.Size())
+	assert.Equal(t, 3, len(dc.Faces))
+	assert.Equal(t, 3, len(diag.EdgeChain()))
+	assert.Equal(t, 3, len(diag.Twin.EdgeChain()))
+	f1, _ := dl.PointLocate(lowRight.X(), lowRight.Y())
+	f2, _ := dl.PointLocate(upLeft.X(), upLeft.Y())
+	assert.NotNil(t, f1)
+	assert.NotNil(t, f2)
+	assert.True(t, f1 != f2)
+	assert.True(t, f1.Contains(lowRight))
+	assert.True(t, f2.Contains(upLeft))
+	assert.Equal(t, compgeo.BadVertexError{}, dl.RemoveVertex(v0))
+
+	// Put a vertex in the middle of the diagonal
+	mid := dcel.NewVertex(50, 50, 0)
+	n, err := dl.SplitEdge(diag, mid)
+	assert.Nil(t, err)
+	assert.Equal(t, 6, dl.Size())
+	assert.True(t, n.Origin == mid)
+	assert.True(t, diag.Twin.Origin == mid)
+	loc, err := dl.Locate(50, 50)
+	assert.Nil(t, err)
+	assert.Equal(t, "Vertex", loc.Feature.String())
+	assert.True(t, loc.Vertex == mid)
+	f3, _ := dl.PointLocate(lowRight.X(), lowRight.Y())
+	assert.True(t, f3 == f1)
+	assert.Equal(t, 4, len(diag.EdgeChain()))
+
+	assert.Nil(t, dl.RemoveVertex(mid))
+	assert.Equal(t, 5, dl.Size())
+	assert.Equal(t, 4, len(dc.Vertices))
+	assert.Equal(t, 10, len(dc.HalfEdges))
+
+	// Merge the two triangles back together
+	assert.Nil(t, dl.MergeFaces(dc.Vertices[0].EdgeToward(dc.Vertices[2])))
+	assert.Equal(t, 4, dl.Size())
+	assert.Equal(t, 2, len(dc.Faces))
+	assert.Equal(t, 8, len(dc.HalfEdges))
+	f1, _ = dl.PointLocate(lowRight.X(), lowRight.Y())
+	f2, _ = dl.PointLocate(upLeft.X(), upLeft.Y())
+	assert.NotNil(t, f1)
+	assert.True(t, f1 == f2)
+	assert.Equal(t, 4, len(f1.Outer.EdgeChain()))
+}
+
+func TestDynamicEulerOFF(t *testing.T) {
+	dc, err := off.Read(strings.NewReader(wedgeOFF))
+	assert.Nil(t, err)
+	for _, e := range dc.HalfEdges {
+		if assert.NotNil(t, e.Prev) {
+			assert.True(t, e.Prev.Next == e)
+		}
+	}
+	dl, err := dynamic.New(dc)
+	assert.Nil(t, err)
+	outer := dc.Faces[dcel.OUTER_FACE]
+
+	// Split and rejoin an edge on the outer face
+	bot := dc.Vertices[0].EdgeToward(dc.Vertices[1])
+	mid := dcel.NewVertex(0, -1, 0)
+	_, err = dl.SplitEdge(bot, mid)
+	assert.Nil(t, err)
+	loc, err := dl.Locate(0, -1)
+	assert.Nil(t, err)
+	assert.True(t, loc.Vertex == mid)
+	assert.Nil(t, dl.RemoveVertex(mid))
+	f, _ := dl.PointLocate(0, -.9)
+	assert.True(t, f == dc.Faces[1])
+
+	// Merge the middle face into the bottom one, then the
+	// top face into the outer face
+	assert.Nil(t, dl.MergeFaces(dc.Vertices[7].EdgeToward(dc.Vertices[2])))
+	assert.Equal(t, 3, len(dc.Faces))
+	top, _ := dl.PointLocate(0, .9)
+	assert.NotNil(t, top)
+	assert.Nil(t, dl.MergeFaces(dc.Vertices[4].EdgeToward(dc.Vertices[5])))
+	assert.Equal(t, 2, len(dc.Faces))
+	f, _ = dl.PointLocate(0, .9)
+	assert.Nil(t, f)
+	f, _ = dl.PointLocate(0, -.9)
+	assert.True(t, f == dc.Faces[1])
+	for _, e := range dc.HalfEdges {
+		assert.True(t, e.Face != top)
+		assert.True(t, e.Next.Prev == e)
+	}
+	if assert.NotNil(t, outer.Inner) {
+		for _, e := range outer.Inner.EdgeChain() {
+			assert.True(t, e.Face == outer)
+		}
+	}
+}
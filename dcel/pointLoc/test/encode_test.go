@@ -0,0 +1,133 @@
+package test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	fullSlab "github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	fullTrapezoid "github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+type codec struct {
+	encode func(*bytes.Buffer) error
+	decode func(*bytes.Reader, *dcel.DCEL) (pointLoc.LocatesPoints, error)
+	lp     pointLoc.LocatesPoints
+}
+
+func codecs(t *testing.T, dc *dcel.DCEL) map[string]codec {
+	sl, err := fullSlab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	spl := sl.(*fullSlab.PointLocator)
+	_, _, tr, err := fullTrapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	rt, err := rtree.DCELtoRtree(dc)
+	assert.Nil(t, err)
+	return map[string]codec{
+		"slab": {
+			func(b *bytes.Buffer) error { return spl.Encode(b) },
+			func(r *bytes.Reader, dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+				return fullSlab.Decode(r, dc)
+			},
+			spl,
+		},
+		"trapezoid": {
+			func(b *bytes.Buffer) error { return tr.Encode(b) },
+			func(r *bytes.Reader, dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+				return fullTrapezoid.Decode(r, dc)
+			},
+			tr,
+		},
+		"rtree": {
+			func(b *bytes.Buffer) error { return rt.Encode(b) },
+			func(r *bytes.Reader, dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+				return rtree.Decode(r, dc)
+			},
+			rt,
+		},
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	rand.Seed(31)
+	dc := dcel.Random2DDCEL(100, 4)
+	pts := randomQueries(2000)
+	for name, c := range codecs(t, dc) {
+		b := &bytes.Buffer{}
+		assert.Nil(t, c.encode(b), name)
+		lp, err := c.decode(bytes.NewReader(b.Bytes()), dc)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+		for _, p := range pts {
+			f, err := c.lp.PointLocate(p.X(), p.Y())
+			f2, err2 := lp.PointLocate(p.X(), p.Y())
+			assert.Equal(t, err, err2, name)
+			assert.True(t, f == f2, name)
+		}
+		// Encoding the decoded locator gives the same bytes
+		b2 := &bytes.Buffer{}
+		switch v := lp.(type) {
+		case *fullSlab.PointLocator:
+			assert.Nil(t, v.Encode(b2))
+		case *fullTrapezoid.Node:
+			assert.Nil(t, v.Encode(b2))
+		case *rtree.Rtree:
+			assert.Nil(t, v.Encode(b2))
+		}
+		assert.Equal(t, b.Bytes(), b2.Bytes(), name)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	rand.Seed(32)
+	dc := dcel.Random2DDCEL(50, 2)
+	other := dcel.Random2DDCEL(50, 2)
+	for name, c := range codecs(t, dc) {
+		b := &bytes.Buffer{}
+		assert.Nil(t, c.encode(b), name)
+		data := b.Bytes()
+
+		_, err := c.decode(bytes.NewReader(data), other)
+		assert.Equal(t, compgeo.BadDCELError{}, err, name)
+		_, err = c.decode(bytes.NewReader(data), nil)
+		assert.Equal(t, compgeo.BadDCELError{}, err, name)
+
+		// Moving a single vertex invalidates the encoding
+		moved := new(dcel.DCEL)
+		*moved = *dc
+		moved.Vertices = append([]*dcel.Vertex{}, dc.Vertices...)
+		v := *moved.Vertices[0]
+		v.Point[0] += 1
+		moved.Vertices[0] = &v
+		_, err = c.decode(bytes.NewReader(data), moved)
+		assert.Equal(t, compgeo.BadDCELError{}, err, name)
+
+		bad := append([]byte{}, data...)
+		bad[8] = byte(pointLoc.EncodingVersion + 1)
+		_, err = c.decode(bytes.NewReader(bad), dc)
+		assert.Equal(t, compgeo.UnsupportedError{}, err, name)
+
+		bad = append([]byte{}, data...)
+		bad[0] = 'X'
+		_, err = c.decode(bytes.NewReader(bad), dc)
+		assert.Equal(t, compgeo.TypeError{}, err, name)
+
+		for _, n := range []int{0, 5, len(data) / 2, len(data) - 1} {
+			_, err = c.decode(bytes.NewReader(data[:n]), dc)
+			assert.NotNil(t, err, name)
+		}
+	}
+	// Locators cannot be decoded as one another
+	cs := codecs(t, dc)
+	b := &bytes.Buffer{}
+	assert.Nil(t, cs["slab"].encode(b))
+	_, err := cs["rtree"].decode(bytes.NewReader(b.Bytes()), dc)
+	assert.Equal(t, compgeo.TypeError{}, err)
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/dynamic"
+	fullSlab "github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	fullTrapezoid "github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+// wedgeOFF is a square split into three faces by two edges,
+// the upper of which runs from (-1, -1/3) to (1, 1/10).
+const wedgeOFF = `OFF
+8 3 0
+-1 -1 0
+1 -1 0
+1 -0.5 0
+1 0.1 0
+1 1 0
+-1 1 0
+-1 -0.3333333333333333 0
+-1 -0.5 0
+4 0 1 2 7
+4 7 2 3 6
+4 6 3 4 5
+`
+
+// exactWedge returns wedgeOFF read with the ends of its upper
+// inner edge at their exact positions, and read as floats.
+func exactWedge(t *testing.T) (*dcel.DCEL, *dcel.DCEL) {
+	float, err := off.Read(strings.NewReader(wedgeOFF))
+	assert.Nil(t, err)
+	exactOFF := strings.Replace(wedgeOFF, "-0.3333333333333333", "-1/3", 1)
+	exact, err := off.ReadExact(strings.NewReader(exactOFF))
+	assert.Nil(t, err)
+	assert.Equal(t, geom.NewRatPoint(big.NewRat(1, 1), big.NewRat(1, 10), new(big.Rat)), exact.Vertices[3].Exact)
+	assert.Equal(t, geom.NewRatPoint(big.NewRat(-1, 1), big.NewRat(-1, 3), new(big.Rat)), exact.Vertices[6].Exact)
+	assert.Equal(t, geom.NewIntPoint(1, 1, 0), exact.Vertices[4].Exact)
+	return exact, float
+}
+
+// bigWedgeOFF is a rectangle around y = 2^54 split in two by
+// an edge from (-1024, 2^54-1023) to (1024, 2^54+1025). Read
+// as floats, the ends of that edge round down by one.
+const bigWedgeOFF = `OFF
+6 2 0
+-1024 18014398508433408 0
+1024 18014398508433408 0
+1024 18014398509483009 0
+1024 18014398510530560 0
+-1024 18014398510530560 0
+-1024 18014398509480961 0
+4 0 1 2 5
+4 5 2 3 4
+`
+
+func TestExactLocate(t *testing.T) {
+	exact, float := exactWedge(t)
+	// q is above the upper inner edge, but below the edge
+	// between its ends' float approximations.
+	q := geom.NewPoint(0.5, -0.008333333333333331, 0)
+	assert.True(t, exact.Faces[3].Contains(q))
+	assert.True(t, float.Faces[2].Contains(q))
+
+	for seed := int64(0); seed < 32; seed++ {
+		for i, dc := range []*dcel.DCEL{exact, float} {
+			_, _, tr, err := fullTrapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, seed)
+			assert.Nil(t, err)
+			f, err := tr.PointLocate(q.X(), q.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, dc.Faces[3-i], f, "seed %d", seed)
+		}
+	}
+	for i, dc := range []*dcel.DCEL{exact, float} {
+		sl, err := fullSlab.Decompose(dc, tree.RedBlack)
+		assert.Nil(t, err)
+		f, err := sl.PointLocate(q.X(), q.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, dc.Faces[3-i], f)
+	}
+}
+
+func TestExactIntLocate(t *testing.T) {
+	exact, err := off.ReadExact(strings.NewReader(bigWedgeOFF))
+	assert.Nil(t, err)
+	for _, v := range exact.Vertices {
+		assert.IsType(t, geom.IntPoint{}, v.Exact)
+	}
+	float, err := off.Read(strings.NewReader(bigWedgeOFF))
+	assert.Nil(t, err)
+	// q is just below the exact edge, and just above its
+	// float approximation.
+	q := geom.NewPoint(-0.5, 1<<54, 0)
+	assert.True(t, exact.Faces[1].Contains(q))
+	assert.True(t, float.Faces[2].Contains(q))
+
+	for i, dc := range []*dcel.DCEL{exact, float} {
+		want := dc.Faces[1+i]
+		for seed := int64(0); seed < 32; seed++ {
+			_, _, tr, err := fullTrapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, seed)
+			assert.Nil(t, err)
+			f, err := tr.PointLocate(q.X(), q.Y())
+			assert.Nil(t, err)
+			assert.Equal(t, want, f, "seed %d", seed)
+		}
+		sl, err := fullSlab.Decompose(dc, tree.RedBlack)
+		assert.Nil(t, err)
+		f, err := sl.PointLocate(q.X(), q.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, want, f)
+		dl, err := dynamic.New(dc)
+		assert.Nil(t, err)
+		f, err = dl.PointLocate(q.X(), q.Y())
+		assert.Nil(t, err)
+		assert.Equal(t, want, f)
+	}
+}
+
+func TestReadExactMalformed(t *testing.T) {
+	for _, c := range []string{"Inf", "NaN", "1/0", "x"} {
+		_, err := off.ReadExact(strings.NewReader(strings.Replace(wedgeOFF, "-0.5", c, 1)))
+		assert.NotNil(t, err, c)
+	}
+}
@@ -0,0 +1,118 @@
+package test
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/chain"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/dynamic"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/harness"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/walk"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+var locators = map[string]harness.Builder{
+	"slab": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return slab.Decompose(dc, tree.RedBlack)
+	},
+	"trapezoid": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		_, _, tr, err := trapezoid.TrapezoidalMap(dc)
+		return tr, err
+	},
+	"chain": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return chain.New(dc)
+	},
+	"rtree": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return rtree.DCELtoRtree(dc)
+	},
+	"walk": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return walk.New(dc), nil
+	},
+	"dynamic": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+		return dynamic.New(dc)
+	},
+}
+
+func TestHarnessRun(t *testing.T) {
+	h := harness.New(locators)
+	for _, m := range h.Run(48, 20, 200) {
+		t.Error(m)
+	}
+}
+
+// rightBlind locates points left of x = 50 correctly,
+// and everything else in no face.
+type rightBlind struct {
+	pointLoc.LocatesPoints
+}
+
+func (rb rightBlind) PointLocate(vs ...float64) (*dcel.Face, error) {
+	if vs[0] > 50 {
+		return nil, nil
+	}
+	return rb.LocatesPoints.PointLocate(vs...)
+}
+
+func TestHarnessShrink(t *testing.T) {
+	h := harness.New(map[string]harness.Builder{
+		"blind": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+			return rightBlind{bruteForce.PlumbLine(dc)}, nil
+		},
+		"panics": func(dc *dcel.DCEL) (pointLoc.LocatesPoints, error) {
+			panic("build")
+		},
+	})
+	dc := dcel.Random2DDCELSeeded(100, 8, 48)
+	ms := h.Check(dc, []geom.D2{
+		geom.NewPoint(25, 50, 0),
+		geom.NewPoint(75, 50, 0),
+	})
+	if !assert.Equal(t, 2, len(ms)) {
+		return
+	}
+	m := ms[0]
+	assert.Equal(t, "blind", m.Locator)
+	assert.Equal(t, geom.NewPoint(75, 50, 0), m.Query)
+	assert.Equal(t, 1, m.Want)
+	assert.Equal(t, -1, m.Got)
+	assert.Nil(t, m.Err)
+	assert.True(t, m.Reproduces)
+	// Every inner face merges into one, which keeps no more
+	// vertices than the four corners of dc.
+	assert.Equal(t, 2, len(m.DCEL.Faces))
+	assert.True(t, len(m.DCEL.Vertices) <= 4)
+	assert.True(t, strings.Contains(m.String(), "OFF"))
+
+	assert.Equal(t, "panics", ms[1].Locator)
+	assert.NotNil(t, ms[1].Err)
+	assert.True(t, strings.Contains(ms[1].String(), "failed to build"))
+}
+
+func FuzzLocators(f *testing.F) {
+	f.Add(int64(1), uint8(4), 50.0, 50.0)
+	f.Add(int64(2), uint8(12), 10.0, 90.0)
+	f.Add(int64(3), uint8(0), 100.0, 0.0)
+	f.Add(int64(4), uint8(16), -5.0, 33.3)
+	h := harness.New(locators)
+	f.Fuzz(func(t *testing.T, seed int64, splits uint8, x, y float64) {
+		if math.IsNaN(x) || math.IsNaN(y) || math.IsInf(x, 0) || math.IsInf(y, 0) {
+			t.Skip()
+		}
+		dc := dcel.Random2DDCELSeeded(100, int(splits%32), seed)
+		qs := append(harness.Queries(dc, 8, rand.New(rand.NewSource(seed))),
+			geom.NewPoint(x, y, 0))
+		for _, m := range h.Check(dc, qs) {
+			t.Error(m)
+		}
+	})
+}
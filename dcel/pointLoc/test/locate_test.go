@@ -0,0 +1,143 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/chain"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	fullSlab "github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	fullTrapezoid "github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/walk"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func featureLocators(t *testing.T, dc *dcel.DCEL) map[string]pointLoc.LocatesFeatures {
+	lfs := make(map[string]pointLoc.LocatesFeatures)
+	sl, err := fullSlab.Decompose(dc, tree.RedBlack)
+	assert.Nil(t, err)
+	lfs["slab"] = sl.(pointLoc.LocatesFeatures)
+	_, _, tr, err := fullTrapezoid.TrapezoidalMap(dc)
+	assert.Nil(t, err)
+	lfs["trapezoid"] = tr
+	rt, err := rtree.DCELtoRtree(dc)
+	assert.Nil(t, err)
+	lfs["rtree"] = rt
+	lfs["plumb line"] = bruteForce.PlumbLine(dc).(pointLoc.LocatesFeatures)
+	lfs["walk"] = walk.New(dc)
+	return lfs
+}
+
+func TestLocateFeatures(t *testing.T) {
+	rand.Seed(7)
+	dc := dcel.Random2DDCEL(100, 4)
+	for name, lf := range featureLocators(t, dc) {
+		for _, v := range dc.Vertices {
+			loc, err := lf.Locate(v.X(), v.Y())
+			assert.Nil(t, err)
+			if assert.Equal(t, pointLoc.OnVertex, loc.Feature, name) {
+				assert.True(t, loc.Vertex.Eq(v))
+				assert.Equal(t, 0.0, loc.Distance)
+			}
+		}
+		for _, e := range dc.HalfEdges {
+			mid, err := e.Mid2D()
+			assert.Nil(t, err)
+			loc, err := lf.Locate(mid.X(), mid.Y())
+			assert.Nil(t, err)
+			if assert.Equal(t, pointLoc.OnEdge, loc.Feature, name) {
+				assert.True(t, loc.Edge == e || loc.Edge == e.Twin, name)
+			}
+		}
+		loc, err := lf.Locate(-10, 50)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.Outside, loc.Feature, name)
+		assert.Nil(t, loc.Face)
+		for i := 0; i < 100; i++ {
+			x, y := 1+rand.Float64()*98, 1+rand.Float64()*98
+			loc, err := lf.Locate(x, y)
+			assert.Nil(t, err)
+			if assert.Equal(t, pointLoc.InFace, loc.Feature, name) {
+				assert.True(t, loc.Face.Contains(geom.NewPoint(x, y, 0)))
+				assert.True(t, loc.Distance > 0)
+			}
+		}
+		_, err = lf.Locate(1)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestRtreeLocateWithin(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	rt, err := rtree.DCELtoRtree(dc)
+	assert.Nil(t, err)
+	loose, err := rtree.DCELtoRtreeWithin(dc, geom.NewTolerance(.5, 0))
+	assert.Nil(t, err)
+	loc, err := rt.Locate(5, 9.8)
+	assert.Nil(t, err)
+	assert.Equal(t, pointLoc.InFace, loc.Feature)
+	loc, err = loose.Locate(5, 9.8)
+	assert.Nil(t, err)
+	assert.Equal(t, pointLoc.OnEdge, loc.Feature)
+	// Queries just outside the face are on its edge too
+	loc, err = loose.Locate(5, 10.2)
+	assert.Nil(t, err)
+	assert.Equal(t, pointLoc.OnEdge, loc.Feature)
+	assert.Equal(t, dc.Faces[1], loc.Face)
+}
+
+func TestLocateRelativeTolerance(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	// With no absolute tolerance, queries on the right edge of
+	// the rectangle still need to look left of it.
+	tol := geom.NewTolerance(0, 1e-9)
+	lfs := make(map[string]pointLoc.LocatesFeatures)
+	sl, err := fullSlab.DecomposeWithin(dc, tree.RedBlack, tol)
+	assert.Nil(t, err)
+	lfs["slab"] = sl.(pointLoc.LocatesFeatures)
+	_, _, tr, err := fullTrapezoid.TrapezoidalMapSeeded(dc, tol, 1)
+	assert.Nil(t, err)
+	lfs["trapezoid"] = tr
+	rt, err := rtree.DCELtoRtreeWithin(dc, tol)
+	assert.Nil(t, err)
+	lfs["rtree"] = rt
+	for name, lf := range lfs {
+		loc, err := lf.Locate(10, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.OnEdge, loc.Feature, name)
+		assert.Equal(t, dc.Faces[1], loc.Face, name)
+		loc, err = lf.Locate(10, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.OnVertex, loc.Feature, name)
+		loc, err = lf.Locate(10+1e-9, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.OnEdge, loc.Feature, name)
+	}
+}
+
+func TestLocateWithin(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	tol := geom.NewTolerance(.5, 0)
+	cl, err := chain.NewWithin(dc, tol)
+	assert.Nil(t, err)
+	lfs := map[string]pointLoc.LocatesFeatures{
+		"walk":  walk.NewWithin(dc, tol),
+		"chain": cl,
+	}
+	for name, lf := range lfs {
+		loc, err := lf.Locate(5, 9.8)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.OnEdge, loc.Feature, name)
+		loc, err = lf.Locate(9.8, 9.8)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.OnVertex, loc.Feature, name)
+		loc, err = lf.Locate(5, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, pointLoc.InFace, loc.Feature, name)
+	}
+}
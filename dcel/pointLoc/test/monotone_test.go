@@ -0,0 +1,26 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/monotone"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoubleIntervalTree(t *testing.T) {
+	dc := dcel.Rect(0, 0, 10, 10)
+	f := dc.Faces[1]
+	dit, err := monotone.NewDoubleIntervalTree(f, dc)
+	assert.Nil(t, err)
+	for _, q := range [][2]float64{{5, 5}, {5, 10}, {5, 0}, {5, 10 + 1e-9}, {5, -1e-9}} {
+		f2, err := dit.PointLocate(q[0], q[1])
+		assert.Nil(t, err)
+		assert.True(t, f == f2, "%v", q)
+	}
+	for _, q := range [][2]float64{{5, 10.1}, {5, -.1}, {-1, 5}, {11, 5}} {
+		f2, err := dit.PointLocate(q[0], q[1])
+		assert.Nil(t, err)
+		assert.Nil(t, f2, "%v", q)
+	}
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"strconv"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/200sc/go-compgeo/dcel/pointLoc/bench/slab"
 	"github.com/200sc/go-compgeo/dcel/pointLoc/bench/trapezoid"
 	"github.com/200sc/go-compgeo/dcel/pointLoc/rtree"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/walk"
 	"github.com/200sc/go-compgeo/geom"
 	"github.com/200sc/go-compgeo/search/tree"
 	"github.com/stretchr/testify/assert"
@@ -27,6 +29,7 @@ var (
 	trapErrors  = 0
 	rtreeErrors = 0
 	plumbErrors = 0
+	walkErrors  = 0
 	seed        int64
 )
 
@@ -125,10 +128,20 @@ func TestRandomDCELPlumbLine(t *testing.T) {
 	printErrors()
 }
 
+func TestRandomDCELWalk(t *testing.T) {
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+
+	w := walk.New(dc)
+
+	testRandomPts(t, w, testCt, &walkErrors)
+	printErrors()
+}
+
 func TestRandomDCELRtree(t *testing.T) {
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
 
-	pl := rtree.DCELtoRtree(dc)
+	pl, err := rtree.DCELtoRtree(dc)
+	assert.Nil(t, err)
 
 	testRandomPts(t, pl, testCt, &rtreeErrors)
 	printErrors()
@@ -139,6 +152,7 @@ func printErrors() {
 	fmt.Println("Slab:", slabErrors)
 	fmt.Println("Trapezoid:", trapErrors)
 	fmt.Println("Rtree:", rtreeErrors)
+	fmt.Println("Walk:", walkErrors)
 	fmt.Println("Plumb Line:", plumbErrors, "(Baseline)")
 	fmt.Println()
 }
@@ -190,7 +204,7 @@ func BenchmarkRandomDCELRtree(b *testing.B) {
 	rand.Seed(seed)
 	dc := dcel.Random2DDCEL(inputRange, inputSize)
 
-	pl := rtree.DCELtoRtree(dc)
+	pl, _ := rtree.DCELtoRtree(dc)
 
 	rand.Seed(seed)
 	b.ResetTimer()
@@ -218,6 +232,72 @@ func BenchmarkRandomDCELPlumbLine(b *testing.B) {
 	}
 }
 
+func BenchmarkRandomDCELWalk(b *testing.B) {
+	if seed == 0 {
+		fmt.Println("Setting seed")
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+
+	pl := walk.New(dc)
+
+	rand.Seed(seed)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pt := randomPt()
+		pl.PointLocate(pt.X(), pt.Y())
+	}
+}
+
+// coherentPts returns n points along a random path with
+// steps of at most step, as a cursor would trace.
+func coherentPts(n int, step float64) []geom.D3 {
+	pts := make([]geom.D3, n)
+	x, y := inputRange/2, inputRange/2
+	for i := range pts {
+		x += (rand.Float64()*2 - 1) * step
+		y += (rand.Float64()*2 - 1) * step
+		x = math.Min(math.Max(x, 0), inputRange)
+		y = math.Min(math.Max(y, 0), inputRange)
+		pts[i] = geom.NewPoint(x, y, 0)
+	}
+	return pts
+}
+
+func benchmarkCoherent(b *testing.B, build func(*dcel.DCEL) pointLoc.LocatesPoints) {
+	if seed == 0 {
+		fmt.Println("Setting seed")
+		seed = time.Now().UnixNano()
+	}
+	rand.Seed(seed)
+	dc := dcel.Random2DDCEL(inputRange, inputSize)
+	pl := build(dc)
+	pts := coherentPts(4096, inputRange/100)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pt := pts[i%len(pts)]
+		pl.PointLocate(pt.X(), pt.Y())
+	}
+}
+
+func BenchmarkCoherentDCELWalk(b *testing.B) {
+	benchmarkCoherent(b, func(dc *dcel.DCEL) pointLoc.LocatesPoints {
+		return walk.New(dc)
+	})
+}
+
+func BenchmarkCoherentDCELTrapezoid(b *testing.B) {
+	benchmarkCoherent(b, func(dc *dcel.DCEL) pointLoc.LocatesPoints {
+		_, _, pl, _ := trapezoid.TrapezoidalMap(dc)
+		return pl
+	})
+}
+
+func BenchmarkCoherentDCELPlumbLine(b *testing.B) {
+	benchmarkCoherent(b, bruteForce.PlumbLine)
+}
+
 func BenchmarkRandomSetupSlab(b *testing.B) {
 	if seed == 0 {
 		fmt.Println("Setting seed")
@@ -280,6 +360,7 @@ func BenchmarkAll(b *testing.B) {
 		b.Run("Rtree", BenchmarkRandomDCELRtree)
 		b.Run("PlumbLineSetup", BenchmarkRandomSetupPlumbLine)
 		b.Run("PlumbLine", BenchmarkRandomDCELPlumbLine)
+		b.Run("Walk", BenchmarkRandomDCELWalk)
 	}
 }
 
@@ -0,0 +1,160 @@
+package test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/off"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/polyhedron"
+	"github.com/stretchr/testify/assert"
+)
+
+// cubeOFF returns the vertices and faces of an axis aligned
+// cube of side s at (x, y, z), numbering vertices from i.
+func cubeOFF(x, y, z, s float64, i int) (string, string) {
+	vs := ""
+	for _, c := range [][3]float64{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0},
+		{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1},
+	} {
+		vs += fmt.Sprintln(x+c[0]*s, y+c[1]*s, z+c[2]*s)
+	}
+	fs := ""
+	for _, f := range [][4]int{
+		{0, 3, 2, 1}, {4, 5, 6, 7}, {0, 1, 5, 4},
+		{1, 2, 6, 5}, {2, 3, 7, 6}, {3, 0, 4, 7},
+	} {
+		fs += fmt.Sprintln(4, f[0]+i, f[1]+i, f[2]+i, f[3]+i)
+	}
+	return vs, fs
+}
+
+func readOFF(t *testing.T, s string) *dcel.DCEL {
+	dc, err := off.Read(strings.NewReader(s))
+	assert.Nil(t, err)
+	return dc
+}
+
+func polyLocators(t *testing.T, dc *dcel.DCEL) map[string]polyhedron.LocatesCells {
+	rc, err := polyhedron.NewRayCaster(dc)
+	assert.Nil(t, err)
+	bsp, err := polyhedron.NewBSP(dc)
+	assert.Nil(t, err)
+	return map[string]polyhedron.LocatesCells{"ray": rc, "bsp": bsp}
+}
+
+func TestPolyhedronConvex(t *testing.T) {
+	vs, fs := cubeOFF(0, 0, 0, 1, 0)
+	dc := readOFF(t, "OFF\n8 6 0\n"+vs+fs)
+	cells, err := polyhedron.Cells(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cells))
+	assert.True(t, cells[0].Convex())
+	assert.InDelta(t, 1.0, cells[0].Volume(), 1e-9)
+	for name, lc := range polyLocators(t, dc) {
+		for _, c := range []struct {
+			p    [3]float64
+			side polyhedron.Side
+		}{
+			{[3]float64{.5, .5, .5}, polyhedron.Inside},
+			{[3]float64{.1, .9, .2}, polyhedron.Inside},
+			{[3]float64{1.5, .5, .5}, polyhedron.Outside},
+			{[3]float64{.5, .5, -.1}, polyhedron.Outside},
+			{[3]float64{.5, .5, 1}, polyhedron.OnBoundary},
+			{[3]float64{1, 1, 1}, polyhedron.OnBoundary},
+		} {
+			loc, err := lc.Locate(c.p[0], c.p[1], c.p[2])
+			assert.Nil(t, err)
+			assert.Equal(t, c.side, loc.Side, name, c.p)
+			assert.Equal(t, c.side == polyhedron.Outside, loc.Cell == nil, name)
+		}
+		_, err := lc.Locate(.5, .5)
+		assert.Equal(t, compgeo.InsufficientDimensionsError{}, err)
+	}
+}
+
+// lPrismOFF is an L shaped prism, the union of [0,2]x[0,1]
+// and [0,1]x[1,2], from z = 0 to 1.
+const lPrismOFF = `OFF
+12 8 0
+0 0 0
+2 0 0
+2 1 0
+1 1 0
+1 2 0
+0 2 0
+0 0 1
+2 0 1
+2 1 1
+1 1 1
+1 2 1
+0 2 1
+6 5 4 3 2 1 0
+6 6 7 8 9 10 11
+4 0 1 7 6
+4 1 2 8 7
+4 2 3 9 8
+4 3 4 10 9
+4 4 5 11 10
+4 5 0 6 11
+`
+
+func TestPolyhedronConcave(t *testing.T) {
+	rand.Seed(51)
+	dc := readOFF(t, lPrismOFF)
+	cells, err := polyhedron.Cells(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(cells))
+	assert.False(t, cells[0].Convex())
+	assert.InDelta(t, 3.0, cells[0].Volume(), 1e-9)
+	lcs := polyLocators(t, dc)
+	assert.True(t, lcs["bsp"].(*polyhedron.BSP).Depth() > 0)
+	for i := 0; i < 2000; i++ {
+		x, y, z := rand.Float64()*3-.5, rand.Float64()*3-.5, rand.Float64()*2-.5
+		in := z > 0 && z < 1 && x > 0 && y > 0 &&
+			((x < 2 && y < 1) || (x < 1 && y < 2))
+		for name, lc := range lcs {
+			ok, err := polyhedron.Contains(lc, x, y, z)
+			assert.Nil(t, err)
+			assert.Equal(t, in, ok, name, x, y, z)
+		}
+	}
+	// The notch of the L is outside
+	for name, lc := range lcs {
+		loc, _ := lc.Locate(1.5, 1.5, .5)
+		assert.Equal(t, polyhedron.Outside, loc.Side, name)
+		loc, _ = lc.Locate(1, 1.5, .5)
+		assert.Equal(t, polyhedron.OnBoundary, loc.Side, name)
+	}
+}
+
+func TestPolyhedronNested(t *testing.T) {
+	vs, fs := cubeOFF(0, 0, 0, 4, 0)
+	vs2, fs2 := cubeOFF(1, 1, 1, 1, 8)
+	dc := readOFF(t, "OFF\n16 12 0\n"+vs+vs2+fs+fs2)
+	cells, err := polyhedron.Cells(dc)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(cells))
+	for name, lc := range polyLocators(t, dc) {
+		outer, _ := lc.Locate(3, 3, 3)
+		inner, _ := lc.Locate(1.5, 1.5, 1.5)
+		out, _ := lc.Locate(5, 5, 5)
+		assert.Equal(t, polyhedron.Inside, outer.Side, name)
+		assert.Equal(t, polyhedron.Inside, inner.Side, name)
+		assert.Equal(t, polyhedron.Outside, out.Side, name)
+		assert.InDelta(t, 64.0, outer.Cell.Volume(), 1e-9, name)
+		assert.InDelta(t, 1.0, inner.Cell.Volume(), 1e-9, name)
+	}
+}
+
+func TestPolyhedronOpen(t *testing.T) {
+	dc := readOFF(t, "OFF\n3 1 0\n0 0 0\n1 0 0\n0 1 0\n3 0 1 2\n")
+	_, err := polyhedron.NewRayCaster(dc)
+	assert.Equal(t, compgeo.NotManifoldError{}, err)
+	_, err = polyhedron.NewBSP(nil)
+	assert.Equal(t, compgeo.BadDCELError{}, err)
+}
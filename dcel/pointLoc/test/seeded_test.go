@@ -0,0 +1,69 @@
+package test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	fullTrapezoid "github.com/200sc/go-compgeo/dcel/pointLoc/trapezoid"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandom2DDCELSeeded(t *testing.T) {
+	dc := dcel.Random2DDCELSeeded(100, 8, 41)
+	dc2 := dcel.Random2DDCELSeeded(100, 8, 41)
+	assert.Equal(t, len(dc.Vertices), len(dc2.Vertices))
+	for i, v := range dc.Vertices {
+		assert.Equal(t, v.Point, dc2.Vertices[i].Point)
+	}
+}
+
+func TestTrapezoidalMapSeeded(t *testing.T) {
+	dc := dcel.Random2DDCELSeeded(100, 16, 42)
+	encode := func(seed int64) ([]byte, fullTrapezoid.Stats) {
+		_, _, tr, err := fullTrapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, seed)
+		assert.Nil(t, err)
+		b := &bytes.Buffer{}
+		assert.Nil(t, tr.Encode(b))
+		return b.Bytes(), tr.Stats()
+	}
+	b1, st1 := encode(7)
+	b2, st2 := encode(7)
+	b3, _ := encode(8)
+	// The same seed builds the same map, down to the order
+	// of its nodes
+	assert.Equal(t, b1, b2)
+	assert.Equal(t, st1, st2)
+	assert.NotEqual(t, b1, b3)
+
+	assert.Equal(t, int64(7), st1.Seed)
+	assert.Equal(t, st1.Nodes, st1.XNodes+st1.YNodes+st1.Trapezoids+1)
+	assert.True(t, st1.Depth > 0)
+	assert.True(t, st1.Depth <= fullTrapezoid.MaxDepth(len(dc.HalfEdges)/2))
+}
+
+func TestTrapezoidalMapRebuild(t *testing.T) {
+	dc := dcel.Random2DDCELSeeded(1000, 4, 3)
+	n := len(dc.HalfEdges) / 2
+	// Find a seed whose map is too deep, and so is rebuilt
+	for seed := int64(0); seed < 1000; seed++ {
+		_, _, tr, err := fullTrapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, seed)
+		assert.Nil(t, err)
+		st := tr.Stats()
+		if st.Seed == seed {
+			continue
+		}
+		assert.True(t, st.Depth <= fullTrapezoid.MaxDepth(n))
+		// The reported seed builds the same map directly
+		_, _, tr2, err := fullTrapezoid.TrapezoidalMapSeeded(dc, geom.DefaultTolerance, st.Seed)
+		assert.Nil(t, err)
+		assert.Equal(t, st, tr2.Stats())
+		b1, b2 := &bytes.Buffer{}, &bytes.Buffer{}
+		assert.Nil(t, tr.Encode(b1))
+		assert.Nil(t, tr2.Encode(b2))
+		assert.Equal(t, b1.Bytes(), b2.Bytes())
+		return
+	}
+	t.Fatal("no map was rebuilt")
+}
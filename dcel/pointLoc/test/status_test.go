@@ -0,0 +1,35 @@
+package test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/bruteForce"
+	"github.com/200sc/go-compgeo/dcel/pointLoc/slab"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/skiplist"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlabSkipListStatus(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	for i := int64(0); i < 10; i++ {
+		dc := dcel.Random2DDCELSeeded(100, 16, 100+i)
+		sl, err := slab.DecomposeWithStatus(dc, skiplist.NewSeeded(i), geom.DefaultTolerance)
+		assert.Nil(t, err)
+		rb, err := slab.Decompose(dc, tree.RedBlack)
+		assert.Nil(t, err)
+		pl := bruteForce.PlumbLine(dc)
+		for j := 0; j < 500; j++ {
+			x, y := rng.Float64()*100, rng.Float64()*100
+			f, err := sl.PointLocate(x, y)
+			assert.Nil(t, err)
+			f2, _ := rb.PointLocate(x, y)
+			f3, _ := pl.PointLocate(x, y)
+			assert.True(t, f == f2, "skip list and red black slabs differ at", x, y)
+			assert.True(t, f == f3, "skip list slab and plumb line differ at", x, y)
+		}
+	}
+}
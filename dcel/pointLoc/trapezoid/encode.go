@@ -0,0 +1,319 @@
+package trapezoid
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+const encodingKind = "TRAP"
+
+// Node kinds in an encoded search structure
+const (
+	rootKind uint8 = iota
+	xKind
+	yKind
+	trapKind
+)
+
+// encTrapezoid is how a trapezoid is encoded, with its
+// neighbors given by their index among the map's
+// trapezoids and its faces by their index in the DCEL.
+type encTrapezoid struct {
+	Top, Bot    [2]float64
+	Left, Right float64
+	Neighbors   [4]int32
+	Faces       [2]int32
+}
+
+// encNode is how a node is encoded. Children are given
+// by their index among the structure's nodes, and always
+// follow their parents. Which of Point, Edge, and Trap is
+// meaningful depends on Kind.
+type encNode struct {
+	Kind        uint8
+	Left, Right int32
+	Point       geom.Point
+	Edge        geom.FullEdge
+	Trap        int32
+}
+
+// Encode writes the search structure rooted at tn, as
+// returned by TrapezoidalMap, to w in a versioned binary
+// format, from which Decode can rebuild it without mapping
+// its DCEL again. After the pointLoc header, the map is
+// written as its tolerance and outer face, its trapezoids,
+// and then the nodes of its search structure, with parents
+// before their children. Exact vertex positions are written
+// as their float approximations.
+func (tn *Node) Encode(w io.Writer) error {
+	info, ok := tn.payload.(rootInfo)
+	if !ok {
+		return compgeo.TypeError{}
+	}
+	bw := bufio.NewWriter(w)
+	if err := pointLoc.WriteHeader(bw, encodingKind, info.dc); err != nil {
+		return err
+	}
+	ix := pointLoc.NewIndex(info.dc)
+	nodes := tn.topological()
+	nodeIdx := make(map[*Node]int32, len(nodes))
+	trapIdx := make(map[*Trapezoid]int32)
+	trs := []*Trapezoid{}
+	for i, n := range nodes {
+		nodeIdx[n] = int32(i)
+		if tr, ok := n.payload.(*Trapezoid); ok {
+			trapIdx[tr] = int32(len(trs))
+			trs = append(trs, tr)
+		}
+	}
+	write := func(v interface{}) error {
+		return binary.Write(bw, pointLoc.Byteorder, v)
+	}
+	if err := write([2]float64{info.tol.Abs, info.tol.Rel}); err != nil {
+		return err
+	}
+	if err := write(ix.Face(info.outer)); err != nil {
+		return err
+	}
+	if err := write(uint32(len(trs))); err != nil {
+		return err
+	}
+	for _, tr := range trs {
+		et := encTrapezoid{
+			Top:   tr.top,
+			Bot:   tr.bot,
+			Left:  tr.left,
+			Right: tr.right,
+			Faces: [2]int32{ix.Face(tr.faces[0]), ix.Face(tr.faces[1])},
+		}
+		for i, nb := range tr.Neighbors {
+			et.Neighbors[i] = -1
+			if j, ok := trapIdx[nb]; ok {
+				et.Neighbors[i] = j
+			}
+		}
+		if err := write(et); err != nil {
+			return err
+		}
+	}
+	if err := write(uint32(len(nodes))); err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		en := encNode{Left: -1, Right: -1, Trap: -1}
+		if n.left != nil {
+			en.Left = nodeIdx[n.left]
+		}
+		if n.right != nil {
+			en.Right = nodeIdx[n.right]
+		}
+		switch v := n.payload.(type) {
+		case rootInfo:
+			en.Kind = rootKind
+		case segment:
+			en.Kind = yKind
+			en.Edge = v.FullEdge
+		case geom.D3:
+			en.Kind = xKind
+			en.Point = geom.NewPoint(v.X(), v.Y(), v.Z())
+		case *Trapezoid:
+			en.Kind = trapKind
+			en.Trap = trapIdx[v]
+		default:
+			return compgeo.TypeError{}
+		}
+		if err := write(en); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// topological returns the nodes reachable from tn, ordered
+// so that each node comes before all of its children.
+func (tn *Node) topological() []*Node {
+	post := []*Node{}
+	seen := make(map[*Node]bool)
+	var visit func(*Node)
+	visit = func(n *Node) {
+		if n == nil || seen[n] {
+			return
+		}
+		seen[n] = true
+		visit(n.left)
+		visit(n.right)
+		post = append(post, n)
+	}
+	visit(tn)
+	for i, j := 0, len(post)-1; i < j; i, j = i+1, j-1 {
+		post[i], post[j] = post[j], post[i]
+	}
+	return post
+}
+
+// Decode reads a search structure over dc from r, as
+// written by Encode. It fails if r was not encoded from
+// a map of a DCEL identical to dc. The x and y nodes of the
+// result compare against the exact positions of dc's
+// vertices, while its trapezoids keep only float bounds.
+func Decode(r io.Reader, dc *dcel.DCEL) (*Node, error) {
+	if err := pointLoc.ReadHeader(r, encodingKind, dc); err != nil {
+		return nil, err
+	}
+	read := func(v interface{}) error {
+		return binary.Read(r, pointLoc.Byteorder, v)
+	}
+	var tolv [2]float64
+	if err := read(&tolv); err != nil {
+		return nil, err
+	}
+	t := geom.NewTolerance(tolv[0], tolv[1])
+	var outer int32
+	if err := read(&outer); err != nil {
+		return nil, err
+	}
+	outerFace, err := pointLoc.FaceAt(dc, outer)
+	if err != nil {
+		return nil, err
+	}
+
+	var n uint32
+	if err := read(&n); err != nil {
+		return nil, err
+	}
+	// Trapezoids are read before they are linked, so that
+	// a short input fails before a large allocation.
+	ets := []encTrapezoid{}
+	for i := uint32(0); i < n; i++ {
+		et := encTrapezoid{}
+		if err := read(&et); err != nil {
+			return nil, err
+		}
+		ets = append(ets, et)
+	}
+	trs := make([]*Trapezoid, len(ets))
+	for i := range trs {
+		trs[i] = new(Trapezoid)
+	}
+	for i, et := range ets {
+		tr := trs[i]
+		tr.top, tr.bot = et.Top, et.Bot
+		tr.left, tr.right = et.Left, et.Right
+		for j, nb := range et.Neighbors {
+			if nb < -1 || int(nb) >= len(trs) {
+				return nil, compgeo.RangeError{}
+			}
+			if nb != -1 {
+				tr.Neighbors[j] = trs[nb]
+			}
+		}
+		tr.sides[top] = newSegment(geom.NewPoint(tr.left, tr.top[left], 0),
+			geom.NewPoint(tr.right, tr.top[right], 0))
+		tr.sides[bot] = newSegment(geom.NewPoint(tr.left, tr.bot[left], 0),
+			geom.NewPoint(tr.right, tr.bot[right], 0))
+		for j, f := range et.Faces {
+			if tr.faces[j], err = pointLoc.FaceAt(dc, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, tr := range trs {
+		tr.setLeft(geom.NewPoint(tr.left, wallY(tr, upleft, botleft, right, tr.bot[left]), 0))
+		tr.setRight(geom.NewPoint(tr.right, wallY(tr, upright, botright, left, tr.bot[right]), 0))
+	}
+	positions := make(map[geom.Point]geom.D3, len(dc.Vertices))
+	for _, v := range dc.Vertices {
+		positions[v.Point] = v.Position()
+	}
+	position := func(p geom.Point) geom.D3 {
+		if d3, ok := positions[p]; ok {
+			return d3
+		}
+		return p
+	}
+
+	if err := read(&n); err != nil {
+		return nil, err
+	}
+	ens := []encNode{}
+	for i := uint32(0); i < n; i++ {
+		en := encNode{}
+		if err := read(&en); err != nil {
+			return nil, err
+		}
+		ens = append(ens, en)
+	}
+	if len(ens) == 0 || ens[0].Kind != rootKind {
+		return nil, compgeo.TypeError{}
+	}
+	nodes := make([]*Node, len(ens))
+	for i, en := range ens {
+		switch en.Kind {
+		case rootKind:
+			if i != 0 {
+				return nil, compgeo.TypeError{}
+			}
+			nodes[i] = NewRoot()
+			nodes[i].payload = rootInfo{dc, outerFace, t, 0}
+		case xKind:
+			nodes[i] = &Node{
+				query: func(fe segment, n *Node) []*Trapezoid {
+					return xQuery(fe, n, t)
+				},
+				payload: position(en.Point),
+			}
+		case yKind:
+			nodes[i] = newY(newSegment(position(en.Edge[0]), position(en.Edge[1])))
+		case trapKind:
+			if en.Trap < 0 || int(en.Trap) >= len(trs) || trs[en.Trap].node != nil {
+				return nil, compgeo.RangeError{}
+			}
+			nodes[i] = NewTrapNode(trs[en.Trap])
+		default:
+			return nil, compgeo.TypeError{}
+		}
+	}
+	for _, tr := range trs {
+		if tr.node == nil {
+			return nil, compgeo.TypeError{}
+		}
+	}
+	for i, en := range ens {
+		// Only x and y nodes may have two children, the root
+		// has only a left child, and trapezoids are leaves.
+		// Children must follow their parents, so that the
+		// structure has no cycles.
+		allowed := [2]bool{en.Kind != trapKind, en.Kind == xKind || en.Kind == yKind}
+		for j, c := range []int32{en.Left, en.Right} {
+			if c == -1 {
+				continue
+			}
+			if !allowed[j] {
+				return nil, compgeo.TypeError{}
+			}
+			if int(c) <= i || int(c) >= len(nodes) {
+				return nil, compgeo.RangeError{}
+			}
+			nodes[i].set(j, nodes[c])
+		}
+	}
+	return nodes[0], nil
+}
+
+// wallY returns the height of the point through which one wall
+// of tr passes: where the two neighbors of tr beyond that wall
+// meet, if it has two, and otherwise y.
+func wallY(tr *Trapezoid, up, down, side int, y float64) float64 {
+	u := tr.Neighbors[up]
+	if u != nil && u != tr.Neighbors[down] {
+		return u.bot[side]
+	}
+	return y
+}
@@ -1,6 +1,7 @@
 package trapezoid
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/200sc/go-compgeo/dcel"
@@ -8,32 +9,85 @@ import (
 	"github.com/200sc/go-compgeo/geom"
 )
 
-var (
-	tree *Node
-	err  error
-)
+// MaxRebuilds is how many times TrapezoidalMapSeeded will
+// rebuild a map whose search structure is too deep before
+// settling for the shallowest map it has built.
+const MaxRebuilds = 16
+
+// MaxDepth returns the greatest depth TrapezoidalMapSeeded
+// accepts in the search structure of a map of n edges.
+// The expected depth of a map built in a random order is
+// O(log n); measured over random DCELs, the median depth is
+// near 5ln(n+1), and about one map in a hundred is deeper
+// than this bound and so is rebuilt.
+func MaxDepth(n int) int {
+	return int(math.Ceil(6*math.Log(float64(n+1)))) + 4
+}
 
 // TrapezoidalMap converts a dcel into a version of itself split into
 // trapezoids and a search structure to find a containing trapezoid in
 // the map in response to a point location query.
 func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
-	bounds := dc.Bounds()
+	return TrapezoidalMapWithin(dc, geom.DefaultTolerance)
+}
+
+// TrapezoidalMapWithin acts as TrapezoidalMap, but treats points
+// within tolerance t of one another as equal, both while building
+// the map and when querying the resulting search structure.
+//
+// The returned Node is safe for concurrent queries, so long
+// as the visualizer is not running.
+func TrapezoidalMapWithin(dc *dcel.DCEL, t geom.Tolerance) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
+	return TrapezoidalMapSeeded(dc, t, rand.Int63())
+}
+
+// TrapezoidalMapSeeded acts as TrapezoidalMapWithin, but inserts
+// edges in a random order drawn from seed, so that the same DCEL
+// and seed always give the same map. Should the map's search
+// structure be deeper than MaxDepth, it is rebuilt with further
+// seeds drawn from seed, bounding the length of any query.
+// The seed of the returned map is reported by its Stats.
+func TrapezoidalMapSeeded(dc *dcel.DCEL, t geom.Tolerance, seed int64) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node, error) {
+	seeds := rand.New(rand.NewSource(seed))
+	var best *Node
+	bestDepth := math.MaxInt32
+	for i := 0; i <= MaxRebuilds; i++ {
+		tn, n, err := build(dc, t, seed)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		d := tn.Stats().Depth
+		if d < bestDepth {
+			best, bestDepth = tn, d
+		}
+		if d <= MaxDepth(n) {
+			break
+		}
+		seed = seeds.Int63()
+	}
+	dc2, m := best.DCEL()
+	return dc2, m, best, nil
+}
+
+// build returns the search structure of a trapezoidal map
+// of dc, inserting its edges in an order drawn from seed,
+// and the number of edges inserted.
+func build(dc *dcel.DCEL, tol geom.Tolerance, seed int64) (*Node, int, error) {
+	min, max := bounds(dc)
 
-	tree = NewRoot()
-	tree.payload = dc.Faces[dcel.OUTER_FACE]
-	tree.set(left, NewTrapNode(newTrapezoid(bounds)))
+	tree := NewRoot()
+	tree.payload = rootInfo{dc, dc.Faces[dcel.OUTER_FACE], tol, seed}
+	tree.set(left, NewTrapNode(newTrapezoid(min, max)))
 
-	fullEdges, faces, err := dc.FullEdges()
+	fullEdges, faces, err := segments(dc)
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, 0, err
 	}
 	// Get rid of bad (duplicate) edges
 	i := 0
 	for i < len(fullEdges) {
 		fe := fullEdges[i]
-		l := fe.Left()
-		r := fe.Right()
-		if geom.F64eq(l.X(), r.X()) && geom.F64eq(l.Y(), r.Y()) {
+		if eqPoint(fe.l, fe.r, tol) {
 			fullEdges = append(fullEdges[0:i], fullEdges[i+1:]...)
 			faces = append(faces[0:i], faces[i+1:]...)
 			i--
@@ -41,8 +95,9 @@ func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node
 		i++
 	}
 	// Scramble the edges
+	rng := rand.New(rand.NewSource(seed))
 	for i := range fullEdges {
-		j := i + rand.Intn(len(fullEdges)-i)
+		j := i + rng.Intn(len(fullEdges)-i)
 		fullEdges[i], fullEdges[j] = fullEdges[j], fullEdges[i]
 		faces[i], faces[j] = faces[j], faces[i]
 	}
@@ -50,7 +105,7 @@ func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node
 		visualize.HighlightColor = visualize.AddColor
 		visualize.DrawLine(fe.Left(), fe.Right())
 		// 1: Find the trapezoids intersected by fe
-		trs := tree.Query(fe)
+		trs := tree.find(fe)
 		// 2: Remove those and replace them with what they become
 		//    due to the intersection of halfEdges[i]
 		// Case A: A fe is contained in a single trapezoid tr
@@ -61,11 +116,10 @@ func TrapezoidalMap(dc *dcel.DCEL) (*dcel.DCEL, map[*dcel.Face]*dcel.Face, *Node
 		if len(trs) == 1 {
 			visualize.HighlightColor = visualize.CheckFaceColor
 			visualize.DrawPoly(trs[0].toPhysics())
-			mapSingleCase(trs[0], fe, faces[k])
+			mapSingleCase(trs[0], fe, faces[k], tol)
 		} else {
-			mapMultipleCase(trs, fe, faces[k])
+			mapMultipleCase(trs, fe, faces[k], tol)
 		}
 	}
-	dc, m := tree.DCEL()
-	return dc, m, tree, nil
+	return tree, len(fullEdges), nil
 }
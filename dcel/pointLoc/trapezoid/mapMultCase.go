@@ -1,32 +1,30 @@
 package trapezoid
 
 import (
-	"fmt"
-
 	"github.com/200sc/go-compgeo/dcel"
 	"github.com/200sc/go-compgeo/geom"
 )
 
-func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
+func mapMultipleCase(trs []*Trapezoid, fe segment, faces [2]*dcel.Face, tol geom.Tolerance) {
 
-	lp, rp := fe.BothPoints()
+	lp, rp := fe.l, fe.r
 	// Case B: fe is contained by more than one trapezoid
 	// Step 1: if either fe.Left() or fe.Right() is not already
 	// in the search structure, we define three new trapezoids by
 	// drawing rays up and down from each new point.
 	var ln, un, bn, x *Node
 
-	y := NewY(fe)
+	y := newY(fe)
 
 	u := trs[0].Copy()
 	b := trs[0].Copy()
 	u.faces = faces
 	b.faces = faces
 
-	u.left = lp.X()
+	u.setLeft(lp)
 	u.setBotleft(fe)
 
-	b.left = lp.X()
+	b.setLeft(lp)
 	b.setTopleft(fe)
 
 	// At this point we have u and b defined as
@@ -39,16 +37,15 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 	//       . .
 	// with no neighbors defined
 
-	if !geom.F64eq(lp.X(), trs[0].left) {
-		fmt.Println("L exists")
+	if !eqX(lp, trs[0].walls[left], tol) {
 		// The three trapezoids are split into
 		// one to the left of an x node
 		// and two below the previous y node
-		x = NewX(lp)
+		x = NewXWithin(lp, tol)
 		l := trs[0].Copy()
 		NewTopRight, _ := l.TopEdge().PointAt(0, lp.X())
 		NewBotRight, _ := l.BotEdge().PointAt(0, lp.X())
-		l.right = lp.X()
+		l.setRight(lp)
 		l.bot[right] = NewBotRight.Y()
 		l.top[right] = NewTopRight.Y()
 		b.bot[left] = NewBotRight.Y()
@@ -62,15 +59,14 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		x.set(left, ln)
 		x.set(right, y)
 
-		l.twoRights(u, b, lp.Y())
+		l.twoRights(u, b, lp, tol)
 
 		annotatedVisualize([]string{"L"}, []*Trapezoid{l})
 
 	} else {
-		fmt.Println("No L")
 		// Otherwise we just split trs[0] into two trapezoids.
 		trs[0].node.discard(y)
-		trs[0].replaceLeftPointers(u, b, lp.Y())
+		trs[0].replaceLeftPointers(u, b, lp, tol)
 	}
 
 	un = NewTrapNode(u)
@@ -87,7 +83,7 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		// We are going to split this trapezoid into
 		// an upper and lower trapezoid.
 
-		y = NewY(fe)
+		y = newY(fe)
 
 		// It is possible that one or both trapezoids
 		// we make are mergeable into the previous upper
@@ -103,7 +99,6 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		// 	u.setBotleft(fe)
 		// 	fmt.Println("Merged:", u)
 		// } else {
-		fmt.Println("Non merge U")
 		u2 := tr.Copy()
 		//
 		u.Neighbors[botright] = u2
@@ -116,8 +111,8 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		// is at a different angle.
 		u2tl := u2.TopEdge().Left()
 		utr := u.TopEdge().Right()
-		if u2tl.X() == utr.X() && u2tl.Y() == utr.Y() {
-			fmt.Println("U2tl and utr equal")
+		c := cmpSides(u2.sides[top], u.sides[top], u2tl.Y(), utr.Y(), u2.walls[left])
+		if c == 0 && geom.CmpVal(u2.walls[left], u.walls[right], 0) == 0 {
 			// In this case, u2's top left and bot left are both u.
 			// u's bot right and bot left are similarly both u2.
 			u.Neighbors[upright] = u2
@@ -128,14 +123,12 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 			//
 			// tr's left neighbors('s neighbors) do not need to be updated,
 			// because both left neighbors were consumed by u.
-		} else if u2tl.Y() > utr.Y() {
-			fmt.Println("U2tl above utr")
+		} else if c > 0 {
 			// B: this trapezoid's left endpoint is above
 			// the left endpoint of the previous trapezoid.
 			u.Neighbors[upright] = u2
 			u2.Neighbors[upleft].replaceNeighbors(tr, u2)
 		} else {
-			fmt.Println("U2tl below utr")
 			// C: this trapezoid's left endpoint is below
 			// the left endpoint of the previous trapezoid.
 			u2.Neighbors[upleft] = u
@@ -143,7 +136,7 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 			// of the former trapezoid by now.
 			u.Neighbors[upright].replaceNeighbors(trs[i-1], u)
 		}
-		u.right = u2.left
+		u.setRight(u2.walls[left])
 		// the bottom edge is now this shard of fe.
 		// if this trapezoid is merged with another,
 		// this may change.
@@ -165,27 +158,24 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		// 	b.setTopleft(fe)
 		// 	fmt.Println("Merged:", b)
 		// } else {
-		fmt.Println("Did not merge B")
 		b2 := tr.Copy()
 		b.Neighbors[upright] = b2
 		b2.Neighbors[upleft] = b
 
 		b2bl := b2.BotEdge().Left()
 		bbr := b.BotEdge().Right()
-		if b2bl.X() == bbr.X() && b2bl.Y() == bbr.Y() {
-			fmt.Println("Equal b2bl and bbr")
+		c = cmpSides(b2.sides[bot], b.sides[bot], b2bl.Y(), bbr.Y(), b2.walls[left])
+		if c == 0 && geom.CmpVal(b2.walls[left], b.walls[right], 0) == 0 {
 			b.Neighbors[botright] = b2
 			b2.Neighbors[botleft] = b
-		} else if b2bl.Y() < bbr.Y() {
-			fmt.Println("b2bl below bbr")
+		} else if c < 0 {
 			b.Neighbors[botright] = b2
 			b2.Neighbors[botleft].replaceNeighbors(tr, b2)
 		} else {
-			fmt.Println("b2bl above bbr")
 			b2.Neighbors[botleft] = b
 			b.Neighbors[botright].replaceNeighbors(trs[i-1], b)
 		}
-		b.right = b2.left
+		b.setRight(b2.walls[left])
 
 		b2.setTopleft(fe)
 
@@ -205,18 +195,17 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 	// will be the same in the next case
 
 	var r *Trapezoid
-	u.right = rp.X()
-	b.right = rp.X()
+	u.setRight(rp)
+	b.setRight(rp)
 
 	trn := trs[len(trs)-1]
 
-	if !geom.F64eq(rp.X(), trn.right) {
-		fmt.Println("RP Not On Right Edge, TRN")
+	if !eqX(rp, trn.walls[right], tol) {
 		r = trn.Copy()
 
 		NewTopLeft, _ := r.TopEdge().PointAt(0, rp.X())
 		NewBotLeft, _ := r.BotEdge().PointAt(0, rp.X())
-		r.left = rp.X()
+		r.setLeft(rp)
 		r.bot[left] = NewBotLeft.Y()
 		r.top[left] = NewTopLeft.Y()
 		b.bot[right] = NewBotLeft.Y()
@@ -224,9 +213,9 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		r.Neighbors[upright].replaceNeighbors(trn, r)
 		r.Neighbors[botright].replaceNeighbors(trn, r)
 
-		r.twoLefts(u, b, rp.Y())
+		r.twoLefts(u, b, rp, tol)
 
-		x = NewX(rp)
+		x = NewXWithin(rp, tol)
 		// X needs to be put between y's
 		// parents and y
 		y.discard(x)
@@ -234,10 +223,8 @@ func mapMultipleCase(trs []*Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		x.set(right, NewTrapNode(r))
 
 	} else {
-		fmt.Println("RP On Right edge, TRN")
-		trn.replaceRightPointers(u, b, rp.Y())
+		trn.replaceRightPointers(u, b, rp, tol)
 	}
 
-	fmt.Println("B", b)
 	annotatedVisualize([]string{"U", "B", "R"}, []*Trapezoid{u, b, r})
 }
@@ -5,11 +5,11 @@ import (
 	"github.com/200sc/go-compgeo/geom"
 )
 
-func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
+func mapSingleCase(tr *Trapezoid, fe segment, faces [2]*dcel.Face, tol geom.Tolerance) {
 
 	var l, r *Trapezoid
 	ur, br, ul, bl := tr.GetNeighbors()
-	lp, rp := fe.BothPoints()
+	lp, rp := fe.l, fe.r
 
 	u := tr.Copy()
 	d := tr.Copy()
@@ -18,11 +18,11 @@ func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 	d.faces = faces
 
 	// LP does not lie on the left edge of TR
-	if !geom.F64eq(lp.X(), tr.left) {
+	if !eqX(lp, tr.walls[left], tol) {
 		l = tr.Copy()
 		NewTopRight, _ := l.TopEdge().PointAt(0, lp.X())
 		NewBotRight, _ := l.BotEdge().PointAt(0, lp.X())
-		l.right = lp.X()
+		l.setRight(lp)
 		l.bot[right] = NewBotRight.Y()
 		l.top[right] = NewTopRight.Y()
 		d.bot[left] = NewBotRight.Y()
@@ -30,15 +30,15 @@ func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		ul.replaceNeighbors(tr, l)
 		bl.replaceNeighbors(tr, l)
 
-		l.twoRights(u, d, lp.Y())
+		l.twoRights(u, d, lp, tol)
 	} else {
-		tr.replaceLeftPointers(u, d, lp.Y())
+		tr.replaceLeftPointers(u, d, lp, tol)
 	}
-	if !geom.F64eq(rp.X(), tr.right) {
+	if !eqX(rp, tr.walls[right], tol) {
 		r = tr.Copy()
 		NewTopLeft, _ := r.TopEdge().PointAt(0, rp.X())
 		NewBotLeft, _ := r.BotEdge().PointAt(0, rp.X())
-		r.left = rp.X()
+		r.setLeft(rp)
 		r.bot[left] = NewBotLeft.Y()
 		r.top[left] = NewTopLeft.Y()
 		d.bot[right] = NewBotLeft.Y()
@@ -46,9 +46,9 @@ func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 		ur.replaceNeighbors(tr, r)
 		br.replaceNeighbors(tr, r)
 
-		r.twoLefts(u, d, rp.Y())
+		r.twoLefts(u, d, rp, tol)
 	} else {
-		tr.replaceRightPointers(u, d, rp.Y())
+		tr.replaceRightPointers(u, d, rp, tol)
 	}
 
 	// D and U are exactly below // above
@@ -59,9 +59,9 @@ func mapSingleCase(tr *Trapezoid, fe geom.FullEdge, faces [2]*dcel.Face) {
 	//    removed trapezoids and add new leaves for the new
 	//    trapezoids, with additional inner nodes as necessary.
 
-	a := NewX(lp)
-	b := NewX(rp)
-	c := NewY(fe)
+	a := NewXWithin(lp, tol)
+	b := NewXWithin(rp, tol)
+	c := newY(fe)
 
 	// Our structure should have tr's parent point to a,
 	// a point to l and b, b point to r and c, and c
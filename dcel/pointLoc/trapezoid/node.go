@@ -1,10 +1,9 @@
 package trapezoid
 
 import (
-	"fmt"
-
 	compgeo "github.com/200sc/go-compgeo"
 	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
 	"github.com/200sc/go-compgeo/geom"
 	"github.com/200sc/go-compgeo/printutil"
 )
@@ -16,7 +15,7 @@ import (
 type Node struct {
 	left, right *Node
 	parents     []*Node
-	query       func(geom.FullEdge, *Node) []*Trapezoid
+	query       func(segment, *Node) []*Trapezoid
 	payload     interface{}
 }
 
@@ -28,6 +27,10 @@ type Node struct {
 // OFF structure as a DCEL.
 func (tn *Node) DCEL() (*dcel.DCEL, map[*dcel.Face]*dcel.Face) {
 	dc := new(dcel.DCEL)
+	tol := geom.DefaultTolerance
+	if info, ok := tn.payload.(rootInfo); ok {
+		tol = info.tol
+	}
 	trs := tn.inOrder()
 	// This maps from faces in the output of this algorithm
 	// to faces in the input of the TrapezoidMap.
@@ -46,7 +49,7 @@ func (tn *Node) DCEL() (*dcel.DCEL, map[*dcel.Face]*dcel.Face) {
 		// each of the up to four edges in a trapezoid
 		// has an edge associated with it, the first of which is the face's
 		// inner (going with the broken convention of always using inner)
-		edges := tr.DCELEdges()
+		edges := tr.DCELEdgesWithin(tol)
 		dc.Faces[i].Outer = edges[0]
 		dc.HalfEdges = append(dc.HalfEdges, edges...)
 		// each vertex in each trapezoid, if it has not been seen before,
@@ -96,12 +99,10 @@ func (tn *Node) PointLocate(vs ...float64) (*dcel.Face, error) {
 	pt := geom.Point{vs[0], vs[1], 0}
 	trs := tn.Query(geom.FullEdge{pt, pt})
 	if len(trs) == 0 {
-		fmt.Println("No trapezoids found")
 		return nil, nil
 	}
 	faces := trs[0].faces
-	outerFace := tn.payload.(*dcel.Face)
-	fmt.Println("Potential faces found", faces)
+	outerFace := tn.payload.(rootInfo).outer
 	if faces[0] != outerFace && faces[0].Contains(pt) {
 		return faces[0], nil
 	}
@@ -111,8 +112,49 @@ func (tn *Node) PointLocate(vs ...float64) (*dcel.Face, error) {
 	return nil, nil
 }
 
-// Query is shorthand for tn.query(fe, tn)
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of the mapped DCEL.
+func (tn *Node) Locate(vs ...float64) (pointLoc.Location, error) {
+	if len(vs) < 2 {
+		return pointLoc.Location{}, compgeo.InsufficientDimensionsError{}
+	}
+	pt := geom.Point{vs[0], vs[1], 0}
+	info := tn.payload.(rootInfo)
+	outerFace, tol := info.outer, info.tol
+	cands := []*dcel.Face{}
+	// Queries on a trapezoid's left or right boundary are
+	// resolved to one side, so also query a short horizontal
+	// segment through pt to find the trapezoids on both.
+	eps := 2 * tol.Epsilon(vs[0])
+	trs := tn.Query(geom.FullEdge{pt, pt})
+	trs = append(trs, tn.Query(geom.FullEdge{
+		geom.Point{vs[0] - eps, vs[1], 0},
+		geom.Point{vs[0] + eps, vs[1], 0},
+	})...)
+	for _, tr := range trs {
+		for _, f := range tr.faces {
+			if f != outerFace {
+				cands = append(cands, f)
+			}
+		}
+	}
+	return pointLoc.Resolve(pt, cands, tol), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (tn *Node) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(tn, points)
+}
+
+// Query returns the trapezoids below tn which fe passes
+// through, from left to right.
 func (tn *Node) Query(fe geom.FullEdge) []*Trapezoid {
+	return tn.find(newSegment(fe[0], fe[1]))
+}
+
+// find is shorthand for tn.query(fe, tn)
+func (tn *Node) find(fe segment) []*Trapezoid {
 	if tn == nil {
 		return []*Trapezoid{}
 	}
@@ -168,10 +210,10 @@ func (tn *Node) payloadString() string {
 	switch v := tn.payload.(type) {
 	case *Trapezoid:
 		return "T" + v.String()
+	case segment:
+		return "Y" + "(" + printutil.Stringf64(v.FullEdge[0][0], v.FullEdge[0][1], v.FullEdge[1][0], v.FullEdge[1][1]) + ")"
 	case geom.D3:
 		return "X" + "(" + printutil.Stringf64(v.Val(0)) + ")"
-	case geom.FullEdge:
-		return "Y" + "(" + printutil.Stringf64(v[0][0], v[0][1], v[1][0], v[1][1]) + ")"
 	}
 	return "Root"
 }
@@ -186,6 +228,15 @@ func NewRoot() *Node {
 	}
 }
 
-func rootQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
-	return n.left.Query(fe)
+// rootInfo is the payload of a root node, recording what a
+// query needs to know about the map it was built from.
+type rootInfo struct {
+	dc    *dcel.DCEL
+	outer *dcel.Face
+	tol   geom.Tolerance
+	seed  int64
+}
+
+func rootQuery(fe segment, n *Node) []*Trapezoid {
+	return n.left.find(fe)
 }
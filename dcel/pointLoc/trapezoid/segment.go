@@ -0,0 +1,158 @@
+package trapezoid
+
+import (
+	"math/big"
+
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A segment is an edge of a mapped DCEL, holding its left
+// and right endpoints at their exact positions alongside the
+// float approximation the map's geometry is built from.
+// Every decision made while building or querying a map is
+// made on the exact endpoints.
+type segment struct {
+	geom.FullEdge
+	l, r geom.D3
+}
+
+func newSegment(p, q geom.D3) segment {
+	c := geom.CmpVal(p, q, 0)
+	if c > 0 || (c == 0 && geom.CmpVal(p, q, 1) > 0) {
+		p, q = q, p
+	}
+	return segment{
+		geom.FullEdge{
+			geom.NewPoint(p.X(), p.Y(), p.Z()),
+			geom.NewPoint(q.X(), q.Y(), q.Z()),
+		}, p, q,
+	}
+}
+
+// segments returns the edges of dc as segments, with the
+// faces on either side of each.
+func segments(dc *dcel.DCEL) ([]segment, [][2]*dcel.Face, error) {
+	_, faces, err := dc.FullEdges()
+	if err != nil {
+		return nil, nil, err
+	}
+	segs := make([]segment, len(faces))
+	for i := range segs {
+		e := dc.HalfEdges[2*i]
+		segs[i] = newSegment(e.Origin.Position(), e.Twin.Origin.Position())
+	}
+	return segs, faces, nil
+}
+
+// bounds returns the lower and upper corners of the box
+// around dc, exactly if any of its vertices is exact.
+func bounds(dc *dcel.DCEL) (geom.D3, geom.D3) {
+	b := dc.Bounds()
+	min := b.At(geom.SPAN_MIN).(geom.Point)
+	max := b.At(geom.SPAN_MAX).(geom.Point)
+	exact := false
+	for _, v := range dc.Vertices {
+		exact = exact || v.Exact != nil
+	}
+	if !exact {
+		return min, max
+	}
+	var lo, hi [3]*big.Rat
+	for i, v := range dc.Vertices {
+		for d := 0; d < 3; d++ {
+			r := geom.Rat(v.Position(), d)
+			if i == 0 || r.Cmp(lo[d]) < 0 {
+				lo[d] = r
+			}
+			if i == 0 || r.Cmp(hi[d]) > 0 {
+				hi[d] = r
+			}
+		}
+	}
+	return geom.NewRatPoint(lo[0], lo[1], lo[2]), geom.NewRatPoint(hi[0], hi[1], hi[2])
+}
+
+// corner returns the point with the x value of p and the
+// y value of q.
+func corner(p, q geom.D3) geom.D3 {
+	if isExact(p, q) {
+		return geom.NewRatPoint(geom.Rat(p, 0), geom.Rat(q, 1), new(big.Rat))
+	}
+	return geom.NewPoint(p.X(), q.Y(), 0)
+}
+
+// isExact returns whether any of ds has an exact position.
+func isExact(ds ...geom.D3) bool {
+	for _, d := range ds {
+		if _, ok := d.(geom.ExactDimensional); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// eqX returns whether p and q share an x value: exactly if
+// either is exact, and otherwise within tol.
+func eqX(p, q geom.D3, tol geom.Tolerance) bool {
+	if isExact(p, q) {
+		return geom.CmpVal(p, q, 0) == 0
+	}
+	return tol.F64eq(p.X(), q.X())
+}
+
+// eqPoint acts as eqX, in both x and y.
+func eqPoint(p, q geom.D3, tol geom.Tolerance) bool {
+	if isExact(p, q) {
+		return geom.CmpVal(p, q, 0) == 0 && geom.CmpVal(p, q, 1) == 0
+	}
+	return tol.F64eq(p.X(), q.X()) && tol.F64eq(p.Y(), q.Y())
+}
+
+// cmpSide returns 1 if p lies above the side s of some
+// trapezoid, -1 if it lies below it, and 0 if it lies on it.
+// y is the float height of s at p's x value, which is compared
+// against within tol if neither p nor s is exact.
+func cmpSide(p geom.D3, s segment, y float64, tol geom.Tolerance) int {
+	if isExact(p, s.l, s.r) {
+		return geom.HzOrient2D(p, s.l, s.r)
+	}
+	if tol.F64eq(p.Y(), y) {
+		return 0
+	}
+	if p.Y() > y {
+		return 1
+	}
+	return -1
+}
+
+// cmpSides returns the sign of the height of side a less that
+// of side b where they cross the vertical line through w. ya
+// and yb are those heights as floats, which are compared
+// instead if none of a, b or w is exact.
+func cmpSides(a, b segment, ya, yb float64, w geom.D3) int {
+	if isExact(a.l, a.r, b.l, b.r, w) {
+		x := geom.Rat(w, 0)
+		return yAt(a, x).Cmp(yAt(b, x))
+	}
+	if ya > yb {
+		return 1
+	} else if ya < yb {
+		return -1
+	}
+	return 0
+}
+
+// yAt returns the exact height of the line through s at x.
+func yAt(s segment, x *big.Rat) *big.Rat {
+	lx, ly := geom.Rat(s.l, 0), geom.Rat(s.l, 1)
+	dx := new(big.Rat).Sub(geom.Rat(s.r, 0), lx)
+	if dx.Sign() == 0 {
+		return ly
+	}
+	dy := new(big.Rat).Sub(geom.Rat(s.r, 1), ly)
+	t := new(big.Rat).Sub(x, lx)
+	t.Mul(t, dy)
+	t.Quo(t, dx)
+	return t.Add(t, ly)
+}
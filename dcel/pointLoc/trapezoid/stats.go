@@ -0,0 +1,59 @@
+package trapezoid
+
+import "github.com/200sc/go-compgeo/geom"
+
+// Stats describes the shape of a search structure.
+type Stats struct {
+	// Depth is the greatest number of x and y nodes
+	// on any path from the root to a trapezoid, and so
+	// bounds how many comparisons any query makes.
+	Depth int
+	// Nodes is the number of distinct nodes in the
+	// structure, of which XNodes are x nodes, YNodes
+	// are y nodes, and Trapezoids are leaves. Nodes
+	// are shared between paths, so this is less than
+	// the size of the structure as a tree.
+	Nodes, XNodes, YNodes, Trapezoids int
+	// Seed is the seed of the map's insertion order,
+	// if the structure was built by this package rather
+	// than decoded.
+	Seed int64
+}
+
+// Stats returns statistics on the search structure
+// rooted at tn.
+func (tn *Node) Stats() Stats {
+	st := Stats{}
+	if info, ok := tn.payload.(rootInfo); ok {
+		st.Seed = info.seed
+	}
+	depths := make(map[*Node]int)
+	var depth func(*Node) int
+	depth = func(n *Node) int {
+		if n == nil {
+			return 0
+		}
+		if d, ok := depths[n]; ok {
+			return d
+		}
+		d := depth(n.left)
+		if d2 := depth(n.right); d2 > d {
+			d = d2
+		}
+		switch n.payload.(type) {
+		case *Trapezoid:
+			st.Trapezoids++
+		case segment:
+			st.YNodes++
+			d++
+		case geom.D3:
+			st.XNodes++
+			d++
+		}
+		st.Nodes++
+		depths[n] = d
+		return d
+	}
+	st.Depth = depth(tn)
+	return st
+}
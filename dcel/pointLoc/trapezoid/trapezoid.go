@@ -4,7 +4,6 @@
 package trapezoid
 
 import (
-	"fmt"
 	"image/color"
 
 	"github.com/oakmound/oak/physics"
@@ -42,9 +41,14 @@ type Trapezoid struct {
 	top         [2]float64 // y values
 	bot         [2]float64 // y values
 	left, right float64    // x values
-	Neighbors   [4]*Trapezoid
-	node        *Node
-	faces       [2]*dcel.Face
+	// walls holds the points whose x values are left and
+	// right, and sides the edges along the top and bottom,
+	// at their exact positions.
+	walls     [2]geom.D3
+	sides     [2]segment
+	Neighbors [4]*Trapezoid
+	node      *Node
+	faces     [2]*dcel.Face
 }
 
 func (tr *Trapezoid) GetNeighbors() (*Trapezoid, *Trapezoid, *Trapezoid, *Trapezoid) {
@@ -55,13 +59,19 @@ func (tr *Trapezoid) GetNeighbors() (*Trapezoid, *Trapezoid, *Trapezoid, *Trapez
 // a trapezoid as DCElEdges with initialized origins,
 // prevs, and nexts.
 func (tr *Trapezoid) DCELEdges() []*dcel.Edge {
+	return tr.DCELEdgesWithin(geom.DefaultTolerance)
+}
+
+// DCELEdgesWithin acts as DCELEdges, but treats corners within
+// tolerance tol of one another as a single vertex.
+func (tr *Trapezoid) DCELEdgesWithin(tol geom.Tolerance) []*dcel.Edge {
 	edges := make([]*dcel.Edge, 1)
 	i := 0
 	edges[i] = dcel.NewEdge()
 	edges[i].Origin = dcel.PointToVertex(geom.NewPoint(tr.left, tr.top[left], 0))
 	edges[i].Origin.OutEdge = edges[i]
 	p := geom.NewPoint(tr.right, tr.top[right], 0)
-	if !p.Eq(edges[i].Origin) {
+	if !p.EqWithin(edges[i].Origin, tol) {
 		i++
 		edges = append(edges, dcel.NewEdge())
 		edges[i].Origin = dcel.PointToVertex(p)
@@ -70,7 +80,7 @@ func (tr *Trapezoid) DCELEdges() []*dcel.Edge {
 		edges[i].Prev = edges[i-1]
 	}
 	p = geom.NewPoint(tr.right, tr.bot[right], 0)
-	if !p.Eq(edges[i].Origin) {
+	if !p.EqWithin(edges[i].Origin, tol) {
 		i++
 		edges = append(edges, dcel.NewEdge())
 		edges[i].Origin = dcel.PointToVertex(p)
@@ -79,8 +89,8 @@ func (tr *Trapezoid) DCELEdges() []*dcel.Edge {
 		edges[i].Prev = edges[i-1]
 	}
 	p = geom.NewPoint(tr.left, tr.bot[left], 0)
-	if !p.Eq(edges[i].Origin) &&
-		!p.Eq(edges[0].Origin) {
+	if !p.EqWithin(edges[i].Origin, tol) &&
+		!p.EqWithin(edges[0].Origin, tol) {
 		i++
 		edges = append(edges, dcel.NewEdge())
 		edges[i].Origin = dcel.PointToVertex(p)
@@ -119,11 +129,25 @@ func (tr *Trapezoid) Copy() *Trapezoid {
 	tr2.bot = tr.bot
 	tr2.left = tr.left
 	tr2.right = tr.right
+	tr2.walls = tr.walls
+	tr2.sides = tr.sides
 	tr2.Neighbors = tr.Neighbors
 	tr2.faces = tr.faces
 	return tr2
 }
 
+// setLeft sets tr's left wall to pass through p.
+func (tr *Trapezoid) setLeft(p geom.D3) {
+	tr.left = p.X()
+	tr.walls[left] = p
+}
+
+// setRight sets tr's right wall to pass through p.
+func (tr *Trapezoid) setRight(p geom.D3) {
+	tr.right = p.X()
+	tr.walls[right] = p
+}
+
 // AsPoints converts a trapezoid's internal values
 // into four points.
 func (tr *Trapezoid) AsPoints() []geom.D2 {
@@ -178,16 +202,16 @@ func (tr *Trapezoid) String() string {
 	return s
 }
 
-func newTrapezoid(sp geom.Span) *Trapezoid {
+func newTrapezoid(min, max geom.D3) *Trapezoid {
 	t := new(Trapezoid)
-	min := sp.At(geom.SPAN_MIN).(geom.Point)
-	max := sp.At(geom.SPAN_MAX).(geom.Point)
 	t.top[left] = max.Y()
 	t.top[right] = max.Y()
 	t.bot[left] = min.Y()
 	t.bot[right] = min.Y()
-	t.left = min.X()
-	t.right = max.X()
+	t.setLeft(min)
+	t.setRight(max)
+	t.sides[top] = newSegment(corner(min, max), max)
+	t.sides[bot] = newSegment(min, corner(max, min))
 	t.Neighbors = [4]*Trapezoid{nil, nil, nil, nil}
 	return t
 }
@@ -202,7 +226,6 @@ func (tr *Trapezoid) toPhysics() []physics.Vector {
 
 func (tr *Trapezoid) visualize() {
 	if tr == nil {
-		fmt.Println("Nothing to visualize")
 		return
 	}
 	visualize.HighlightColor = visualize.AddFaceColor
@@ -247,25 +270,24 @@ func (tr *Trapezoid) replaceNeighbor(dir int, rep, new *Trapezoid) {
 
 // Assign the neighbors of the trapezoid tr's upleft and upright
 // neighbors (if they exist) dependant on tr being replaced by
-// the two trapezoids u and b split at y value lpy.
+// the two trapezoids u and b split at the point lp.
 //
 //  ~ ~ ~ ~ ~ ~
 //    ul |  u
 //  ~ ~ ~ -lpy-----
 //    bl |  b
 //  ~ ~ ~ ~ ~ ~
-func (tr *Trapezoid) replaceLeftPointers(u, b *Trapezoid, lpy float64) {
-	replaceLeftPointers(tr, tr.Neighbors[upleft], tr.Neighbors[botleft], u, b, lpy)
+func (tr *Trapezoid) replaceLeftPointers(u, b *Trapezoid, lp geom.D3, tol geom.Tolerance) {
+	replaceLeftPointers(tr, tr.Neighbors[upleft], tr.Neighbors[botleft], u, b, lp, tol)
 }
 
 // Given the trapezoid tr, being replaced by u and b where
-// u is above b and lpy is the point at which u and be connect
+// u is above b and lp is the point at which u and be connect
 // on tr's left edge, assign all pointers from ul and bl where ul
 // is above bl that previously pointed to tr to the appropriate
 // trapezoid of u and b.
-func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
-	if ul != nil && geom.F64eq(ul.bot[right], lpy) {
-		fmt.Println("Case 0")
+func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lp geom.D3, tol geom.Tolerance) {
+	if ul != nil && cmpSide(lp, ul.sides[bot], ul.bot[right], tol) == 0 {
 		// U matches exactly to ul,
 		// B matches exactly to bl.
 		//
@@ -277,9 +299,8 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 		//
 		ul.replaceNeighbors(tr, u)
 		bl.replaceNeighbors(tr, b)
-	} else if (ul != nil && geom.F64eq(ul.top[right], lpy)) ||
-		(ul == nil && bl != nil && geom.F64eq(bl.top[right], lpy)) {
-		fmt.Println("Case 1")
+	} else if (ul != nil && cmpSide(lp, ul.sides[top], ul.top[right], tol) == 0) ||
+		(ul == nil && bl != nil && cmpSide(lp, bl.sides[top], bl.top[right], tol) == 0) {
 		// U does not border the left edge
 		//
 		// ~ ~ ~ lpy \
@@ -300,9 +321,8 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 			b.Neighbors[botleft] = ul
 		}
 		u.Lefts(b)
-	} else if (bl != nil && geom.F64eq(bl.bot[right], lpy)) ||
-		(bl == nil && ul != nil && geom.F64eq(ul.bot[right], lpy)) {
-		fmt.Println("Case 2")
+	} else if (bl != nil && cmpSide(lp, bl.sides[bot], bl.bot[right], tol) == 0) ||
+		(bl == nil && ul != nil && cmpSide(lp, ul.sides[bot], ul.bot[right], tol) == 0) {
 		// D does not border the left edge
 		//
 		// ~ ~ ~ ~ ~ ~ ~ ~
@@ -324,8 +344,7 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 			u.Neighbors[upleft] = bl
 		}
 		b.Lefts(u)
-	} else if ul != nil && ul.bot[right] < lpy {
-		fmt.Println("Case 3")
+	} else if ul != nil && cmpSide(lp, ul.sides[bot], ul.bot[right], tol) > 0 {
 		// UL expands past FE
 		//
 		// ~ ~ ~ ~ ~ ~ ~ ~ ~
@@ -343,8 +362,7 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 		u.Lefts(ul)
 		b.Neighbors[upleft] = ul
 		b.Neighbors[botleft] = bl
-	} else if bl != nil && bl.top[right] > lpy {
-		fmt.Println("Case 4")
+	} else if bl != nil && cmpSide(lp, bl.sides[top], bl.top[right], tol) < 0 {
 		// BL expands past FE
 		//
 		// ~ ~ ~ ~ ~ ~ ~ ~ ~
@@ -362,12 +380,6 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 		b.Lefts(bl)
 		u.Neighbors[upleft] = ul
 		u.Neighbors[botleft] = bl
-	} else {
-		fmt.Println("No left pointer case satisfied.")
-		fmt.Println("Both neighbors nil:", ul == nil && bl == nil)
-		fmt.Println(ul, bl)
-		fmt.Println(u, b)
-		fmt.Println(lpy)
 	}
 }
 
@@ -376,13 +388,12 @@ func replaceLeftPointers(tr, ul, bl, u, b *Trapezoid, lpy float64) {
 //  --rpy ~ ~ ~
 //    b |  br
 //  ~ ~ ~ ~ ~ ~
-func (tr *Trapezoid) replaceRightPointers(u, b *Trapezoid, rpy float64) {
-	replaceRightPointers(tr, tr.Neighbors[upright], tr.Neighbors[botright], u, b, rpy)
+func (tr *Trapezoid) replaceRightPointers(u, b *Trapezoid, rp geom.D3, tol geom.Tolerance) {
+	replaceRightPointers(tr, tr.Neighbors[upright], tr.Neighbors[botright], u, b, rp, tol)
 }
 
-func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
-	if ur != nil && geom.F64eq(ur.bot[left], rpy) {
-		fmt.Println("Case 0")
+func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rp geom.D3, tol geom.Tolerance) {
+	if ur != nil && cmpSide(rp, ur.sides[bot], ur.bot[left], tol) == 0 {
 		// U matches exactly to ur,
 		// B matches exactly to br.
 		//
@@ -394,9 +405,8 @@ func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
 		//
 		ur.replaceNeighbors(tr, u)
 		br.replaceNeighbors(tr, b)
-	} else if (ur != nil && geom.F64eq(ur.top[left], rpy)) ||
-		(ur == nil && br != nil && geom.F64eq(br.top[left], rpy)) {
-		fmt.Println("Right case 1")
+	} else if (ur != nil && cmpSide(rp, ur.sides[top], ur.top[left], tol) == 0) ||
+		(ur == nil && br != nil && cmpSide(rp, br.sides[top], br.top[left], tol) == 0) {
 		// U does not border the right edge
 		//
 		//  ~ ~ rpy ~ ~ ~
@@ -418,9 +428,8 @@ func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
 		} else {
 			b.Neighbors[botright] = ur
 		}
-	} else if (br != nil && geom.F64eq(br.bot[left], rpy)) ||
-		(br == nil && ur != nil && geom.F64eq(ur.bot[left], rpy)) {
-		fmt.Println("Right case 2")
+	} else if (br != nil && cmpSide(rp, br.sides[bot], br.bot[left], tol) == 0) ||
+		(br == nil && ur != nil && cmpSide(rp, ur.sides[bot], ur.bot[left], tol) == 0) {
 		//
 		//  ~ ~ rpy ~ ~ ~
 		//  \   \    ur
@@ -441,8 +450,7 @@ func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
 		} else {
 			u.Neighbors[botright] = ur
 		}
-	} else if ur != nil && ur.bot[left] < rpy {
-		fmt.Println("Right case 3")
+	} else if ur != nil && cmpSide(rp, ur.sides[bot], ur.bot[left], tol) > 0 {
 		// UR expands past FE
 		//
 		// ~ ~ ~ ~ ~ ~ ~ ~ ~
@@ -460,8 +468,7 @@ func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
 		u.Rights(ur)
 		b.Neighbors[upright] = ur
 		b.Neighbors[botright] = br
-	} else if br != nil && br.top[left] > rpy {
-		fmt.Println("Right case 4")
+	} else if br != nil && cmpSide(rp, br.sides[top], br.top[left], tol) < 0 {
 		// BR expands past FE
 		//
 		// ~ ~ ~ ~ ~ ~ ~ ~ ~
@@ -479,66 +486,63 @@ func replaceRightPointers(tr, ur, br, u, b *Trapezoid, rpy float64) {
 		b.Rights(br)
 		u.Neighbors[upright] = ur
 		u.Neighbors[botright] = br
-	} else {
-		fmt.Println("No right pointer case satisfied")
-		fmt.Println("Both neighbors nil:", br == nil && ur == nil)
 	}
 }
 
-func (tr *Trapezoid) twoRights(u, b *Trapezoid, lpy float64) {
+func (tr *Trapezoid) twoRights(u, b *Trapezoid, lp geom.D3, tol geom.Tolerance) {
 	tr.Neighbors[upright] = u
 	tr.Neighbors[botright] = b
-	if geom.F64eq(tr.top[right], lpy) {
+	if cmpSide(lp, tr.sides[top], tr.top[right], tol) == 0 {
 		tr.Neighbors[upright] = b
-	} else if geom.F64eq(tr.bot[right], lpy) {
+	} else if cmpSide(lp, tr.sides[bot], tr.bot[right], tol) == 0 {
 		tr.Neighbors[botright] = u
 	}
 	u.Lefts(tr)
 	b.Lefts(tr)
 }
 
-func (tr *Trapezoid) twoLefts(u, b *Trapezoid, rpy float64) {
+func (tr *Trapezoid) twoLefts(u, b *Trapezoid, rp geom.D3, tol geom.Tolerance) {
 	tr.Neighbors[upleft] = u
 	tr.Neighbors[botleft] = b
-	if geom.F64eq(tr.top[left], rpy) {
+	if cmpSide(rp, tr.sides[top], tr.top[left], tol) == 0 {
 		tr.Neighbors[upleft] = b
-	} else if geom.F64eq(tr.bot[left], rpy) {
+	} else if cmpSide(rp, tr.sides[bot], tr.bot[left], tol) == 0 {
 		tr.Neighbors[botleft] = u
 	}
 	u.Rights(tr)
 	b.Rights(tr)
 }
 
-func splitExactly(u, d *Trapezoid, fe geom.FullEdge) {
-	u.exactly(top, fe)
-	d.exactly(bot, fe)
+func splitExactly(u, d *Trapezoid, s segment) {
+	u.exactly(top, s)
+	d.exactly(bot, s)
 }
 
-func (tr *Trapezoid) exactly(d int, fe geom.FullEdge) {
-	lp := fe.Left()
-	rp := fe.Right()
-	tr.left = lp.X()
-	tr.right = rp.X()
+func (tr *Trapezoid) exactly(d int, s segment) {
+	lp := s.Left()
+	rp := s.Right()
+	tr.setLeft(s.l)
+	tr.setRight(s.r)
 	if d == bot {
 		tr.top[left] = lp.Y()
 		tr.top[right] = rp.Y()
+		tr.sides[top] = s
 	} else if d == top {
 		tr.bot[left] = lp.Y()
 		tr.bot[right] = rp.Y()
+		tr.sides[bot] = s
 	}
 }
 
 func annotatedVisualize(strs []string, trs []*Trapezoid) {
-	for i, s := range strs {
-		fmt.Println("Visualizing " + s)
+	for i := range strs {
 		trs[i].visualize()
 		//trs[i].visualizeNeighbors()
-		fmt.Print(s + " visualized.")
 	}
-	fmt.Println("")
 }
 
-func (tr *Trapezoid) setBotleft(fe geom.FullEdge) {
+func (tr *Trapezoid) setBotleft(s segment) {
+	fe := s.FullEdge
 	r := tr.right
 	if r > fe.Right().X() {
 		r = fe.Right().X()
@@ -550,9 +554,11 @@ func (tr *Trapezoid) setBotleft(fe geom.FullEdge) {
 	edge, _ := fe.SubEdge(0, l, r)
 	tr.bot[left] = edge.Left().Y()
 	tr.bot[right] = edge.Right().Y()
+	tr.sides[bot] = s
 }
 
-func (tr *Trapezoid) setTopleft(fe geom.FullEdge) {
+func (tr *Trapezoid) setTopleft(s segment) {
+	fe := s.FullEdge
 	r := tr.right
 	if r > fe.Right().X() {
 		r = fe.Right().X()
@@ -564,4 +570,5 @@ func (tr *Trapezoid) setTopleft(fe geom.FullEdge) {
 	edge, _ := fe.SubEdge(0, l, r)
 	tr.top[left] = edge.Left().Y()
 	tr.top[right] = edge.Right().Y()
+	tr.sides[top] = s
 }
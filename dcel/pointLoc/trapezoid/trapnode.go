@@ -17,15 +17,14 @@ func NewTrapNode(tr *Trapezoid) *Node {
 	return node
 }
 
-func trapQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
+func trapQuery(fe segment, n *Node) []*Trapezoid {
 	tr := n.payload.(*Trapezoid)
 	traps := []*Trapezoid{tr}
 	if visualize.VisualCh != nil {
 		visualize.HighlightColor = color.RGBA{0, 0, 128, 128}
 		visualize.DrawPoly(tr.toPhysics())
 	}
-	r := fe.Right()
-	for tr != nil && r.X() > tr.right {
+	for tr != nil && geom.CmpVal(fe.r, tr.walls[right], 0) > 0 {
 		// We perform this check here is it is less expensive
 		// than the cross product in the latter case, even
 		// though the latter case would suffice to do this.
@@ -38,17 +37,19 @@ func trapQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
 			// the bottom trapezoid.
 			// For this aboveness check we just use the left endpoint
 			// of the separating edge, as we know that is within fe's
-			// horizontal span.
-			if geom.IsAbove(
-				tr.Neighbors[upright].BotEdge().Left(), fe.Left(), fe.Right()) {
+			// horizontal span. That endpoint is the point through
+			// tr's right wall.
+			if geom.IsAbove(tr.walls[right], fe.l, fe.r) {
 				tr = tr.Neighbors[botright]
 			} else {
 				tr = tr.Neighbors[upright]
 			}
 		}
 		if tr != nil {
-			visualize.HighlightColor = visualize.CheckFaceColor
-			visualize.DrawPoly(tr.toPhysics())
+			if visualize.VisualCh != nil {
+				visualize.HighlightColor = visualize.CheckFaceColor
+				visualize.DrawPoly(tr.toPhysics())
+			}
 			traps = append(traps, tr)
 		}
 	}
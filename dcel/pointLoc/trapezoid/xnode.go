@@ -7,25 +7,33 @@ import (
 	"github.com/200sc/go-compgeo/geom"
 )
 
-// NewX returns an X-Node at point P
+// NewX returns an X-Node at point P.
 func NewX(p geom.D3) *Node {
+	return NewXWithin(p, geom.DefaultTolerance)
+}
+
+// NewXWithin returns an X-Node at point P, which treats
+// queries within tolerance tol of P as on P.
+func NewXWithin(p geom.D3, tol geom.Tolerance) *Node {
 	return &Node{
-		query:   xQuery,
+		query: func(fe segment, n *Node) []*Trapezoid {
+			return xQuery(fe, n, tol)
+		},
 		payload: p,
 	}
 }
 
-func xQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
-	p := n.payload.(geom.Point)
+func xQuery(fe segment, n *Node, tol geom.Tolerance) []*Trapezoid {
+	p := n.payload.(geom.D3)
 	if visualize.VisualCh != nil {
 		visualize.HighlightColor = color.RGBA{128, 128, 128, 128}
 		visualize.DrawVerticalLine(p)
 	}
-	if geom.F64eq(fe.Left().X(), p.X()) {
+	if eqX(fe.l, p, tol) {
 		// If equal, go right.
-		return n.right.Query(fe)
-	} else if fe.Left().X() < p.X() {
-		return n.left.Query(fe)
+		return n.right.find(fe)
+	} else if geom.CmpVal(fe.l, p, 0) < 0 {
+		return n.left.find(fe)
 	}
-	return n.right.Query(fe)
+	return n.right.find(fe)
 }
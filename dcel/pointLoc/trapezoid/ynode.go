@@ -9,34 +9,43 @@ import (
 
 // NewY returns a Y-Node at edge e
 func NewY(e geom.FullEdge) *Node {
+	return newY(newSegment(e[0], e[1]))
+}
+
+func newY(s segment) *Node {
 	return &Node{
 		query:   yQuery,
-		payload: e,
+		payload: s,
 	}
 }
 
-func yQuery(fe geom.FullEdge, n *Node) []*Trapezoid {
+func yQuery(fe segment, n *Node) []*Trapezoid {
 	// This query asks if fe.Left() is above or below
 	// yn.FullEdge.
 	// If they are colinear, however, we need to check
 	// which slope is larger. If fe is larger, we go above,
 	// else we go below.
-	yn := n.payload.(geom.FullEdge)
+	yn := n.payload.(segment)
 	if visualize.VisualCh != nil {
 		visualize.HighlightColor = color.RGBA{128, 128, 128, 128}
 		visualize.DrawLine(yn.Left(), yn.Right())
 	}
-	cp := geom.HzCross2D(fe.Left(), yn.Left(), yn.Right())
+	cp := geom.HzOrient2D(fe.l, yn.l, yn.r)
+	if cp == 0 {
+		// The colinear case, in which fe's slope is larger
+		// exactly when its right end is above yn.
+		cp = geom.HzOrient2D(fe.r, yn.l, yn.r)
+	}
 	if cp > 0 {
-		return n.left.Query(fe)
+		return n.left.find(fe)
 	} else if cp < 0 {
-		return n.right.Query(fe)
+		return n.right.find(fe)
 	}
-	// The colinear case
+	// fe is a point on yn, or lies along it
 	s1 := fe.Slope()
 	s2 := yn.Slope()
 	if s1 > s2 {
-		return n.left.Query(fe)
+		return n.left.find(fe)
 	}
-	return n.right.Query(fe)
+	return n.right.find(fe)
 }
@@ -0,0 +1,293 @@
+// walk implements point location by walking from face
+// to face across a DCEL toward the query point.
+
+package walk
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/dcel"
+	"github.com/200sc/go-compgeo/dcel/pointLoc"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Walker locates points by jump and walk: it jumps to the
+// nearest of a few sampled faces, or the face it located last,
+// then walks toward the query, crossing any edge of the current
+// face which the query lies beyond. It needs no preprocessing,
+// and walks are short when queries are near one another, as
+// when following a cursor.
+//
+// Faces must be convex, as in a triangulation, for walks to
+// reach the query. The edges of each face are checked in a
+// random order, so that walks cannot cycle forever, and
+// a walk which runs too long falls back to checking every face.
+//
+// A Walker is safe for concurrent queries.
+type Walker struct {
+	// queries counts the walks begun, each of which draws
+	// from its own source.
+	queries uint64
+	seed    uint64
+	dc      *dcel.DCEL
+	tol     geom.Tolerance
+	// Samples is how many faces are sampled to start
+	// a walk from. If it is zero, the cube root of the
+	// number of faces is used.
+	Samples int
+	// last is a *dcel.Edge bounding the last face located.
+	last atomic.Value
+}
+
+// New returns a Walker over dc. Its choices are drawn from
+// a source seeded by math/rand.
+func New(dc *dcel.DCEL) *Walker {
+	return NewWithin(dc, geom.DefaultTolerance)
+}
+
+// NewWithin acts as New, but treats query points within
+// tolerance tol of a vertex or edge as lying on it.
+func NewWithin(dc *dcel.DCEL, tol geom.Tolerance) *Walker {
+	return NewSeededWithin(dc, tol, rand.Int63())
+}
+
+// NewSeeded returns a Walker over dc whose choices are
+// drawn from a source with the given seed.
+func NewSeeded(dc *dcel.DCEL, seed int64) *Walker {
+	return NewSeededWithin(dc, geom.DefaultTolerance, seed)
+}
+
+// NewSeededWithin acts as NewSeeded, with the tolerance
+// of NewWithin.
+func NewSeededWithin(dc *dcel.DCEL, tol geom.Tolerance, seed int64) *Walker {
+	return &Walker{dc: dc, tol: tol, seed: uint64(seed)}
+}
+
+// source returns the random source for a new walk. Each
+// walk has its own, so that concurrent walks do not contend
+// over one, and is seeded by w's seed and how many walks
+// came before it.
+func (w *Walker) source() *source {
+	n := atomic.AddUint64(&w.queries, 1)
+	s := source(w.seed + n*golden)
+	s = source(s.next())
+	return &s
+}
+
+// golden is the increment of splitmix64.
+const golden = 0x9e3779b97f4a7c15
+
+// A source is a splitmix64 random source. It is much cheaper
+// to seed than a math/rand source, so one can be made for
+// every walk.
+type source uint64
+
+func (s *source) next() uint64 {
+	*s += golden
+	z := uint64(*s)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// intn returns a number in [0, n).
+func (s *source) intn(n int) int {
+	return int(s.next() % uint64(n))
+}
+
+// PointLocate returns which face of w's DCEL contains the
+// query point, if any.
+func (w *Walker) PointLocate(vs ...float64) (*dcel.Face, error) {
+	if len(vs) < 2 {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	rng := w.source()
+	f := w.walk(w.start(p, rng), p, rng)
+	return f, nil
+}
+
+// WalkFrom acts as PointLocate, but walks from start instead
+// of a sampled face.
+func (w *Walker) WalkFrom(start *dcel.Face, vs ...float64) (*dcel.Face, error) {
+	if len(vs) < 2 {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	if start == nil || start.Outer == nil {
+		return nil, compgeo.BadDCELError{}
+	}
+	return w.walk(start.Outer, geom.NewPoint(vs[0], vs[1], 0), w.source()), nil
+}
+
+// Locate returns where the query point lies among the
+// vertices, edges, and faces of w's DCEL.
+func (w *Walker) Locate(vs ...float64) (pointLoc.Location, error) {
+	f, err := w.PointLocate(vs...)
+	if err != nil {
+		return pointLoc.Location{}, err
+	}
+	p := geom.NewPoint(vs[0], vs[1], 0)
+	cands := []*dcel.Face{f}
+	if f != nil {
+		// Points on f's boundary may also be reported on
+		// the boundary of a neighbor.
+		for _, e := range f.Outer.EdgeChain() {
+			cands = append(cands, e.Face, e.Twin.Face)
+		}
+	} else {
+		cands = w.dc.Faces[1:]
+	}
+	return pointLoc.Resolve(p, cands, w.tol), nil
+}
+
+// LocateAll point locates each of points concurrently,
+// returning the face and error for points[i] at index i.
+func (w *Walker) LocateAll(points []geom.D2) ([]*dcel.Face, []error) {
+	return pointLoc.LocateAll(w, points)
+}
+
+// start returns an edge on the boundary of the face to
+// start a walk to p from, sampling faces with rng.
+func (w *Walker) start(p geom.D2, rng *source) *dcel.Edge {
+	inner := w.dc.Faces[1:]
+	if len(inner) == 0 {
+		return nil
+	}
+	k := w.Samples
+	if k <= 0 {
+		k = int(math.Ceil(math.Cbrt(float64(len(inner)))))
+	}
+	var best *dcel.Edge
+	bestD := math.Inf(1)
+	try := func(e *dcel.Edge) {
+		if e == nil {
+			return
+		}
+		dx, dy := e.Origin.X()-p.X(), e.Origin.Y()-p.Y()
+		if d := dx*dx + dy*dy; d < bestD {
+			best, bestD = e, d
+		}
+	}
+	if last, ok := w.last.Load().(*dcel.Edge); ok {
+		try(last)
+	}
+	for i := 0; i < k; i++ {
+		try(inner[rng.intn(len(inner))].Outer)
+	}
+	return best
+}
+
+// walk walks from the face bounded by the chain of e to
+// the face containing p, ordering the edges checked on each
+// step with rng.
+func (w *Walker) walk(e *dcel.Edge, p geom.D2, rng *source) *dcel.Face {
+	if e == nil {
+		return nil
+	}
+	s, n := chainSign(e)
+	maxSteps := 2*len(w.dc.Faces) + 8
+	for steps := 0; steps < maxSteps; steps++ {
+		e2 := e
+		for off := rng.intn(n); off > 0; off-- {
+			e2 = e2.Next
+		}
+		crossed := false
+		for i := 0; i < n; i, e2 = i+1, e2.Next {
+			// p is beyond e2 if it is on the side of e2
+			// away from the face's interior.
+			if geom.Orient2D(e2.Origin, e2.Twin.Origin, p)*s >= 0 {
+				continue
+			}
+			t := e2.Twin
+			ts, tn := chainSign(t)
+			if ts != s {
+				// A face's neighbor lies on the opposite side of
+				// their shared edge, so its chain has the same
+				// orientation. Only the outer boundaries of
+				// enclosing faces run the other way.
+				if chainFace(t) == w.dc.Faces[dcel.OUTER_FACE] {
+					return nil
+				}
+				return w.scan(p)
+			}
+			e, n = t, tn
+			crossed = true
+			break
+		}
+		if !crossed {
+			w.last.Store(e)
+			return chainFace(e)
+		}
+	}
+	// The faces were not convex, so fall back to
+	// checking every face
+	return w.scan(p)
+}
+
+// scan returns the first inner face containing p.
+func (w *Walker) scan(p geom.D2) *dcel.Face {
+	for _, f := range w.dc.Faces[1:] {
+		if f.Contains(p) {
+			return f
+		}
+	}
+	return nil
+}
+
+// chainSign returns 1 if the chain of e runs counterclockwise,
+// and -1 otherwise, and the number of edges in the chain.
+func chainSign(e *dcel.Edge) (int, int) {
+	a := 0.0
+	n := 0
+	e2 := e
+	for {
+		a += e2.Origin.X()*e2.Twin.Origin.Y() - e2.Twin.Origin.X()*e2.Origin.Y()
+		n++
+		e2 = e2.Next
+		if e2 == e {
+			break
+		}
+	}
+	if a < 0 {
+		return -1, n
+	}
+	return 1, n
+}
+
+// chainFace returns the face whose boundary is the chain of e.
+// The face pointers of edges are not trusted, as some DCELs
+// set them to the face on the other side of the edge.
+func chainFace(e *dcel.Edge) *dcel.Face {
+	e2 := e
+	for {
+		for _, f := range []*dcel.Face{e2.Face, e2.Twin.Face} {
+			if f != nil && (inChain(e, f.Outer) || inChain(e, f.Inner)) {
+				return f
+			}
+		}
+		e2 = e2.Next
+		if e2 == e {
+			return e.Face
+		}
+	}
+}
+
+// inChain returns whether e2 is in the chain of e.
+func inChain(e, e2 *dcel.Edge) bool {
+	if e2 == nil {
+		return false
+	}
+	e3 := e
+	for {
+		if e3 == e2 {
+			return true
+		}
+		e3 = e3.Next
+		if e3 == e {
+			return false
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package dcel
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/200sc/go-compgeo/geom"
@@ -125,11 +126,52 @@ func FourPoint(p1, p2, p3, p4 geom.D3) *DCEL {
 	return dc
 }
 
-func goodrandf64() float64 {
-	return (rand.Float64() * (8.0 / 10.0)) + .1
+// randSource is the part of a *rand.Rand that random
+// DCELs are built with, so that they may be built either
+// from the global source or from a seed.
+type randSource interface {
+	Intn(int) int
+	Float64() float64
 }
 
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int   { return rand.Intn(n) }
+func (globalRand) Float64() float64 { return rand.Float64() }
+
+// onLine returns whether both endpoints of e2 lie on the
+// line through e1.
+func onLine(e1, e2 *Edge) bool {
+	a, b := e1.Origin, e1.Twin.Origin
+	l := math.Hypot(b.X()-a.X(), b.Y()-a.Y())
+	for _, c := range []*Vertex{e2.Origin, e2.Twin.Origin} {
+		if math.Abs(geom.Cross2D(a, b, c)) > geom.DefaultTolerance.Abs*l {
+			return false
+		}
+	}
+	return true
+}
+
+func goodrandf64(rng randSource) float64 {
+	return (rng.Float64() * (8.0 / 10.0)) + .1
+}
+
+// Random2DDCEL returns a size by size square DCEL, split
+// splits times by edges between random points on the
+// boundary of a random face. It draws from the global
+// math/rand source.
 func Random2DDCEL(size float64, splits int) *DCEL {
+	return random2DDCEL(globalRand{}, size, splits)
+}
+
+// Random2DDCELSeeded acts as Random2DDCEL, but draws from
+// its own source with the given seed, so that the same
+// arguments always give the same DCEL.
+func Random2DDCELSeeded(size float64, splits int, seed int64) *DCEL {
+	return random2DDCEL(rand.New(rand.NewSource(seed)), size, splits)
+}
+
+func random2DDCEL(rng randSource, size float64, splits int) *DCEL {
 	// Generate a bounding box as a DCEL with one face
 	dc := FourPoint(
 		geom.NewPoint(0, 0, 0),
@@ -141,7 +183,7 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 
 	for i := 0; i < splits; i++ {
 		// choose a random face of the dcel
-		fi := rand.Intn(len(dc.Faces)-1) + 1
+		fi := rng.Intn(len(dc.Faces)-1) + 1
 		f := dc.Faces[fi]
 		// fmt.Println("Face", fi, f)
 		// choose two random edges of that face
@@ -155,9 +197,12 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 				break
 			}
 		}
-		r1 := rand.Intn(len(edges))
-		r2 := rand.Intn(len(edges))
-		if r2 == r1 {
+		r1 := rng.Intn(len(edges))
+		r2 := rng.Intn(len(edges))
+		// Splitting edges leaves pieces of one line in the same
+		// face, and joining two of those would make a face with
+		// no area, so we skip to an edge off e1's line.
+		for r2 == r1 || onLine(edges[r1], edges[r2]) {
 			r2 = (r2 + 1) % len(edges)
 		}
 		e1 := edges[r1]
@@ -167,8 +212,8 @@ func Random2DDCEL(size float64, splits int) *DCEL {
 		// On each edge choose a random point
 		// We add some correction on this randomness
 		// so that we avoid having extremely small faces
-		v1 := PointToVertex(e1.PointAlong(0, goodrandf64()))
-		v2 := PointToVertex(e2.PointAlong(0, goodrandf64()))
+		v1 := PointToVertex(e1.PointAlong(0, goodrandf64(rng)))
+		v2 := PointToVertex(e2.PointAlong(0, goodrandf64(rng)))
 		// Add new vertices to dc at p1 and p2,
 		dc.Vertices = append(dc.Vertices, v1, v2)
 		// Split e1 and e2 and their twins at v1 and v2
@@ -0,0 +1,52 @@
+package dcel
+
+import "github.com/200sc/go-compgeo/geom"
+
+// Transform applies m to every vertex in the DCEL.
+// If m reverses orientation, every chain in the DCEL
+// is reversed as well, so faces keep the directionality
+// CorrectDirectionalityAll expects of them.
+// Exact vertex positions are discarded.
+func (dc *DCEL) Transform(m geom.Affine) {
+	for _, v := range dc.Vertices {
+		v.Point = m.Transform(v.Position())
+		v.Exact = nil
+	}
+	if m.Det() < 0 {
+		dc.reverseChains()
+	}
+}
+
+// reverseChains reverses the direction of every half
+// edge in the DCEL, keeping each on its original face.
+func (dc *DCEL) reverseChains() {
+	origins := make([]*Vertex, len(dc.HalfEdges))
+	for i, e := range dc.HalfEdges {
+		origins[i] = e.Twin.Origin
+	}
+	for i, e := range dc.HalfEdges {
+		e.Origin = origins[i]
+		e.Next, e.Prev = e.Prev, e.Next
+	}
+	// Each vertex's old out edge now points into it.
+	for _, v := range dc.Vertices {
+		if v.OutEdge != nil {
+			v.OutEdge = v.OutEdge.Twin
+		}
+	}
+}
+
+// Project returns the position of each of dc's vertices under
+// pr, in the same order as dc.Vertices. The DCEL itself is
+// not modified.
+func (dc *DCEL) Project(pr geom.Projection) ([]geom.Point, error) {
+	pts := make([]geom.Point, len(dc.Vertices))
+	for i, v := range dc.Vertices {
+		p, err := pr.Project(v.Position())
+		if err != nil {
+			return nil, err
+		}
+		pts[i] = p
+	}
+	return pts, nil
+}
@@ -0,0 +1,70 @@
+package dcel
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	dc := Rect(0, 0, 2, 1)
+	dc.Vertices[2].Exact = geom.NewRatPoint(big.NewRat(2, 1), big.NewRat(1, 1), new(big.Rat))
+	// Translating, then scaling
+	dc.Transform(geom.Translate2(1, 2).Scale(3, 1))
+	assert.Equal(t, geom.NewPoint(3, 2, 0), dc.Vertices[0].Point)
+	assert.Equal(t, geom.NewPoint(9, 3, 0), dc.Vertices[2].Point)
+	assert.Nil(t, dc.Vertices[2].Exact)
+
+	// Mirroring reverses orientation, so every chain is
+	// reversed to keep faces counterclockwise.
+	before := make(map[*Edge]*Vertex)
+	for _, e := range dc.HalfEdges {
+		before[e] = e.Origin
+	}
+	clock, err := dc.Faces[1].Outer.IsClockwise()
+	assert.Nil(t, err)
+	dc.Transform(geom.Scale2(-1, 1))
+	assert.Equal(t, geom.NewPoint(-3, 2, 0), dc.Vertices[0].Point)
+	clock2, err := dc.Faces[1].Outer.IsClockwise()
+	assert.Nil(t, err)
+	assert.Equal(t, clock, clock2)
+	for _, e := range dc.HalfEdges {
+		assert.Equal(t, before[e.Twin], e.Origin)
+		assert.Equal(t, e.Twin.Origin, e.Next.Origin)
+		assert.Equal(t, e, e.Next.Prev)
+		assert.Equal(t, e.Face, e.Next.Face)
+	}
+	for _, v := range dc.Vertices {
+		assert.Equal(t, v, v.OutEdge.Origin)
+	}
+	assert.True(t, dc.Faces[1].Contains(geom.NewPoint(-4, 2.5, 0)))
+}
+
+func TestProject(t *testing.T) {
+	dc := Rect(0, 0, 2, 1)
+	dc.Vertices[2].Exact = geom.NewRatPoint(big.NewRat(5, 2), big.NewRat(1, 1), new(big.Rat))
+	// A quarter turn about z, doubled in size
+	o := geom.NewOrthographic(geom.AxisAngle(geom.NewPoint(0, 0, 1), math.Pi/2), 2)
+	pts, err := dc.Project(o)
+	assert.Nil(t, err)
+	assert.Equal(t, len(dc.Vertices), len(pts))
+	assert.True(t, pts[1].EqWithin(geom.NewPoint(0, 4, 0), geom.DefaultTolerance))
+	// Exact positions are projected
+	assert.True(t, pts[2].EqWithin(geom.NewPoint(-2, 5, 0), geom.DefaultTolerance))
+	// The DCEL itself is not moved
+	assert.Equal(t, geom.NewPoint(2, 1, 0), dc.Vertices[2].Point)
+	assert.NotNil(t, dc.Vertices[2].Exact)
+
+	pr := geom.NewPerspective(geom.IdentityQuaternion(), 2, 1)
+	pts, err = dc.Project(pr)
+	assert.Nil(t, err)
+	assert.True(t, pts[2].EqWithin(geom.NewPoint(1.25, .5, 2), geom.DefaultTolerance))
+	dc.Vertices[3].Point[2] = -2
+	pts, err = dc.Project(pr)
+	assert.Equal(t, compgeo.RangeError{}, err)
+	assert.Nil(t, pts)
+}
@@ -9,25 +9,49 @@ import (
 type Vertex struct {
 	geom.Point
 	OutEdge *Edge
+	// Exact, if not nil, holds the exact position of this
+	// vertex, of which Point is a float64 approximation.
+	Exact geom.ExactDimensional
 }
 
 // NewVertex returns a Vertex at a given position with no
 // outEdge
 func NewVertex(x, y, z float64) *Vertex {
 	return &Vertex{
-		geom.Point{x, y, z},
-		nil,
+		Point: geom.Point{x, y, z},
 	}
 }
 
-// Add adds to the point behind a vertex
+// NewExactVertex returns a Vertex at the exact position p
+// with no outEdge.
+func NewExactVertex(p geom.ExactDimensional) *Vertex {
+	return &Vertex{
+		Point: geom.Point{p.Val(0), p.Val(1), p.Val(2)},
+		Exact: p,
+	}
+}
+
+// Position returns v's exact position if it has one,
+// and otherwise its Point.
+func (v *Vertex) Position() geom.D3 {
+	if d3, ok := v.Exact.(geom.D3); ok {
+		return d3
+	}
+	return v.Point
+}
+
+// Add adds to the point behind a vertex.
+// This discards any exact position the vertex had.
 func (v *Vertex) Add(d int, f float64) {
 	v.Point[d] += f
+	v.Exact = nil
 }
 
-// Mult multiplies the point behind a vertex
+// Mult multiplies the point behind a vertex.
+// This discards any exact position the vertex had.
 func (v *Vertex) Mult(d int, f float64) {
 	v.Point[d] *= f
+	v.Exact = nil
 }
 
 // AllEdges iterates through the edges surrounding
@@ -56,6 +80,13 @@ func (v *Vertex) EdgeToward(v2 *Vertex) *Edge {
 // than the given vertex in dimension d.
 func (v *Vertex) PartitionEdges(d int) (lesser []*Edge,
 	greater []*Edge, colinear []*Edge, err error) {
+	return v.PartitionEdgesWithin(d, geom.DefaultTolerance)
+}
+
+// PartitionEdgesWithin acts as PartitionEdges, treating
+// endpoints as equal to v when they are within tolerance t.
+func (v *Vertex) PartitionEdgesWithin(d int, t geom.Tolerance) (lesser []*Edge,
+	greater []*Edge, colinear []*Edge, err error) {
 
 	if len(v.Point) <= d {
 		err = compgeo.BadDimensionError{}
@@ -65,7 +96,7 @@ func (v *Vertex) PartitionEdges(d int) (lesser []*Edge,
 	checkAgainst := v.Val(d)
 	for _, e1 := range allEdges {
 		e2 := e1.Twin
-		if geom.F64eq(e2.Origin.Val(d), checkAgainst) {
+		if t.F64eq(e2.Origin.Val(d), checkAgainst) {
 			colinear = append(colinear, e1)
 		} else if e2.Origin.Val(d) < checkAgainst {
 			lesser = append(lesser, e1)
@@ -76,7 +107,11 @@ func (v *Vertex) PartitionEdges(d int) (lesser []*Edge,
 	return
 }
 
-// PointToVertex converts a point into a vertex
+// PointToVertex converts a point into a vertex. If the
+// point is exact, the vertex keeps its exact position.
 func PointToVertex(dp geom.D3) *Vertex {
+	if ed, ok := dp.(geom.ExactDimensional); ok {
+		return NewExactVertex(ed)
+	}
 	return NewVertex(dp.Val(0), dp.Val(1), dp.Val(2))
 }
@@ -20,20 +20,25 @@ import (
 // a generic color because they are barely visibile anyway
 // with our drawing scheme.
 // Polyhedrons are not drawn in a very sophisticated manner.
+//
+// A Polyhedron is drawn as seen through View. Because the
+// demo edits its DCEL in screen space, rotations are still
+// applied to the DCEL itself, and View defaults to an
+// orthographic projection that leaves it in place.
 type Polyhedron struct {
 	*render.Sprite
 	dcel.DCEL
 	FaceColors []color.Color
 	EdgeColors []color.Color
 	Center     physics.Vector
+	View       geom.Projection
 }
 
 var (
 	// Default colors
-	edgeColor   = color.RGBA{0, 0, 255, 255}
-	faceColor   = color.RGBA{0, 150, 150, 255}
-	ptColor     = color.RGBA{255, 255, 255, 255}
-	zCorrection = 1.0
+	edgeColor = color.RGBA{0, 0, 255, 255}
+	faceColor = color.RGBA{0, 150, 150, 255}
+	ptColor   = color.RGBA{255, 255, 255, 255}
 )
 
 // NewPolyhedronFromDCEL creates a polyhedron from a dcel
@@ -44,6 +49,7 @@ func NewPolyhedronFromDCEL(dc *dcel.DCEL, x, y float64) *Polyhedron {
 	p.Center = physics.NewVector(0, 0)
 	p.SetPos(x, y)
 	p.DCEL = *dc
+	p.View = geom.NewOrthographic(geom.IdentityQuaternion(), 1)
 	p.Update()
 	p.Center = physics.NewVector(p.X()+(1+p.MaxX())/2, p.Y()+(1+p.MaxY())/2)
 	return p
@@ -61,11 +67,16 @@ func (p *Polyhedron) Update() {
 	rect := image.Rect(0, 0, int(maxX), int(maxY))
 	rgba := image.NewRGBA(rect)
 
-	// We ignore Z in terms of where exactly we draw anything
-	// on screen.
-	// There isn't an alternative to this, aside from
-	// recoloring things to account for different z values,
-	// without having a camera system, which is a lot of work
+	// Everything is drawn where View puts it, and ordered
+	// by the depth View gives it.
+	pts, err := p.Project(p.View)
+	if err != nil {
+		return
+	}
+	proj := make(map[*dcel.Vertex]geom.Point, len(pts))
+	for i, v := range p.Vertices {
+		proj[v] = pts[i]
+	}
 
 	// Try to keep the center of this polyhedron to stay in
 	// one place on screen. This is not exactly the expected
@@ -78,9 +89,8 @@ func (p *Polyhedron) Update() {
 		p.SetY(p.Y() - (cy - p.Center.Y()))
 	}
 
-	// Eventually:
-	// For all Faces, Edges, and Vertices, sort by z value
-	// and draw them high-to-low
+	// For all Faces, Edges, and Vertices, sort by depth
+	// and draw them far-to-near
 	zi := 0
 	zOrder := make([]polyDraw, len(p.HalfEdges)/2+len(p.Faces)-1+len(p.Vertices))
 	// I understand that this is not an accurate way of drawing things
@@ -105,8 +115,8 @@ func (p *Polyhedron) Update() {
 	}
 
 	for i := 0; i < len(p.HalfEdges); i += 2 {
-		points, err := p.FullEdge(i)
-		if err != nil {
+		e := p.HalfEdges[i]
+		if e.Origin == nil || e.Twin == nil || e.Twin.Origin == nil {
 			continue
 		}
 		if i/2 >= len(p.EdgeColors) {
@@ -115,13 +125,13 @@ func (p *Polyhedron) Update() {
 		if p.EdgeColors[i/2] == nil {
 			p.EdgeColors[i/2] = edgeColor
 		}
-		zOrder[zi] = coloredEdge{points, p.EdgeColors[i/2]}
+		zOrder[zi] = coloredEdge{proj[e.Origin], proj[e.Twin.Origin], p.EdgeColors[i/2]}
 		zi++
 	}
 
 	// Step 2: draw all vertices
-	for _, v := range p.Vertices {
-		zOrder[zi] = drawPoint{v}
+	for _, pt := range pts {
+		zOrder[zi] = drawPoint{pt}
 		zi++
 	}
 
@@ -136,12 +146,13 @@ func (p *Polyhedron) Update() {
 			p.FaceColors[i] = faceColor
 		}
 		verts := f.Vertices()
-		maxZ := math.MaxFloat64 * -1
+		minZ := math.MaxFloat64
 		physVerts := make([]physics.Vector, len(verts))
 		for i, v := range verts {
-			physVerts[i] = physics.NewVector(v.X(), v.Y())
-			if v.Z() > maxZ {
-				maxZ = v.Z()
+			pt := proj[v]
+			physVerts[i] = physics.NewVector(pt.X(), pt.Y())
+			if pt.Z() < minZ {
+				minZ = pt.Z()
 			}
 		}
 
@@ -153,7 +164,7 @@ func (p *Polyhedron) Update() {
 		}
 		fpoly := facePolygon{
 			poly,
-			maxZ,
+			minZ,
 			p.FaceColors[i],
 		}
 
@@ -161,12 +172,18 @@ func (p *Polyhedron) Update() {
 		zi++
 	}
 
-	// Sort the elements of zOrder by their Z values.
+	// Sort the elements of zOrder by their depth, farthest
+	// first. Where depths tie, as they do for a face and its
+	// nearest edges and vertices, faces are drawn first, then
+	// edges, then vertices, so outlines stay visible.
 	sort.Slice(zOrder, func(i, j int) bool {
 		if zOrder[i] == nil || zOrder[j] == nil {
 			return false
 		}
-		return zOrder[i].Z() < zOrder[j].Z()
+		if zOrder[i].Z() != zOrder[j].Z() {
+			return zOrder[i].Z() > zOrder[j].Z()
+		}
+		return zOrder[i].layer() < zOrder[j].layer()
 	})
 
 	for _, item := range zOrder {
@@ -180,33 +197,38 @@ func (p *Polyhedron) Update() {
 
 type polyDraw interface {
 	Z() float64
+	layer() int
 	draw(*image.RGBA)
 }
 
 type drawPoint struct {
-	*dcel.Vertex
+	geom.Point
 }
 
-func (dp drawPoint) Z() float64 {
-	return dp.Vertex.Z() + zCorrection*2
+func (dp drawPoint) layer() int {
+	return 2
 }
 
 func (dp drawPoint) draw(rgba *image.RGBA) {
-	rgba.Set(int(dp.Val(0)), int(dp.Val(1)), ptColor)
+	rgba.Set(int(dp.X()), int(dp.Y()), ptColor)
 }
 
 type coloredEdge struct {
-	ps geom.FullEdge
-	c  color.Color
+	a, b geom.Point
+	c    color.Color
 }
 
 func (ce coloredEdge) Z() float64 {
-	return ce.ps.High(2).Val(2) + zCorrection
+	return math.Min(ce.a.Z(), ce.b.Z())
+}
+
+func (ce coloredEdge) layer() int {
+	return 1
 }
 
 func (ce coloredEdge) draw(rgba *image.RGBA) {
-	render.DrawLineOnto(rgba, int(ce.ps[0].X()), int(ce.ps[0].Y()),
-		int(ce.ps[1].X()), int(ce.ps[1].Y()), ce.c)
+	render.DrawLineOnto(rgba, int(ce.a.X()), int(ce.a.Y()),
+		int(ce.b.X()), int(ce.b.Y()), ce.c)
 }
 
 type facePolygon struct {
@@ -219,6 +241,10 @@ func (fp facePolygon) Z() float64 {
 	return fp.z
 }
 
+func (fp facePolygon) layer() int {
+	return 0
+}
+
 func (fp facePolygon) draw(rgba *image.RGBA) {
 	for x := fp.Rect.MinX; x < fp.Rect.MaxX; x++ {
 		for y := fp.Rect.MinY; y < fp.Rect.MaxY; y++ {
@@ -247,52 +273,28 @@ func PolygonFromFace(f *dcel.Face) *render.Polygon {
 
 // RotZ rotates the polyhedron around the Z axis
 func (p *Polyhedron) RotZ(theta float64) {
-	st := math.Sin(theta)
-	ct := math.Cos(theta)
-
-	for _, v := range p.Vertices {
-		v0 := v.X()*ct - v.Y()*st
-		v.Point[1] = v.Y()*ct + v.X()*st
-		v.Point[0] = v0
-	}
-	p.Update()
+	p.Rotate(geom.AxisAngle(geom.Point{0, 0, 1}, theta))
 }
 
 // RotX rotates the polyhedron around the X axis
 func (p *Polyhedron) RotX(theta float64) {
-	st := math.Sin(theta)
-	ct := math.Cos(theta)
-
-	for _, v := range p.Vertices {
-		v1 := v.Y()*ct - v.Z()*st
-		v.Point[2] = v.Z()*ct + v.Y()*st
-		v.Point[1] = v1
-	}
-	p.Update()
+	p.Rotate(geom.AxisAngle(geom.Point{1, 0, 0}, theta))
 }
 
 // RotY rotates the polyhedron around the Y axis
 func (p *Polyhedron) RotY(theta float64) {
-	st := math.Sin(theta)
-	ct := math.Cos(theta)
+	p.Rotate(geom.AxisAngle(geom.Point{0, 1, 0}, -theta))
+}
 
-	for _, v := range p.Vertices {
-		v0 := v.X()*ct - v.Z()*st
-		v.Point[2] = v.Z()*ct + v.X()*st
-		v.Point[0] = v0
-	}
+// Rotate rotates the polyhedron by the given quaternion
+func (p *Polyhedron) Rotate(q geom.Quaternion) {
+	p.DCEL.Transform(q.Affine3())
 	p.Update()
 }
 
 // Scale scales up or down the given polyhedron
 func (p *Polyhedron) Scale(factor float64) {
-	for _, v := range p.Vertices {
-		v.Point = geom.Point{
-			v.X() * factor,
-			v.Y() * factor,
-			v.Z() * factor,
-		}
-	}
+	p.DCEL.Transform(geom.Scale3(factor, factor, factor))
 	p.Update()
 }
 
@@ -0,0 +1,294 @@
+package geom
+
+import (
+	"math"
+
+	compgeo "github.com/200sc/go-compgeo"
+)
+
+// An Affine type is a transformation which maps points
+// to points, preserving lines and parallelism.
+type Affine interface {
+	// Transform applies the transformation to a point.
+	Transform(D3) Point
+	// Det returns the determinant of the linear portion
+	// of the transformation. A negative determinant means
+	// the transformation reverses orientation.
+	Det() float64
+}
+
+// Affine2 is a two dimensional affine transformation, stored
+// as the top two rows of a 3x3 homogeneous matrix, the last
+// row of which is always (0, 0, 1). Affine2s leave the z
+// value of transformed points unchanged.
+type Affine2 [2][3]float64
+
+// Identity2 returns the Affine2 which does nothing.
+func Identity2() Affine2 {
+	return Affine2{
+		{1, 0, 0},
+		{0, 1, 0},
+	}
+}
+
+// Translate2 returns an Affine2 which moves points by (x, y).
+func Translate2(x, y float64) Affine2 {
+	return Affine2{
+		{1, 0, x},
+		{0, 1, y},
+	}
+}
+
+// Rotate2 returns an Affine2 which rotates points theta
+// radians around the origin.
+func Rotate2(theta float64) Affine2 {
+	st := math.Sin(theta)
+	ct := math.Cos(theta)
+	return Affine2{
+		{ct, -st, 0},
+		{st, ct, 0},
+	}
+}
+
+// Scale2 returns an Affine2 which scales points by x and y
+// along their respective axes.
+func Scale2(x, y float64) Affine2 {
+	return Affine2{
+		{x, 0, 0},
+		{0, y, 0},
+	}
+}
+
+// Shear2 returns an Affine2 which shears x by x times y,
+// and y by y times x.
+func Shear2(x, y float64) Affine2 {
+	return Affine2{
+		{1, x, 0},
+		{y, 1, 0},
+	}
+}
+
+// Compose returns the transformation which applies m2, then m.
+func (m Affine2) Compose(m2 Affine2) Affine2 {
+	var out Affine2
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			out[i][j] = m[i][0]*m2[0][j] + m[i][1]*m2[1][j]
+		}
+		out[i][2] += m[i][2]
+	}
+	return out
+}
+
+// Translate returns m followed by a translation.
+func (m Affine2) Translate(x, y float64) Affine2 {
+	return Translate2(x, y).Compose(m)
+}
+
+// Rotate returns m followed by a rotation.
+func (m Affine2) Rotate(theta float64) Affine2 {
+	return Rotate2(theta).Compose(m)
+}
+
+// Scale returns m followed by a scale.
+func (m Affine2) Scale(x, y float64) Affine2 {
+	return Scale2(x, y).Compose(m)
+}
+
+// Shear returns m followed by a shear.
+func (m Affine2) Shear(x, y float64) Affine2 {
+	return Shear2(x, y).Compose(m)
+}
+
+// Det returns the determinant of m's linear portion.
+func (m Affine2) Det() float64 {
+	return m[0][0]*m[1][1] - m[0][1]*m[1][0]
+}
+
+// Invert returns the transformation which undoes m. If m
+// has a zero determinant, it returns a DivideByZero error.
+func (m Affine2) Invert() (Affine2, error) {
+	det := m.Det()
+	if det == 0 {
+		return Affine2{}, compgeo.DivideByZero{}
+	}
+	var out Affine2
+	out[0][0] = m[1][1] / det
+	out[0][1] = -m[0][1] / det
+	out[1][0] = -m[1][0] / det
+	out[1][1] = m[0][0] / det
+	out[0][2] = -(out[0][0]*m[0][2] + out[0][1]*m[1][2])
+	out[1][2] = -(out[1][0]*m[0][2] + out[1][1]*m[1][2])
+	return out, nil
+}
+
+// Transform applies m to p.
+func (m Affine2) Transform(p D3) Point {
+	return Point{
+		m[0][0]*p.X() + m[0][1]*p.Y() + m[0][2],
+		m[1][0]*p.X() + m[1][1]*p.Y() + m[1][2],
+		p.Z(),
+	}
+}
+
+// Affine3 is a three dimensional affine transformation, stored
+// as the top three rows of a 4x4 homogeneous matrix, the last
+// row of which is always (0, 0, 0, 1).
+type Affine3 [3][4]float64
+
+// Identity3 returns the Affine3 which does nothing.
+func Identity3() Affine3 {
+	return Affine3{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+	}
+}
+
+// Translate3 returns an Affine3 which moves points by (x, y, z).
+func Translate3(x, y, z float64) Affine3 {
+	return Affine3{
+		{1, 0, 0, x},
+		{0, 1, 0, y},
+		{0, 0, 1, z},
+	}
+}
+
+// RotateX3 returns an Affine3 which rotates points theta
+// radians around the x axis.
+func RotateX3(theta float64) Affine3 {
+	st := math.Sin(theta)
+	ct := math.Cos(theta)
+	return Affine3{
+		{1, 0, 0, 0},
+		{0, ct, -st, 0},
+		{0, st, ct, 0},
+	}
+}
+
+// RotateY3 returns an Affine3 which rotates points theta
+// radians around the y axis.
+func RotateY3(theta float64) Affine3 {
+	st := math.Sin(theta)
+	ct := math.Cos(theta)
+	return Affine3{
+		{ct, 0, st, 0},
+		{0, 1, 0, 0},
+		{-st, 0, ct, 0},
+	}
+}
+
+// RotateZ3 returns an Affine3 which rotates points theta
+// radians around the z axis.
+func RotateZ3(theta float64) Affine3 {
+	st := math.Sin(theta)
+	ct := math.Cos(theta)
+	return Affine3{
+		{ct, -st, 0, 0},
+		{st, ct, 0, 0},
+		{0, 0, 1, 0},
+	}
+}
+
+// Scale3 returns an Affine3 which scales points by x, y and z
+// along their respective axes.
+func Scale3(x, y, z float64) Affine3 {
+	return Affine3{
+		{x, 0, 0, 0},
+		{0, y, 0, 0},
+		{0, 0, z, 0},
+	}
+}
+
+// Shear3 returns an Affine3 which shears each axis by the other
+// two. xy is how much x changes per unit of y, xz how much x
+// changes per unit of z, and so on.
+func Shear3(xy, xz, yx, yz, zx, zy float64) Affine3 {
+	return Affine3{
+		{1, xy, xz, 0},
+		{yx, 1, yz, 0},
+		{zx, zy, 1, 0},
+	}
+}
+
+// Compose returns the transformation which applies m2, then m.
+func (m Affine3) Compose(m2 Affine3) Affine3 {
+	var out Affine3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			out[i][j] = m[i][0]*m2[0][j] + m[i][1]*m2[1][j] + m[i][2]*m2[2][j]
+		}
+		out[i][3] += m[i][3]
+	}
+	return out
+}
+
+// Translate returns m followed by a translation.
+func (m Affine3) Translate(x, y, z float64) Affine3 {
+	return Translate3(x, y, z).Compose(m)
+}
+
+// RotateX returns m followed by a rotation around the x axis.
+func (m Affine3) RotateX(theta float64) Affine3 {
+	return RotateX3(theta).Compose(m)
+}
+
+// RotateY returns m followed by a rotation around the y axis.
+func (m Affine3) RotateY(theta float64) Affine3 {
+	return RotateY3(theta).Compose(m)
+}
+
+// RotateZ returns m followed by a rotation around the z axis.
+func (m Affine3) RotateZ(theta float64) Affine3 {
+	return RotateZ3(theta).Compose(m)
+}
+
+// Scale returns m followed by a scale.
+func (m Affine3) Scale(x, y, z float64) Affine3 {
+	return Scale3(x, y, z).Compose(m)
+}
+
+// Shear returns m followed by a shear.
+func (m Affine3) Shear(xy, xz, yx, yz, zx, zy float64) Affine3 {
+	return Shear3(xy, xz, yx, yz, zx, zy).Compose(m)
+}
+
+// Det returns the determinant of m's linear portion.
+func (m Affine3) Det() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Invert returns the transformation which undoes m. If m
+// has a zero determinant, it returns a DivideByZero error.
+func (m Affine3) Invert() (Affine3, error) {
+	det := m.Det()
+	if det == 0 {
+		return Affine3{}, compgeo.DivideByZero{}
+	}
+	var out Affine3
+	// The inverse of the linear portion is its adjugate
+	// divided by its determinant.
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			r1, r2 := (j+1)%3, (j+2)%3
+			c1, c2 := (i+1)%3, (i+2)%3
+			out[i][j] = (m[r1][c1]*m[r2][c2] - m[r1][c2]*m[r2][c1]) / det
+		}
+	}
+	for i := 0; i < 3; i++ {
+		out[i][3] = -(out[i][0]*m[0][3] + out[i][1]*m[1][3] + out[i][2]*m[2][3])
+	}
+	return out, nil
+}
+
+// Transform applies m to p.
+func (m Affine3) Transform(p D3) Point {
+	x, y, z := p.X(), p.Y(), p.Z()
+	return Point{
+		m[0][0]*x + m[0][1]*y + m[0][2]*z + m[0][3],
+		m[1][0]*x + m[1][1]*y + m[1][2]*z + m[1][3],
+		m[2][0]*x + m[2][1]*y + m[2][2]*z + m[2][3],
+	}
+}
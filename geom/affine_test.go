@@ -0,0 +1,46 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAffine2(t *testing.T) {
+	m := Identity2().Scale(2, 3).Rotate(math.Pi/2).Translate(1, 1)
+	p := m.Transform(NewPoint(1, 1, 5))
+	assert.True(t, p.EqWithin(NewPoint(-2, 3, 5), DefaultTolerance))
+	inv, err := m.Invert()
+	assert.Nil(t, err)
+	assert.True(t, inv.Transform(p).EqWithin(NewPoint(1, 1, 5), DefaultTolerance))
+	assert.True(t, DefaultTolerance.F64eq(6, m.Det()))
+	assert.True(t, Scale2(-1, 1).Det() < 0)
+	_, err = Scale2(0, 1).Invert()
+	assert.NotNil(t, err)
+	sh := Shear2(1, 0).Transform(NewPoint(1, 2, 0))
+	assert.Equal(t, NewPoint(3, 2, 0), sh)
+}
+
+func TestAffine3(t *testing.T) {
+	m := Translate3(1, 2, 3).RotateX(.3).RotateY(1.1).RotateZ(-.7).
+		Shear(.1, .2, .3, 0, 0, .4).Scale(2, 1, .5)
+	p := NewPoint(4, -2, 7)
+	inv, err := m.Invert()
+	assert.Nil(t, err)
+	assert.True(t, inv.Transform(m.Transform(p)).EqWithin(p, DefaultTolerance))
+	assert.True(t, m.Compose(inv).Transform(p).EqWithin(p, DefaultTolerance))
+	assert.True(t, DefaultTolerance.F64eq(1, RotateZ3(2).Det()))
+	q := RotateZ3(math.Pi / 2).Transform(NewPoint(1, 0, 0))
+	assert.True(t, q.EqWithin(NewPoint(0, 1, 0), DefaultTolerance))
+	// Each rotation is right handed, turning one axis toward
+	// the next.
+	q = RotateX3(math.Pi / 2).Transform(NewPoint(0, 1, 0))
+	assert.True(t, q.EqWithin(NewPoint(0, 0, 1), DefaultTolerance))
+	q = RotateY3(math.Pi / 2).Transform(NewPoint(0, 0, 1))
+	assert.True(t, q.EqWithin(NewPoint(1, 0, 0), DefaultTolerance))
+	q = RotateY3(.4).Transform(NewPoint(1, 2, 3))
+	assert.True(t, q.EqWithin(AxisAngle(NewPoint(0, 1, 0), .4).Rotate(NewPoint(1, 2, 3)), DefaultTolerance))
+	_, err = Scale3(1, 1, 0).Invert()
+	assert.NotNil(t, err)
+}
@@ -39,68 +39,92 @@ func HzCross2D(a, b, c D2) float64 {
 	return cp
 }
 
+// VertOrient2D is the exact equivalent of VertCross2D,
+// returning only the sign of the cross product.
+func VertOrient2D(a, b, c D2) int {
+	o := Orient2D(a, b, c)
+	if CmpVal(b, c, 1) > 0 {
+		o *= -1
+	}
+	return o
+}
+
+// HzOrient2D is the exact equivalent of HzCross2D,
+// returning only the sign of the cross product.
+func HzOrient2D(a, b, c D2) int {
+	o := Orient2D(a, b, c)
+	if CmpVal(b, c, 0) > 0 {
+		o *= -1
+	}
+	return o
+}
+
 // IsColinear returns whether the cross product reports 0.
 func IsColinear(a, b, c D2) bool {
-	return Cross2D(a, b, c) == 0
+	return Orient2D(a, b, c) == 0
 }
 
 // IsAbove returns whether a is above the line segment
 // formed by (b->c)
 func IsAbove(a, b, c D2) bool {
-	return HzCross2D(a, b, c) > 0
+	return HzOrient2D(a, b, c) > 0
 }
 
 // IsBelow is equivalent to !IsAbove && !IsColinear
 func IsBelow(a, b, c D2) bool {
-	return HzCross2D(a, b, c) < 0
+	return HzOrient2D(a, b, c) < 0
 }
 
 // IsColinearOrAbove is equivalent to calling IsColinear || IsAbove
 // without redoing the cross product calculation.
 func IsColinearOrAbove(a, b, c D2) bool {
-	return HzCross2D(a, b, c) >= 0
+	return HzOrient2D(a, b, c) >= 0
 }
 
 // IsColinearOrBelow is equivalent to calling IsColinear || IsBelow
 // without redoing the cross product calculation
 func IsColinearOrBelow(a, b, c D2) bool {
-	return HzCross2D(a, b, c) <= 0
+	return HzOrient2D(a, b, c) <= 0
 }
 
 // IsLeftOf returns whether a is to the left of the line segment
 // formed by (b->c)
 func IsLeftOf(a, b, c D2) bool {
-	return VertCross2D(a, b, c) > 0
+	return VertOrient2D(a, b, c) > 0
 }
 
 // IsRightOf is equivalent to !IsLeftOf, except that both
 // IsRightOf and IsLeftOf return false for Cross2D() == 0
 func IsRightOf(a, b, c D2) bool {
-	return VertCross2D(a, b, c) < 0
+	return VertOrient2D(a, b, c) < 0
 }
 
 // IsColinearOrLeft is equivalent to calling IsColinear || IsLeft
 // without redoing the cross product calculation.
 func IsColinearOrLeft(a, b, c D2) bool {
-	return VertCross2D(a, b, c) >= 0
+	return VertOrient2D(a, b, c) >= 0
 }
 
 // IsColinearOrRight is equivalent to calling IsColinear || IsRight
 // without redoing the cross product calculation
 func IsColinearOrRight(a, b, c D2) bool {
-	return VertCross2D(a, b, c) <= 0
+	return VertOrient2D(a, b, c) <= 0
 }
 
 // VerticalCompare returns a search result
 // representing whether this point is above
 // equal or below the query edge.
 func VerticalCompare(dp D2, e Spanning) search.CompareResult {
-	p1 := e.At(0).(D2)
-	p2 := e.At(1).(D2)
-	if p1.X() < p2.X() {
+	return VerticalComparePoints(dp, e.At(0).(D2), e.At(1).(D2))
+}
+
+// VerticalComparePoints acts as VerticalCompare on the
+// edge from p1 to p2.
+func VerticalComparePoints(dp, p1, p2 D2) search.CompareResult {
+	if CmpVal(p1, p2, 0) < 0 {
 		p1, p2 = p2, p1
 	}
-	s := Cross2D(p1, p2, dp)
+	s := Orient2D(p1, p2, dp)
 	if s == 0 {
 		return search.Equal
 	} else if s < 0 {
@@ -108,3 +132,26 @@ func VerticalCompare(dp D2, e Spanning) search.CompareResult {
 	}
 	return search.Greater
 }
+
+// VerticalOrder returns -1 if the segment from l1 to r1 lies
+// below the segment from l2 to r2 over the x range they share,
+// 1 if it lies above it, and 0 if the two are colinear. Each
+// segment is given left endpoint first. Unlike comparing y
+// values at some shared x, this is exact, as it is built from
+// orientation tests against each segment's endpoints.
+func VerticalOrder(l1, r1, l2, r2 D2) int {
+	if CmpVal(l1, l2, 0) >= 0 {
+		// The first segment begins within the second's
+		// range, so test it against the second.
+		o := Orient2D(l2, r2, l1)
+		if o == 0 {
+			o = Orient2D(l2, r2, r1)
+		}
+		return o
+	}
+	o := Orient2D(l1, r1, l2)
+	if o == 0 {
+		o = Orient2D(l1, r1, r2)
+	}
+	return -o
+}
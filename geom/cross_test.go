@@ -0,0 +1,29 @@
+package geom
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerticalOrder(t *testing.T) {
+	a1, a2 := NewPoint(0, 0, 0), NewPoint(10, 0, 0)
+	b1, b2 := NewPoint(5, 1, 0), NewPoint(20, 3, 0)
+	assert.Equal(t, -1, VerticalOrder(a1, a2, b1, b2))
+	assert.Equal(t, 1, VerticalOrder(b1, b2, a1, a2))
+	// Segments sharing an endpoint are ordered by their
+	// other endpoints
+	c2 := NewPoint(10, -1, 0)
+	assert.Equal(t, 1, VerticalOrder(a1, a2, a1, c2))
+	assert.Equal(t, -1, VerticalOrder(a1, c2, a1, a2))
+	assert.Equal(t, 0, VerticalOrder(a1, a2, NewPoint(5, 0, 0), NewPoint(15, 0, 0)))
+	// Segments through (3, 1/3) and (6, 2/3) lie exactly on
+	// the line from the origin through (9, 1), but their float
+	// approximations do not.
+	o1, o2 := NewPoint(0, 0, 0), NewPoint(9, 1, 0)
+	d1 := NewRatPoint(big.NewRat(3, 1), big.NewRat(1, 3), new(big.Rat))
+	d2 := NewRatPoint(big.NewRat(6, 1), big.NewRat(2, 3), new(big.Rat))
+	assert.Equal(t, 0, VerticalOrder(d1, d2, o1, o2))
+	assert.NotEqual(t, 0, VerticalOrder(d1.Point(), d2.Point(), o1, o2))
+}
@@ -5,19 +5,88 @@ package geom
 import "math"
 
 const (
-	// Epsilon could probably be smaller than this without
-	// causing problems, but we're being overly cautious.
-	ε = 1.0e-7
 	// Inf is shorthand for math.MaxFloat64
 	Inf = math.MaxFloat64
 	// NegInf is shorthand for math.MaxFloat64 * -1
 	NegInf = -math.MaxFloat64
 )
 
-// F64eq returns whether two input float64s have
-// a lesser difference than epsilon.
+var (
+	// DefaultTolerance is the tolerance used by F64eq and
+	// by every builder which is not given its own tolerance.
+	// Its absolute epsilon could probably be smaller than this
+	// without causing problems, but we're being overly cautious.
+	DefaultTolerance = Tolerance{Abs: 1.0e-7}
+)
+
+// A Tolerance defines how close two values need to be to
+// be considered equal. Two values are equal under a tolerance
+// if their difference is no greater than Abs, or no greater
+// than Rel multiplied by the larger magnitude of the two values.
+// The zero Tolerance only considers identical values equal.
+type Tolerance struct {
+	Abs float64
+	Rel float64
+}
+
+// NewTolerance returns a Tolerance with the given absolute
+// and relative thresholds.
+func NewTolerance(abs, rel float64) Tolerance {
+	return Tolerance{Abs: abs, Rel: rel}
+}
+
+// F64eq returns whether two input float64s are
+// equal under this tolerance.
+func (t Tolerance) F64eq(f1, f2 float64) bool {
+	if f1 == f2 {
+		return true
+	}
+	diff := math.Abs(f1 - f2)
+	if diff <= t.Abs {
+		return true
+	}
+	return diff <= t.Rel*math.Max(math.Abs(f1), math.Abs(f2))
+}
+
+// Epsilon returns how far a value may be from f and still
+// be equal to it under this tolerance.
+func (t Tolerance) Epsilon(f float64) float64 {
+	return math.Max(t.Abs, t.Rel*math.Abs(f))
+}
+
+// Eq returns whether two dimensionals have the same number of
+// dimensions and are equal under this tolerance in each of them.
+func (t Tolerance) Eq(d1, d2 Dimensional) bool {
+	if d1.D() != d2.D() {
+		return false
+	}
+	for i := 0; i < d1.D(); i++ {
+		if !t.F64eq(d1.Val(i), d2.Val(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// SpanEq returns whether two spanning types have the same
+// length and dimension, and each of their points are equal
+// under this tolerance.
+func (t Tolerance) SpanEq(s1, s2 Spanning) bool {
+	if s1.D() != s2.D() || s1.Len() != s2.Len() {
+		return false
+	}
+	for i := 0; i < s1.Len(); i++ {
+		if !t.Eq(s1.At(i), s2.At(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// F64eq returns whether two input float64s are
+// equal under the DefaultTolerance.
 func F64eq(f1, f2 float64) bool {
-	return math.Abs(f1-f2) <= ε
+	return DefaultTolerance.F64eq(f1, f2)
 }
 
 // Credit to David Calhoun at StackOverflow
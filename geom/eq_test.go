@@ -0,0 +1,24 @@
+package geom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTolerance(t *testing.T) {
+	tol := NewTolerance(0.01, 0)
+	assert.True(t, tol.F64eq(1, 1.005))
+	assert.False(t, tol.F64eq(1, 1.02))
+	rel := NewTolerance(0, 1e-6)
+	assert.True(t, rel.F64eq(1e9, 1e9+100))
+	assert.False(t, rel.F64eq(1, 1.001))
+	p := NewPoint(1, 2, 3)
+	assert.True(t, p.EqWithin(NewPoint(1.001, 2, 3), tol))
+	assert.False(t, p.Eq(NewPoint(1.001, 2, 3)))
+	assert.Equal(t, 0.01, tol.Epsilon(1e9))
+	assert.Equal(t, 1000.0, rel.Epsilon(-1e9))
+	assert.True(t, rel.F64eq(1e9, 1e9+rel.Epsilon(1e9)))
+	fe := FullEdge{p, NewPoint(4, 5, 6)}
+	assert.True(t, fe.EqWithin(FullEdge{NewPoint(1.001, 2, 3), NewPoint(4, 5, 6)}, tol))
+}
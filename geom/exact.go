@@ -0,0 +1,302 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/200sc/go-compgeo/printutil"
+)
+
+// ExactDimensional values can report their position in
+// a given dimension exactly, as a rational number, alongside
+// the float64 approximation Val offers.
+type ExactDimensional interface {
+	Dimensional
+	Rat(int) *big.Rat
+}
+
+// Rat returns the exact value of d at dimension i. If d
+// is not an ExactDimensional, the float64 value of d is
+// converted, which is exact for every finite float64.
+// Infinities and NaN have no exact value, so Rat panics
+// on them rather than let a predicate answer wrongly.
+func Rat(d Dimensional, i int) *big.Rat {
+	if ed, ok := d.(ExactDimensional); ok {
+		return ed.Rat(i)
+	}
+	return finiteRat(d.Val(i))
+}
+
+func finiteRat(v float64) *big.Rat {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		panic("Exact value of a non-finite float64")
+	}
+	return new(big.Rat).SetFloat64(v)
+}
+
+// CmpVal compares d1 and d2 at dimension i, exactly if either
+// is an ExactDimensional. It returns -1, 0, or 1 as d1 is less
+// than, equal to, or greater than d2.
+func CmpVal(d1, d2 Dimensional, i int) int {
+	_, ok1 := d1.(ExactDimensional)
+	_, ok2 := d2.(ExactDimensional)
+	if !ok1 && !ok2 {
+		v1 := d1.Val(i)
+		v2 := d2.Val(i)
+		if v1 < v2 {
+			return -1
+		} else if v1 > v2 {
+			return 1
+		}
+		return 0
+	}
+	return Rat(d1, i).Cmp(Rat(d2, i))
+}
+
+// IntPoint is a three dimensional point with integer
+// coordinates. Predicates on IntPoints are evaluated exactly,
+// with int64 arithmetic where it will not overflow and with
+// arbitrary precision arithmetic otherwise.
+type IntPoint [POINT_DIM]int64
+
+// NewIntPoint returns an IntPoint initialized at the given position
+func NewIntPoint(x, y, z int64) IntPoint {
+	return IntPoint{x, y, z}
+}
+
+// String converts ip into a string.
+func (ip IntPoint) String() string {
+	return "(" + printutil.Stringf64(ip.X(), ip.Y(), ip.Z()) + ")"
+}
+
+// Set sets the value at the given dimension on the point.
+// v is truncated toward zero, and must be finite.
+func (ip IntPoint) Set(i int, v float64) Dimensional {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		panic("Exact value of a non-finite float64")
+	}
+	ip[i] = int64(v)
+	return ip
+}
+
+// D returns the number of dimensions supported by an IntPoint.
+func (ip IntPoint) D() int {
+	return POINT_DIM
+}
+
+// Val returns the float64 approximation of ip at dimension d.
+func (ip IntPoint) Val(d int) float64 {
+	return float64(ip[d])
+}
+
+// Rat returns the exact value of ip at dimension d.
+func (ip IntPoint) Rat(d int) *big.Rat {
+	return new(big.Rat).SetInt64(ip[d])
+}
+
+// X returns the float64 approximation of ip on the x axis
+func (ip IntPoint) X() float64 {
+	return ip.Val(0)
+}
+
+// Y returns the float64 approximation of ip on the y axis
+func (ip IntPoint) Y() float64 {
+	return ip.Val(1)
+}
+
+// Z returns the float64 approximation of ip on the z axis
+func (ip IntPoint) Z() float64 {
+	return ip.Val(2)
+}
+
+// Eq returns whether ip is exactly equivalent to p2.
+func (ip IntPoint) Eq(p2 Dimensional) bool {
+	return exactEq(ip, p2)
+}
+
+// Point returns the float64 approximation of ip.
+func (ip IntPoint) Point() Point {
+	return Point{ip.X(), ip.Y(), ip.Z()}
+}
+
+// RatPoint is a three dimensional point with rational
+// coordinates. A nil coordinate is treated as zero.
+type RatPoint [POINT_DIM]*big.Rat
+
+// NewRatPoint returns a RatPoint initialized at the given position.
+// The input values are copied.
+func NewRatPoint(x, y, z *big.Rat) RatPoint {
+	return RatPoint{
+		new(big.Rat).Set(x),
+		new(big.Rat).Set(y),
+		new(big.Rat).Set(z),
+	}
+}
+
+// String converts rp into a string.
+func (rp RatPoint) String() string {
+	return "(" + printutil.Stringf64(rp.X(), rp.Y(), rp.Z()) + ")"
+}
+
+// Set sets the value at the given dimension on the point.
+// v must be finite.
+func (rp RatPoint) Set(i int, v float64) Dimensional {
+	rp[i] = finiteRat(v)
+	return rp
+}
+
+// D returns the number of dimensions supported by a RatPoint.
+func (rp RatPoint) D() int {
+	return POINT_DIM
+}
+
+// Val returns the float64 approximation of rp at dimension d.
+func (rp RatPoint) Val(d int) float64 {
+	if rp[d] == nil {
+		return 0
+	}
+	f, _ := rp[d].Float64()
+	return f
+}
+
+// Rat returns a copy of the exact value of rp at dimension d.
+func (rp RatPoint) Rat(d int) *big.Rat {
+	if rp[d] == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(rp[d])
+}
+
+// X returns the float64 approximation of rp on the x axis
+func (rp RatPoint) X() float64 {
+	return rp.Val(0)
+}
+
+// Y returns the float64 approximation of rp on the y axis
+func (rp RatPoint) Y() float64 {
+	return rp.Val(1)
+}
+
+// Z returns the float64 approximation of rp on the z axis
+func (rp RatPoint) Z() float64 {
+	return rp.Val(2)
+}
+
+// Eq returns whether rp is exactly equivalent to p2.
+func (rp RatPoint) Eq(p2 Dimensional) bool {
+	return exactEq(rp, p2)
+}
+
+// Point returns the float64 approximation of rp.
+func (rp RatPoint) Point() Point {
+	return Point{rp.X(), rp.Y(), rp.Z()}
+}
+
+func exactEq(d1, d2 Dimensional) bool {
+	if d1.D() != d2.D() {
+		return false
+	}
+	for i := 0; i < d1.D(); i++ {
+		if CmpVal(d1, d2, i) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// orientErrBound bounds the relative error of a floating point
+// evaluation of Cross2D, following Shewchuk's orient2d filter.
+const orientErrBound = (3.0 + 16.0*epsilon) * epsilon
+
+// epsilon is half of the distance between 1 and the next float64.
+const epsilon = 1.0 / (1 << 53)
+
+// Orient2D returns the sign of Cross2D(a, b, c), evaluated
+// exactly: 1 if c lies to the left of a->b, -1 if it lies
+// to the right, and 0 if the three points are colinear.
+// Float inputs are first checked with a filtered floating point
+// evaluation, and only fall back to exact arithmetic when the
+// float result is too small to trust.
+func Orient2D(a, b, c D2) int {
+	if ai, ok := a.(IntPoint); ok {
+		if bi, ok := b.(IntPoint); ok {
+			if ci, ok := c.(IntPoint); ok {
+				if s, ok := orientInt64(ai, bi, ci); ok {
+					return s
+				}
+				return orientExact(a, b, c)
+			}
+		}
+	}
+	_, ok1 := a.(ExactDimensional)
+	_, ok2 := b.(ExactDimensional)
+	_, ok3 := c.(ExactDimensional)
+	if ok1 || ok2 || ok3 {
+		return orientExact(a, b, c)
+	}
+	detLeft := (b.X() - a.X()) * (c.Y() - a.Y())
+	detRight := (b.Y() - a.Y()) * (c.X() - a.X())
+	det := detLeft - detRight
+	errBound := orientErrBound * (math.Abs(detLeft) + math.Abs(detRight))
+	if det > errBound {
+		return 1
+	} else if -det > errBound {
+		return -1
+	} else if det == 0 && errBound == 0 {
+		return 0
+	}
+	return orientExact(a, b, c)
+}
+
+func orientExact(a, b, c D2) int {
+	ax, ay := Rat(a, 0), Rat(a, 1)
+	bx := Rat(b, 0).Sub(Rat(b, 0), ax)
+	by := Rat(b, 1).Sub(Rat(b, 1), ay)
+	cx := Rat(c, 0).Sub(Rat(c, 0), ax)
+	cy := Rat(c, 1).Sub(Rat(c, 1), ay)
+	l := bx.Mul(bx, cy)
+	r := by.Mul(by, cx)
+	return l.Cmp(r)
+}
+
+// orientInt64 evaluates Orient2D on IntPoints with int64
+// arithmetic, reporting false if any step would overflow.
+func orientInt64(a, b, c IntPoint) (int, bool) {
+	bx, ok1 := subInt64(b[0], a[0])
+	by, ok2 := subInt64(b[1], a[1])
+	cx, ok3 := subInt64(c[0], a[0])
+	cy, ok4 := subInt64(c[1], a[1])
+	if !(ok1 && ok2 && ok3 && ok4) {
+		return 0, false
+	}
+	l, ok1 := mulInt64(bx, cy)
+	r, ok2 := mulInt64(by, cx)
+	if !(ok1 && ok2) {
+		return 0, false
+	}
+	if l > r {
+		return 1, true
+	} else if l < r {
+		return -1, true
+	}
+	return 0, true
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	c := a - b
+	if (c < a) != (b > 0) {
+		return 0, false
+	}
+	return c, true
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (c < 0) != ((a < 0) != (b < 0)) || c/b != a {
+		return 0, false
+	}
+	return c, true
+}
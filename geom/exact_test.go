@@ -0,0 +1,69 @@
+package geom
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrient2DFloat(t *testing.T) {
+	a := NewPoint(0, 0, 0)
+	b := NewPoint(1, 1, 0)
+	assert.Equal(t, 1, Orient2D(a, b, NewPoint(0, 1, 0)))
+	assert.Equal(t, -1, Orient2D(a, b, NewPoint(1, 0, 0)))
+	assert.Equal(t, 0, Orient2D(a, b, NewPoint(2, 2, 0)))
+	c := NewPoint(0.5, 0.5, 0)
+	d := NewPoint(12, 12, 0)
+	e := NewPoint(24, 24, 0)
+	assert.Equal(t, 0, Orient2D(c, d, e))
+}
+
+func TestOrient2DInt(t *testing.T) {
+	big1 := int64(1 << 60)
+	a := NewIntPoint(0, 0, 0)
+	b := NewIntPoint(big1, big1, 0)
+	// Values near 2^60 would overflow int64 in the cross
+	// product, and are not exactly representable as floats.
+	assert.Equal(t, 0, Orient2D(a, b, NewIntPoint(big1-1, big1-1, 0)))
+	assert.Equal(t, 1, Orient2D(a, b, NewIntPoint(big1-1, big1, 0)))
+	assert.Equal(t, -1, Orient2D(a, b, NewIntPoint(big1, big1-1, 0)))
+	assert.Equal(t, 1, Orient2D(a, NewIntPoint(4, 0, 0), NewIntPoint(2, 1, 0)))
+}
+
+func TestOrient2DRat(t *testing.T) {
+	third := big.NewRat(1, 3)
+	a := NewRatPoint(new(big.Rat), new(big.Rat), new(big.Rat))
+	b := NewRatPoint(third, third, new(big.Rat))
+	c := NewRatPoint(big.NewRat(2, 3), big.NewRat(2, 3), new(big.Rat))
+	assert.Equal(t, 0, Orient2D(a, b, c))
+	assert.Equal(t, 1, Orient2D(a, b, NewPoint(0, 1, 0)))
+}
+
+func TestMulSubInt64(t *testing.T) {
+	_, ok := mulInt64(math.MaxInt64, 2)
+	assert.False(t, ok)
+	_, ok = mulInt64(math.MinInt64, -1)
+	assert.False(t, ok)
+	v, ok := mulInt64(-3, 4)
+	assert.True(t, ok)
+	assert.Equal(t, int64(-12), v)
+	_, ok = subInt64(math.MinInt64, 1)
+	assert.False(t, ok)
+	v, ok = subInt64(5, 7)
+	assert.True(t, ok)
+	assert.Equal(t, int64(-2), v)
+}
+
+func TestRatNonFinite(t *testing.T) {
+	a := NewPoint(0, 0, 0)
+	b := NewPoint(1, 1, 0)
+	for _, v := range []float64{math.Inf(1), math.Inf(-1), math.NaN()} {
+		assert.Panics(t, func() { Rat(NewPoint(v, 0, 0), 0) })
+		assert.Panics(t, func() { Orient2D(a, b, NewPoint(v, 0, 0)) })
+		assert.Panics(t, func() { NewIntPoint(0, 0, 0).Set(0, v) })
+		assert.Panics(t, func() { NewRatPoint(new(big.Rat), new(big.Rat), new(big.Rat)).Set(0, v) })
+	}
+	assert.Equal(t, big.NewRat(1, 4), Rat(NewPoint(0.25, 0, 0), 0))
+}
@@ -130,18 +130,16 @@ func (fe FullEdge) Outer() D3 {
 	return fe.Low(2).(D3)
 }
 
-// Eq returns whether this edge is equivalent
+// Eq returns whether this edge is exactly equivalent
 // to another spanning type
 func (fe FullEdge) Eq(s Spanning) bool {
-	if fe.D() != s.D() {
-		return false
-	}
-	for i := 0; i < fe.D(); i++ {
-		if !fe.At(i).Eq(s.At(i)) {
-			return false
-		}
-	}
-	return true
+	return fe.EqWithin(s, Tolerance{})
+}
+
+// EqWithin returns whether this edge is equivalent
+// to another spanning type under the given tolerance
+func (fe FullEdge) EqWithin(s Spanning, t Tolerance) bool {
+	return t.SpanEq(fe, s)
 }
 
 // Set returns this edge with a value at i
@@ -0,0 +1,427 @@
+package geom
+
+import "math"
+
+// A Linear type is a portion of a line, P + t*D, where
+// t is restricted to some range. Segments, Rays and Lines
+// are all Linear.
+// Evaluation, projection and distance queries on Linear
+// types consider all three dimensions. Intersection queries
+// consider only x and y.
+type Linear interface {
+	// Eval returns the point at parameter t.
+	Eval(t float64) Point
+	// Range returns the valid parameters of this type.
+	Range() (lo, hi float64)
+	// Points returns the points at t=0 and t=1.
+	Points() (Point, Point)
+}
+
+// A Segment is the portion of a line between A and B.
+// A Segment is parameterized such that A is at t=0
+// and B is at t=1.
+type Segment struct {
+	A, B Point
+}
+
+// NewSegment returns a Segment from a to b.
+func NewSegment(a, b D3) Segment {
+	return Segment{toPoint(a), toPoint(b)}
+}
+
+// Eval returns the point at parameter t.
+func (s Segment) Eval(t float64) Point {
+	return eval(s.A, s.B, t)
+}
+
+// Range returns [0, 1].
+func (s Segment) Range() (float64, float64) {
+	return 0, 1
+}
+
+// Points returns A and B.
+func (s Segment) Points() (Point, Point) {
+	return s.A, s.B
+}
+
+// Length returns the distance from A to B.
+func (s Segment) Length() float64 {
+	return length(sub(s.B, s.A))
+}
+
+// Project returns the parameter of the point on s's
+// supporting line closest to p, which may be outside [0, 1].
+func (s Segment) Project(p D3) float64 {
+	return project(s, p)
+}
+
+// ClosestPoint returns the point on s closest to p.
+func (s Segment) ClosestPoint(p D3) Point {
+	return closestPoint(s, p)
+}
+
+// Distance returns the distance from p to s.
+func (s Segment) Distance(p D3) float64 {
+	return length(sub(toPoint(p), closestPoint(s, p)))
+}
+
+// Intersect returns the intersection of s and l.
+func (s Segment) Intersect(l Linear) Intersection {
+	return Intersect(s, l)
+}
+
+// FullEdge converts s into a FullEdge.
+func (s Segment) FullEdge() FullEdge {
+	return FullEdge{s.A, s.B}
+}
+
+// Segment converts fe into a Segment.
+func (fe FullEdge) Segment() Segment {
+	return Segment{fe[0], fe[1]}
+}
+
+// A Ray is the portion of a line starting at Origin
+// and continuing forever in direction Dir.
+// A Ray is parameterized such that Origin is at t=0
+// and Origin+Dir is at t=1.
+type Ray struct {
+	Origin, Dir Point
+}
+
+// NewRay returns a Ray starting at origin, heading in
+// direction dir.
+func NewRay(origin, dir D3) Ray {
+	return Ray{toPoint(origin), toPoint(dir)}
+}
+
+// Eval returns the point at parameter t.
+func (r Ray) Eval(t float64) Point {
+	return add(r.Origin, scale(r.Dir, t))
+}
+
+// Range returns [0, +Inf].
+func (r Ray) Range() (float64, float64) {
+	return 0, math.Inf(1)
+}
+
+// Points returns Origin and Origin+Dir.
+func (r Ray) Points() (Point, Point) {
+	return r.Origin, add(r.Origin, r.Dir)
+}
+
+// Project returns the parameter of the point on r's
+// supporting line closest to p, which may be negative.
+func (r Ray) Project(p D3) float64 {
+	return project(r, p)
+}
+
+// ClosestPoint returns the point on r closest to p.
+func (r Ray) ClosestPoint(p D3) Point {
+	return closestPoint(r, p)
+}
+
+// Distance returns the distance from p to r.
+func (r Ray) Distance(p D3) float64 {
+	return length(sub(toPoint(p), closestPoint(r, p)))
+}
+
+// Intersect returns the intersection of r and l.
+func (r Ray) Intersect(l Linear) Intersection {
+	return Intersect(r, l)
+}
+
+// A Line is the infinite line through P in direction Dir.
+// A Line is parameterized such that P is at t=0 and
+// P+Dir is at t=1.
+type Line struct {
+	P, Dir Point
+}
+
+// NewLine returns the Line through p in direction dir.
+func NewLine(p, dir D3) Line {
+	return Line{toPoint(p), toPoint(dir)}
+}
+
+// LineThrough returns the Line through a and b.
+func LineThrough(a, b D3) Line {
+	pa := toPoint(a)
+	return Line{pa, sub(toPoint(b), pa)}
+}
+
+// Eval returns the point at parameter t.
+func (l Line) Eval(t float64) Point {
+	return add(l.P, scale(l.Dir, t))
+}
+
+// Range returns [-Inf, +Inf].
+func (l Line) Range() (float64, float64) {
+	return math.Inf(-1), math.Inf(1)
+}
+
+// Points returns P and P+Dir.
+func (l Line) Points() (Point, Point) {
+	return l.P, add(l.P, l.Dir)
+}
+
+// Project returns the parameter of the point on l
+// closest to p.
+func (l Line) Project(p D3) float64 {
+	return project(l, p)
+}
+
+// ClosestPoint returns the point on l closest to p.
+func (l Line) ClosestPoint(p D3) Point {
+	return closestPoint(l, p)
+}
+
+// Distance returns the distance from p to l.
+func (l Line) Distance(p D3) float64 {
+	return length(sub(toPoint(p), closestPoint(l, p)))
+}
+
+// Intersect returns the intersection of l and l2.
+func (l Line) Intersect(l2 Linear) Intersection {
+	return Intersect(l, l2)
+}
+
+// IntersectionKind classifies an Intersection.
+type IntersectionKind int
+
+// Intersection kinds
+const (
+	NoIntersection IntersectionKind = iota
+	PointIntersection
+	OverlapIntersection
+)
+
+// An Intersection is the result of intersecting two Linear types.
+type Intersection struct {
+	Kind IntersectionKind
+	// Point is the intersection point for PointIntersections,
+	// and a point within the overlap for OverlapIntersections.
+	Point Point
+	// Overlap is the shared portion of two colinear inputs,
+	// for OverlapIntersections. It is a Segment if the shared
+	// portion is bounded, and otherwise a Ray or a Line.
+	Overlap Linear
+}
+
+// Intersect returns the intersection of a and b in the
+// xy plane. Whether the inputs are colinear, and whether
+// bounded ends touch the other input, are decided exactly.
+// Returned points take their z value from a.
+func Intersect(a, b Linear) Intersection {
+	p0, p1 := a.Points()
+	q0, q1 := b.Points()
+	if eq2D(p0, p1) {
+		return pointIntersect(p0, b)
+	}
+	if eq2D(q0, q1) {
+		in := pointIntersect(q0, a)
+		if in.Kind == PointIntersection {
+			in.Point[2] = a.Eval(project2D(a, q0)).Z()
+		}
+		return in
+	}
+	o1 := Orient2D(p0, p1, q0)
+	o2 := Orient2D(p0, p1, q1)
+	if o1 == 0 && o2 == 0 {
+		return overlap(a, b)
+	}
+	r1 := Orient2D(q0, q1, p0)
+	r2 := Orient2D(q0, q1, p1)
+	d := sub(p1, p0)
+	e := sub(q1, q0)
+	denom := d[0]*e[1] - d[1]*e[0]
+	if denom == 0 {
+		// Parallel, but not colinear
+		return Intersection{}
+	}
+	w := sub(q0, p0)
+	t := (w[0]*e[1] - w[1]*e[0]) / denom
+	s := (w[0]*d[1] - w[1]*d[0]) / denom
+	// Decide bounded ends exactly where we can
+	switch {
+	case r1 == 0:
+		t = 0
+	case r2 == 0:
+		t = 1
+	}
+	switch {
+	case o1 == 0:
+		s = 0
+	case o2 == 0:
+		s = 1
+	}
+	if !inRange(a, t, r1, r2) || !inRange(b, s, o1, o2) {
+		return Intersection{}
+	}
+	pt := a.Eval(t)
+	switch {
+	case r1 == 0:
+		pt = p0
+	case r2 == 0:
+		pt = p1
+	case o1 == 0:
+		pt[0], pt[1] = q0[0], q0[1]
+	case o2 == 0:
+		pt[0], pt[1] = q1[0], q1[1]
+	}
+	return Intersection{Kind: PointIntersection, Point: pt}
+}
+
+// inRange reports whether t is a valid parameter on l.
+// o1 and o2 are the orientations of l's t=0 and t=1 points
+// against the other input; when l is a Segment they decide
+// the result exactly.
+func inRange(l Linear, t float64, o1, o2 int) bool {
+	if _, ok := l.(Segment); ok {
+		return o1*o2 <= 0
+	}
+	lo, hi := l.Range()
+	return t >= lo && t <= hi
+}
+
+func pointIntersect(p Point, l Linear) Intersection {
+	q0, q1 := l.Points()
+	if eq2D(q0, q1) {
+		if eq2D(p, q0) {
+			return Intersection{Kind: PointIntersection, Point: p}
+		}
+		return Intersection{}
+	}
+	if Orient2D(q0, q1, p) != 0 {
+		return Intersection{}
+	}
+	t := project2D(l, p)
+	lo, hi := l.Range()
+	if t < lo || t > hi {
+		return Intersection{}
+	}
+	return Intersection{Kind: PointIntersection, Point: p}
+}
+
+// overlap intersects two colinear, non-degenerate inputs.
+func overlap(a, b Linear) Intersection {
+	q0, q1 := b.Points()
+	// Map b's range onto a's parameterization
+	s0 := project2D(a, q0)
+	s1 := project2D(a, q1)
+	blo, bhi := b.Range()
+	lo := mapParam(s0, s1, blo)
+	hi := mapParam(s0, s1, bhi)
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	alo, ahi := a.Range()
+	lo = math.Max(lo, alo)
+	hi = math.Min(hi, ahi)
+	if lo > hi {
+		return Intersection{}
+	}
+	if lo == hi {
+		return Intersection{Kind: PointIntersection, Point: a.Eval(lo)}
+	}
+	p0, p1 := a.Points()
+	d := sub(p1, p0)
+	var ov Linear
+	var pt Point
+	switch {
+	case !math.IsInf(lo, 0) && !math.IsInf(hi, 0):
+		ov = Segment{a.Eval(lo), a.Eval(hi)}
+		pt = a.Eval(lo)
+	case !math.IsInf(lo, 0):
+		pt = a.Eval(lo)
+		ov = Ray{pt, d}
+	case !math.IsInf(hi, 0):
+		pt = a.Eval(hi)
+		ov = Ray{pt, scale(d, -1)}
+	default:
+		pt = p0
+		ov = Line{p0, d}
+	}
+	return Intersection{Kind: OverlapIntersection, Point: pt, Overlap: ov}
+}
+
+// mapParam converts parameter v on a linear type whose t=0 and
+// t=1 points lie at s0 and s1 on another into that other's
+// parameterization.
+func mapParam(s0, s1, v float64) float64 {
+	if math.IsInf(v, 0) {
+		if (s1 > s0) == (v > 0) {
+			return math.Inf(1)
+		}
+		return math.Inf(-1)
+	}
+	return s0 + v*(s1-s0)
+}
+
+func project(l Linear, p D3) float64 {
+	p0, p1 := l.Points()
+	d := sub(p1, p0)
+	dd := dot(d, d)
+	if dd == 0 {
+		return 0
+	}
+	return dot(sub(toPoint(p), p0), d) / dd
+}
+
+func project2D(l Linear, p Point) float64 {
+	p0, p1 := l.Points()
+	d := sub(p1, p0)
+	dd := d[0]*d[0] + d[1]*d[1]
+	if dd == 0 {
+		return 0
+	}
+	return ((p[0]-p0[0])*d[0] + (p[1]-p0[1])*d[1]) / dd
+}
+
+func closestPoint(l Linear, p D3) Point {
+	t := project(l, p)
+	lo, hi := l.Range()
+	if t < lo {
+		t = lo
+	} else if t > hi {
+		t = hi
+	}
+	return l.Eval(t)
+}
+
+func toPoint(d D3) Point {
+	if p, ok := d.(Point); ok {
+		return p
+	}
+	return Point{d.X(), d.Y(), d.Z()}
+}
+
+func eval(a, b Point, t float64) Point {
+	// Evaluate from the nearer end so that t=1 returns b exactly
+	if t > .5 {
+		return add(b, scale(sub(a, b), 1-t))
+	}
+	return add(a, scale(sub(b, a), t))
+}
+
+func eq2D(a, b Point) bool {
+	return a[0] == b[0] && a[1] == b[1]
+}
+
+func add(a, b Point) Point {
+	return Point{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func sub(a, b Point) Point {
+	return Point{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func scale(a Point, f float64) Point {
+	return Point{a[0] * f, a[1] * f, a[2] * f}
+}
+
+func dot(a, b Point) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func length(a Point) float64 {
+	return math.Sqrt(dot(a, a))
+}
@@ -0,0 +1,91 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentIntersect(t *testing.T) {
+	s1 := NewSegment(NewPoint(0, 0, 0), NewPoint(2, 2, 0))
+	s2 := NewSegment(NewPoint(0, 2, 0), NewPoint(2, 0, 0))
+	in := s1.Intersect(s2)
+	assert.Equal(t, PointIntersection, in.Kind)
+	assert.True(t, in.Point.EqWithin(NewPoint(1, 1, 0), DefaultTolerance))
+	// Touching at an endpoint
+	s3 := NewSegment(NewPoint(2, 2, 0), NewPoint(3, 0, 0))
+	in = s1.Intersect(s3)
+	assert.Equal(t, PointIntersection, in.Kind)
+	assert.Equal(t, NewPoint(2, 2, 0), in.Point)
+	// Disjoint
+	s4 := NewSegment(NewPoint(3, 3, 0), NewPoint(4, 0, 0))
+	assert.Equal(t, NoIntersection, s1.Intersect(s4).Kind)
+	// Parallel
+	s5 := NewSegment(NewPoint(0, 1, 0), NewPoint(2, 3, 0))
+	assert.Equal(t, NoIntersection, s1.Intersect(s5).Kind)
+	// Colinear overlap
+	s6 := NewSegment(NewPoint(3, 3, 0), NewPoint(1, 1, 0))
+	in = s1.Intersect(s6)
+	assert.Equal(t, OverlapIntersection, in.Kind)
+	a, b := in.Overlap.Points()
+	assert.Equal(t, NewPoint(1, 1, 0), a)
+	assert.Equal(t, NewPoint(2, 2, 0), b)
+	// Colinear, touching
+	s7 := NewSegment(NewPoint(2, 2, 0), NewPoint(3, 3, 0))
+	in = s1.Intersect(s7)
+	assert.Equal(t, PointIntersection, in.Kind)
+	assert.Equal(t, NewPoint(2, 2, 0), in.Point)
+	// Colinear, disjoint
+	s8 := NewSegment(NewPoint(4, 4, 0), NewPoint(3, 3, 0))
+	assert.Equal(t, NoIntersection, s1.Intersect(s8).Kind)
+}
+
+func TestRayLineIntersect(t *testing.T) {
+	r := NewRay(NewPoint(0, 0, 0), NewPoint(1, 0, 0))
+	s := NewSegment(NewPoint(5, -1, 0), NewPoint(5, 1, 0))
+	in := r.Intersect(s)
+	assert.Equal(t, PointIntersection, in.Kind)
+	assert.Equal(t, NewPoint(5, 0, 0), in.Point)
+	behind := NewSegment(NewPoint(-5, -1, 0), NewPoint(-5, 1, 0))
+	assert.Equal(t, NoIntersection, r.Intersect(behind).Kind)
+	l := LineThrough(NewPoint(0, 0, 0), NewPoint(1, 0, 0))
+	assert.Equal(t, PointIntersection, l.Intersect(behind).Kind)
+	// A ray overlapping a line is the ray
+	in = l.Intersect(r)
+	assert.Equal(t, OverlapIntersection, in.Kind)
+	ov, ok := in.Overlap.(Ray)
+	assert.True(t, ok)
+	assert.Equal(t, NewPoint(0, 0, 0), ov.Origin)
+	// Opposing rays overlap in a segment
+	r2 := NewRay(NewPoint(3, 0, 0), NewPoint(-1, 0, 0))
+	in = r.Intersect(r2)
+	assert.Equal(t, OverlapIntersection, in.Kind)
+	_, ok = in.Overlap.(Segment)
+	assert.True(t, ok)
+	// Lines overlapping are lines
+	in = l.Intersect(NewLine(NewPoint(7, 0, 0), NewPoint(-2, 0, 0)))
+	_, ok = in.Overlap.(Line)
+	assert.True(t, ok)
+	// Degenerate segments act as points
+	pt := NewSegment(NewPoint(4, 0, 0), NewPoint(4, 0, 0))
+	assert.Equal(t, PointIntersection, r.Intersect(pt).Kind)
+	assert.Equal(t, PointIntersection, pt.Intersect(r).Kind)
+	assert.Equal(t, NoIntersection, pt.Intersect(behind).Kind)
+}
+
+func TestLinearDistance(t *testing.T) {
+	s := NewSegment(NewPoint(0, 0, 0), NewPoint(4, 0, 0))
+	assert.Equal(t, NewPoint(2, 0, 0), s.Eval(.5))
+	assert.Equal(t, NewPoint(4, 0, 0), s.Eval(1))
+	assert.Equal(t, 4.0, s.Length())
+	p := NewPoint(6, 3, 0)
+	assert.Equal(t, 1.5, s.Project(p))
+	assert.Equal(t, NewPoint(4, 0, 0), s.ClosestPoint(p))
+	assert.Equal(t, math.Sqrt(13), s.Distance(p))
+	r := NewRay(NewPoint(0, 0, 0), NewPoint(4, 0, 0))
+	assert.Equal(t, 3.0, r.Distance(p))
+	assert.Equal(t, math.Sqrt(13), r.Distance(NewPoint(-2, 3, 0)))
+	l := NewLine(NewPoint(0, 0, 0), NewPoint(0, 0, 1))
+	assert.Equal(t, 5.0, l.Distance(NewPoint(3, 4, 100)))
+}
@@ -64,17 +64,15 @@ func (dp Point) Z() float64 {
 	return dp[2]
 }
 
-// Eq returns whether two points are equivalent.
+// Eq returns whether two points are exactly equivalent.
 func (dp Point) Eq(p2 Dimensional) bool {
-	if dp.D() != p2.D() {
-		return false
-	}
-	for i := range dp {
-		if dp[i] != p2.Val(i) {
-			return false
-		}
-	}
-	return true
+	return dp.EqWithin(p2, Tolerance{})
+}
+
+// EqWithin returns whether two points are equivalent
+// under the given tolerance.
+func (dp Point) EqWithin(p2 Dimensional, t Tolerance) bool {
+	return t.Eq(dp, p2)
 }
 
 // Mid2D returns the point in the middle of
@@ -0,0 +1,134 @@
+package geom
+
+import (
+	"math"
+	"strconv"
+)
+
+// A PointN is a point in any number of dimensions.
+// As a D3, a PointN reports zero on any of the x, y and
+// z axes it does not have.
+type PointN []float64
+
+// NewPointN returns a PointN holding the given values.
+// The input values are copied.
+func NewPointN(vs ...float64) PointN {
+	pn := make(PointN, len(vs))
+	copy(pn, vs)
+	return pn
+}
+
+// ToPointN converts any Dimensional into a PointN.
+func ToPointN(d Dimensional) PointN {
+	pn := make(PointN, d.D())
+	for i := range pn {
+		pn[i] = d.Val(i)
+	}
+	return pn
+}
+
+// String converts pn into a string.
+func (pn PointN) String() string {
+	s := "("
+	for i, v := range pn {
+		if i != 0 {
+			s += ", "
+		}
+		s += strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return s + ")"
+}
+
+// Set returns a copy of pn with the value at dimension i
+// set to v.
+func (pn PointN) Set(i int, v float64) Dimensional {
+	pn2 := NewPointN(pn...)
+	pn2[i] = v
+	return pn2
+}
+
+// D returns the number of dimensions in pn.
+func (pn PointN) D() int {
+	return len(pn)
+}
+
+// Val returns the value of pn at dimension d.
+func (pn PointN) Val(d int) float64 {
+	return pn[d]
+}
+
+// X returns the value of pn on the x axis, or 0.
+func (pn PointN) X() float64 {
+	return valOrZero(pn, 0)
+}
+
+// Y returns the value of pn on the y axis, or 0.
+func (pn PointN) Y() float64 {
+	return valOrZero(pn, 1)
+}
+
+// Z returns the value of pn on the z axis, or 0.
+func (pn PointN) Z() float64 {
+	return valOrZero(pn, 2)
+}
+
+// Eq returns whether two points are exactly equivalent.
+func (pn PointN) Eq(p2 Dimensional) bool {
+	return pn.EqWithin(p2, Tolerance{})
+}
+
+// EqWithin returns whether two points are equivalent
+// under the given tolerance.
+func (pn PointN) EqWithin(p2 Dimensional, t Tolerance) bool {
+	return t.Eq(pn, p2)
+}
+
+// Bounds on a PointN returns a SpanN containing
+// only the point itself.
+func (pn PointN) Bounds() SpanN {
+	return NewSpanN(pn.D(), pn)
+}
+
+// Distance returns the euclidean distance between
+// d1 and d2. Dimensions which only one of the inputs
+// has are treated as zero on the other.
+func Distance(d1, d2 Dimensional) float64 {
+	return math.Sqrt(DistanceSquared(d1, d2))
+}
+
+// DistanceSquared returns the square of the euclidean
+// distance between d1 and d2.
+func DistanceSquared(d1, d2 Dimensional) float64 {
+	n := maxD(d1, d2)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		diff := valOrZero(d1, i) - valOrZero(d2, i)
+		sum += diff * diff
+	}
+	return sum
+}
+
+// ManhattanDistance returns the sum of the differences
+// between d1 and d2 in each dimension.
+func ManhattanDistance(d1, d2 Dimensional) float64 {
+	n := maxD(d1, d2)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += math.Abs(valOrZero(d1, i) - valOrZero(d2, i))
+	}
+	return sum
+}
+
+func maxD(d1, d2 Dimensional) int {
+	if d1.D() > d2.D() {
+		return d1.D()
+	}
+	return d2.D()
+}
+
+func valOrZero(d Dimensional, i int) float64 {
+	if i >= d.D() {
+		return 0
+	}
+	return d.Val(i)
+}
@@ -0,0 +1,70 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPointN(t *testing.T) {
+	p := NewPointN(1, 2, 3, 4, 5)
+	assert.Equal(t, 5, p.D())
+	assert.Equal(t, 4.0, p.Val(3))
+	p2 := p.Set(3, 0)
+	assert.Equal(t, 4.0, p.Val(3))
+	assert.Equal(t, 0.0, p2.Val(3))
+	assert.False(t, p.Eq(p2))
+	assert.True(t, p.Eq(NewPointN(1, 2, 3, 4, 5)))
+	assert.False(t, p.Eq(NewPoint(1, 2, 3)))
+	assert.Equal(t, 0.0, NewPointN(1).Y())
+	assert.Equal(t, "(1, 2.5)", NewPointN(1, 2.5).String())
+	assert.Equal(t, 4.0, Distance(p, p2))
+	assert.Equal(t, 5.0, Distance(NewPointN(3, 4), NewPointN()))
+	assert.Equal(t, 7.0, ManhattanDistance(NewPointN(3, 4), NewPoint(0, 0, 0)))
+}
+
+func TestSpanN(t *testing.T) {
+	sp := NewSpanN(4, NewPointN(0, 0, 0, 0), NewPointN(1, 2, 3, 4))
+	assert.Equal(t, 4, sp.D())
+	assert.Equal(t, 24.0, sp.Volume())
+	assert.Equal(t, 10.0, sp.Margin())
+	assert.True(t, sp.Contains(NewPointN(1, 1, 1, 1)))
+	assert.False(t, sp.Contains(NewPointN(1, 1, 1, 5)))
+	sp2 := SpanNFrom(NewPointN(1, 2, 3, 4), NewPointN(2, 3, 4, 5))
+	assert.True(t, sp.Intersects(sp2))
+	in, ok := sp.Intersection(sp2)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, in.Volume())
+	sp3 := SpanNFrom(NewPointN(5, 5, 5, 5), NewPointN(6, 6, 6, 6))
+	assert.False(t, sp.Intersects(sp3))
+	_, ok = sp.Intersection(sp3)
+	assert.False(t, ok)
+	u := sp.Union(sp3)
+	assert.True(t, u.ContainsSpan(sp))
+	assert.True(t, u.ContainsSpan(sp3))
+	// Union did not modify sp
+	assert.Equal(t, 4.0, sp.Max[3])
+	assert.Equal(t, math.Sqrt(4), sp.Distance(NewPointN(1, 2, 3, 6)))
+	assert.Equal(t, 0.0, sp.Distance(NewPointN(.5, .5, .5, .5)))
+	assert.True(t, ToSpanN(NewSpan(NewPoint(1, 2, 3))).Eq(NewPoint(1, 2, 3).Bounds()))
+}
+
+func TestSpanNMixedDimensions(t *testing.T) {
+	sp := SpanNFrom(NewPointN(-1, -1, -1), NewPointN(2, 2, 2))
+	flat := SpanNFrom(NewPointN(0, 0), NewPointN(1, 1))
+	// flat's missing z is zero, which sp covers
+	assert.True(t, sp.Intersects(flat))
+	assert.True(t, sp.ContainsSpan(flat))
+	in, ok := sp.Intersection(flat)
+	assert.True(t, ok)
+	assert.Equal(t, SpanNFrom(NewPointN(0, 0, 0), NewPointN(1, 1, 0)), in)
+	high := SpanNFrom(NewPointN(-1, -1, 1), NewPointN(2, 2, 2))
+	assert.False(t, high.Intersects(flat))
+	assert.False(t, high.ContainsSpan(flat))
+	_, ok = high.Intersection(flat)
+	assert.False(t, ok)
+	// Dimensions beyond sp's are ignored
+	assert.True(t, flat.Intersects(sp))
+	assert.False(t, flat.ContainsSpan(sp))
+}
@@ -0,0 +1,57 @@
+package geom
+
+import compgeo "github.com/200sc/go-compgeo"
+
+// A Projection maps three dimensional points onto a two
+// dimensional view plane. Projected points keep their
+// depth from the viewer in z, so callers can order
+// what they draw without further correction.
+type Projection interface {
+	Project(D3) (Point, error)
+}
+
+// Orthographic is a Projection which rotates points by
+// Rotation, then drops them directly onto the xy plane,
+// scaled by Scale.
+type Orthographic struct {
+	Rotation Quaternion
+	Scale    float64
+}
+
+// NewOrthographic returns an Orthographic projection.
+func NewOrthographic(rot Quaternion, scale float64) Orthographic {
+	return Orthographic{rot.Normalize(), scale}
+}
+
+// Project projects p orthographically. It never fails.
+func (o Orthographic) Project(p D3) (Point, error) {
+	r := o.Rotation.Rotate(p)
+	return Point{r.X() * o.Scale, r.Y() * o.Scale, r.Z()}, nil
+}
+
+// Perspective is a Projection which rotates points by
+// Rotation, then views them from a point Distance units
+// down the negative z axis, looking toward positive z,
+// onto a view plane Focal units in front of that point.
+type Perspective struct {
+	Rotation Quaternion
+	Distance float64
+	Focal    float64
+}
+
+// NewPerspective returns a Perspective projection.
+func NewPerspective(rot Quaternion, distance, focal float64) Perspective {
+	return Perspective{rot.Normalize(), distance, focal}
+}
+
+// Project projects p in perspective. If p is not strictly in
+// front of the viewer, a RangeError is returned.
+func (pr Perspective) Project(p D3) (Point, error) {
+	r := pr.Rotation.Rotate(p)
+	depth := r.Z() + pr.Distance
+	if depth <= 0 {
+		return Point{}, compgeo.RangeError{}
+	}
+	f := pr.Focal / depth
+	return Point{r.X() * f, r.Y() * f, depth}, nil
+}
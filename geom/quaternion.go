@@ -0,0 +1,136 @@
+package geom
+
+import "math"
+
+// A Quaternion represents a rotation in three dimensions,
+// stored as (w, x, y, z) where w is the real part.
+// Quaternions avoid the gimbal lock that composing
+// rotations around individual axes accumulates.
+// The zero Quaternion is treated as the identity rotation.
+type Quaternion [4]float64
+
+// IdentityQuaternion returns the Quaternion which does
+// not rotate.
+func IdentityQuaternion() Quaternion {
+	return Quaternion{1, 0, 0, 0}
+}
+
+// AxisAngle returns the Quaternion which rotates theta radians
+// around axis. If axis has zero length, the identity is returned.
+func AxisAngle(axis D3, theta float64) Quaternion {
+	x, y, z := axis.X(), axis.Y(), axis.Z()
+	l := math.Sqrt(x*x + y*y + z*z)
+	if l == 0 {
+		return IdentityQuaternion()
+	}
+	s := math.Sin(theta/2) / l
+	return Quaternion{math.Cos(theta / 2), x * s, y * s, z * s}
+}
+
+// W returns the real part of q.
+func (q Quaternion) W() float64 {
+	return q[0]
+}
+
+// X returns the i component of q.
+func (q Quaternion) X() float64 {
+	return q[1]
+}
+
+// Y returns the j component of q.
+func (q Quaternion) Y() float64 {
+	return q[2]
+}
+
+// Z returns the k component of q.
+func (q Quaternion) Z() float64 {
+	return q[3]
+}
+
+// Mul returns the Hamilton product q * q2. As a rotation,
+// this is the rotation which applies q2, then q.
+func (q Quaternion) Mul(q2 Quaternion) Quaternion {
+	return Quaternion{
+		q[0]*q2[0] - q[1]*q2[1] - q[2]*q2[2] - q[3]*q2[3],
+		q[0]*q2[1] + q[1]*q2[0] + q[2]*q2[3] - q[3]*q2[2],
+		q[0]*q2[2] - q[1]*q2[3] + q[2]*q2[0] + q[3]*q2[1],
+		q[0]*q2[3] + q[1]*q2[2] - q[2]*q2[1] + q[3]*q2[0],
+	}
+}
+
+// Conj returns the conjugate of q, which for unit
+// quaternions is the inverse rotation.
+func (q Quaternion) Conj() Quaternion {
+	return Quaternion{q[0], -q[1], -q[2], -q[3]}
+}
+
+// Norm returns the length of q.
+func (q Quaternion) Norm() float64 {
+	return math.Sqrt(q[0]*q[0] + q[1]*q[1] + q[2]*q[2] + q[3]*q[3])
+}
+
+// Normalize returns q scaled to unit length, or the
+// identity if q is zero.
+func (q Quaternion) Normalize() Quaternion {
+	n := q.Norm()
+	if n == 0 {
+		return IdentityQuaternion()
+	}
+	return Quaternion{q[0] / n, q[1] / n, q[2] / n, q[3] / n}
+}
+
+// Rotate returns p rotated by q.
+func (q Quaternion) Rotate(p D3) Point {
+	q = q.Normalize()
+	w, ux, uy, uz := q[0], q[1], q[2], q[3]
+	px, py, pz := p.X(), p.Y(), p.Z()
+	// t = 2 * (u x p)
+	tx := 2 * (uy*pz - uz*py)
+	ty := 2 * (uz*px - ux*pz)
+	tz := 2 * (ux*py - uy*px)
+	// p + w*t + u x t
+	return Point{
+		px + w*tx + (uy*tz - uz*ty),
+		py + w*ty + (uz*tx - ux*tz),
+		pz + w*tz + (ux*ty - uy*tx),
+	}
+}
+
+// Affine3 returns the rotation matrix equivalent to q.
+func (q Quaternion) Affine3() Affine3 {
+	q = q.Normalize()
+	w, x, y, z := q[0], q[1], q[2], q[3]
+	return Affine3{
+		{1 - 2*(y*y+z*z), 2 * (x*y - z*w), 2 * (x*z + y*w), 0},
+		{2 * (x*y + z*w), 1 - 2*(x*x+z*z), 2 * (y*z - x*w), 0},
+		{2 * (x*z - y*w), 2 * (y*z + x*w), 1 - 2*(x*x+y*y), 0},
+	}
+}
+
+// Slerp spherically interpolates between q and q2, returning
+// q at t = 0 and q2 at t = 1.
+func (q Quaternion) Slerp(q2 Quaternion, t float64) Quaternion {
+	q = q.Normalize()
+	q2 = q2.Normalize()
+	dot := q[0]*q2[0] + q[1]*q2[1] + q[2]*q2[2] + q[3]*q2[3]
+	// Take the shorter path around the sphere
+	if dot < 0 {
+		dot = -dot
+		q2 = Quaternion{-q2[0], -q2[1], -q2[2], -q2[3]}
+	}
+	var s1, s2 float64
+	if dot > 1-1e-9 {
+		// Nearly identical, interpolate linearly
+		s1, s2 = 1-t, t
+	} else {
+		theta := math.Acos(dot)
+		st := math.Sin(theta)
+		s1 = math.Sin((1-t)*theta) / st
+		s2 = math.Sin(t*theta) / st
+	}
+	var out Quaternion
+	for i := range out {
+		out[i] = s1*q[i] + s2*q2[i]
+	}
+	return out.Normalize()
+}
@@ -0,0 +1,42 @@
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuaternionRotate(t *testing.T) {
+	p := NewPoint(1, 2, 3)
+	for _, axis := range []Point{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 2, -1}} {
+		q := AxisAngle(axis, .7)
+		assert.True(t, q.Rotate(p).EqWithin(q.Affine3().Transform(p), DefaultTolerance))
+		assert.True(t, q.Conj().Rotate(q.Rotate(p)).EqWithin(p, DefaultTolerance))
+	}
+	q := AxisAngle(NewPoint(0, 0, 1), math.Pi/2)
+	assert.True(t, q.Rotate(NewPoint(1, 0, 0)).EqWithin(NewPoint(0, 1, 0), DefaultTolerance))
+	assert.True(t, RotateZ3(math.Pi/2).Transform(p).EqWithin(q.Rotate(p), DefaultTolerance))
+	q2 := AxisAngle(NewPoint(1, 0, 0), math.Pi/2)
+	both := q2.Mul(q)
+	assert.True(t, both.Rotate(p).EqWithin(q2.Rotate(q.Rotate(p)), DefaultTolerance))
+	assert.Equal(t, p, Quaternion{}.Rotate(p))
+	half := IdentityQuaternion().Slerp(q, .5)
+	assert.True(t, half.Rotate(p).EqWithin(AxisAngle(NewPoint(0, 0, 1), math.Pi/4).Rotate(p), DefaultTolerance))
+}
+
+func TestProjection(t *testing.T) {
+	o := NewOrthographic(IdentityQuaternion(), 2)
+	p, err := o.Project(NewPoint(1, 2, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, NewPoint(2, 4, 3), p)
+	pr := NewPerspective(IdentityQuaternion(), 10, 5)
+	p, err = pr.Project(NewPoint(2, 4, 0))
+	assert.Nil(t, err)
+	assert.True(t, p.EqWithin(NewPoint(1, 2, 10), DefaultTolerance))
+	// Farther points appear closer to the center
+	p2, _ := pr.Project(NewPoint(2, 4, 10))
+	assert.True(t, p2.X() < p.X())
+	_, err = pr.Project(NewPoint(0, 0, -10))
+	assert.NotNil(t, err)
+}
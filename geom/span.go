@@ -47,18 +47,16 @@ func (sp Span) High(i int) Dimensional {
 	return sp.At(SPAN_MAX)
 }
 
-// Eq returns whether Span is equivalent to
+// Eq returns whether Span is exactly equivalent to
 // the given spanning type
 func (sp Span) Eq(s Spanning) bool {
-	if sp.D() != s.D() {
-		return false
-	}
-	for i := 0; i < sp.D(); i++ {
-		if !sp.At(i).Eq(s.At(i)) {
-			return false
-		}
-	}
-	return true
+	return sp.EqWithin(s, Tolerance{})
+}
+
+// EqWithin returns whether Span is equivalent to
+// the given spanning type under the given tolerance
+func (sp Span) EqWithin(s Spanning, t Tolerance) bool {
+	return t.SpanEq(sp, s)
 }
 
 // Set , which you should probably not use on a Span,
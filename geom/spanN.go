@@ -0,0 +1,223 @@
+package geom
+
+import "math"
+
+// A SpanN is an axis aligned box in any number of
+// dimensions, from Min to Max.
+type SpanN struct {
+	Min, Max PointN
+}
+
+// NewSpanN returns a d dimensional span with its values
+// set to appropriate infinities, expanded to hold ds.
+func NewSpanN(d int, ds ...Dimensional) SpanN {
+	sp := SpanN{make(PointN, d), make(PointN, d)}
+	for i := 0; i < d; i++ {
+		sp.Min[i] = Inf
+		sp.Max[i] = NegInf
+	}
+	return sp.Expand(ds...)
+}
+
+// SpanNFrom returns the SpanN between the two given points.
+// The points do not need to be ordered.
+func SpanNFrom(p1, p2 Dimensional) SpanN {
+	return NewSpanN(maxD(p1, p2), p1, p2)
+}
+
+// ToSpanN converts any Spanning type into a SpanN
+// covering every point in it.
+func ToSpanN(s Spanning) SpanN {
+	sp := NewSpanN(s.D())
+	for i := 0; i < s.Len(); i++ {
+		sp = sp.Expand(s.At(i))
+	}
+	return sp
+}
+
+// D returns the number of dimensions in sp.
+func (sp SpanN) D() int {
+	return len(sp.Min)
+}
+
+// Len returns the number of points on sp (2)
+func (sp SpanN) Len() int {
+	return 2
+}
+
+// At returns Min at SPAN_MIN and Max at SPAN_MAX.
+func (sp SpanN) At(i int) Dimensional {
+	if i == SPAN_MIN {
+		return sp.Min
+	}
+	return sp.Max
+}
+
+// Set returns a copy of sp with Min or Max replaced by d.
+func (sp SpanN) Set(i int, d Dimensional) Spanning {
+	if i == SPAN_MIN {
+		sp.Min = ToPointN(d)
+	} else {
+		sp.Max = ToPointN(d)
+	}
+	return sp
+}
+
+// Low returns Min
+func (sp SpanN) Low(i int) Dimensional {
+	return sp.Min
+}
+
+// High returns Max
+func (sp SpanN) High(i int) Dimensional {
+	return sp.Max
+}
+
+// Eq returns whether sp is exactly equivalent to
+// the given spanning type
+func (sp SpanN) Eq(s Spanning) bool {
+	return sp.EqWithin(s, Tolerance{})
+}
+
+// EqWithin returns whether sp is equivalent to
+// the given spanning type under the given tolerance
+func (sp SpanN) EqWithin(s Spanning, t Tolerance) bool {
+	return t.SpanEq(sp, s)
+}
+
+// Expand returns sp, grown to contain each of ps.
+// Dimensions of ps beyond sp's are ignored.
+func (sp SpanN) Expand(ps ...Dimensional) SpanN {
+	sp = sp.copy()
+	for _, p := range ps {
+		for i := 0; i < sp.D() && i < p.D(); i++ {
+			v := p.Val(i)
+			if v < sp.Min[i] {
+				sp.Min[i] = v
+			}
+			if v > sp.Max[i] {
+				sp.Max[i] = v
+			}
+		}
+	}
+	return sp
+}
+
+// Union returns the smallest span containing sp and sp2.
+func (sp SpanN) Union(sp2 SpanN) SpanN {
+	return sp.Expand(sp2.Min, sp2.Max)
+}
+
+// Intersection returns the span shared by sp and sp2,
+// and whether that span is non-empty. As in Contains,
+// dimensions sp2 lacks are treated as zero.
+func (sp SpanN) Intersection(sp2 SpanN) (SpanN, bool) {
+	out := sp.copy()
+	for i := 0; i < out.D(); i++ {
+		out.Min[i] = math.Max(sp.Min[i], valOrZero(sp2.Min, i))
+		out.Max[i] = math.Min(sp.Max[i], valOrZero(sp2.Max, i))
+		if out.Min[i] > out.Max[i] {
+			return out, false
+		}
+	}
+	return out, true
+}
+
+// Intersects returns whether sp and sp2 share any point.
+// Spans which only touch on their boundaries intersect.
+// Dimensions sp2 lacks are treated as zero.
+func (sp SpanN) Intersects(sp2 SpanN) bool {
+	for i := 0; i < sp.D(); i++ {
+		if sp.Min[i] > valOrZero(sp2.Max, i) || valOrZero(sp2.Min, i) > sp.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns whether p lies within or on sp.
+func (sp SpanN) Contains(p Dimensional) bool {
+	for i := 0; i < sp.D(); i++ {
+		v := valOrZero(p, i)
+		if v < sp.Min[i] || v > sp.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsSpan returns whether sp2 lies entirely within sp.
+// Dimensions sp2 lacks are treated as zero.
+func (sp SpanN) ContainsSpan(sp2 SpanN) bool {
+	for i := 0; i < sp.D(); i++ {
+		if valOrZero(sp2.Min, i) < sp.Min[i] || valOrZero(sp2.Max, i) > sp.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the extent of sp in each dimension.
+func (sp SpanN) Diff() PointN {
+	d := make(PointN, sp.D())
+	for i := range d {
+		d[i] = sp.Max[i] - sp.Min[i]
+	}
+	return d
+}
+
+// Center returns the point in the middle of sp.
+func (sp SpanN) Center() PointN {
+	c := make(PointN, sp.D())
+	for i := range c {
+		c[i] = (sp.Min[i] + sp.Max[i]) / 2
+	}
+	return c
+}
+
+// Volume returns the product of sp's extents. Spans which
+// are flat in any dimension have zero volume.
+func (sp SpanN) Volume() float64 {
+	v := 1.0
+	for _, d := range sp.Diff() {
+		v *= d
+	}
+	return v
+}
+
+// Margin returns the sum of sp's extents.
+func (sp SpanN) Margin() float64 {
+	m := 0.0
+	for _, d := range sp.Diff() {
+		m += d
+	}
+	return m
+}
+
+// DistanceSquared returns the squared euclidean distance
+// from p to the closest point on sp, which is zero if sp
+// contains p.
+func (sp SpanN) DistanceSquared(p Dimensional) float64 {
+	sum := 0.0
+	for i := 0; i < sp.D(); i++ {
+		v := valOrZero(p, i)
+		var diff float64
+		if v < sp.Min[i] {
+			diff = sp.Min[i] - v
+		} else if v > sp.Max[i] {
+			diff = v - sp.Max[i]
+		}
+		sum += diff * diff
+	}
+	return sum
+}
+
+// Distance returns the euclidean distance from p to
+// the closest point on sp.
+func (sp SpanN) Distance(p Dimensional) float64 {
+	return math.Sqrt(sp.DistanceSquared(p))
+}
+
+func (sp SpanN) copy() SpanN {
+	return SpanN{NewPointN(sp.Min...), NewPointN(sp.Max...)}
+}
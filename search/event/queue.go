@@ -0,0 +1,164 @@
+// package event defines a priority queue of events keyed
+// by points, for sweep algorithms which need to add, move
+// and remove events as they sweep.
+
+package event
+
+import (
+	"container/heap"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A LessFn reports whether a should be processed before b.
+type LessFn func(a, b geom.Dimensional) bool
+
+// Lexicographic returns a LessFn which orders points by
+// their values in dimensions ds, in the order given.
+// To sweep left to right, breaking ties on lesser y,
+// use with (0,1).
+func Lexicographic(ds ...int) LessFn {
+	return func(a, b geom.Dimensional) bool {
+		for _, d := range ds {
+			v1 := a.Val(d)
+			v2 := b.Val(d)
+			if v1 != v2 {
+				return v1 < v2
+			}
+		}
+		return false
+	}
+}
+
+// An Event is an entry in a Queue. The Queue owns an
+// Event's Point; change it through Queue.Update.
+type Event struct {
+	Point geom.Dimensional
+	Val   interface{}
+	// index is this event's position in the queue, or -1
+	// once it has been removed.
+	index int
+	seq   uint64
+}
+
+// Queued returns whether e is still in its queue.
+func (e *Event) Queued() bool {
+	return e.index >= 0
+}
+
+// A Queue is a binary heap of Events. Events at equal
+// points are returned in the order they were pushed.
+type Queue struct {
+	less   LessFn
+	events []*Event
+	seq    uint64
+}
+
+// New returns an empty Queue ordered by less.
+func New(less LessFn) *Queue {
+	return &Queue{less: less}
+}
+
+// Len returns the number of events in q.
+func (q *Queue) Len() int {
+	return len(q.events)
+}
+
+// Push adds an event at p holding v to q, returning
+// the event so that it can later be updated or removed.
+func (q *Queue) Push(p geom.Dimensional, v interface{}) *Event {
+	q.seq++
+	e := &Event{Point: p, Val: v, seq: q.seq}
+	heap.Push((*eventHeap)(q), e)
+	return e
+}
+
+// Peek returns the next event in q without removing it,
+// or nil if q is empty.
+func (q *Queue) Peek() *Event {
+	if len(q.events) == 0 {
+		return nil
+	}
+	return q.events[0]
+}
+
+// Pop removes and returns the next event in q, or nil if
+// q is empty.
+func (q *Queue) Pop() *Event {
+	if len(q.events) == 0 {
+		return nil
+	}
+	return heap.Pop((*eventHeap)(q)).(*Event)
+}
+
+// Update moves e to point p.
+func (q *Queue) Update(e *Event, p geom.Dimensional) error {
+	if !q.holds(e) {
+		return compgeo.RangeError{}
+	}
+	e.Point = p
+	heap.Fix((*eventHeap)(q), e.index)
+	return nil
+}
+
+// DecreaseKey moves e to point p, which must not come
+// after e's current point.
+func (q *Queue) DecreaseKey(e *Event, p geom.Dimensional) error {
+	if q.less(e.Point, p) {
+		return compgeo.RangeError{}
+	}
+	return q.Update(e, p)
+}
+
+// Remove removes e from q.
+func (q *Queue) Remove(e *Event) error {
+	if !q.holds(e) {
+		return compgeo.RangeError{}
+	}
+	heap.Remove((*eventHeap)(q), e.index)
+	return nil
+}
+
+func (q *Queue) holds(e *Event) bool {
+	return e.index >= 0 && e.index < len(q.events) && q.events[e.index] == e
+}
+
+// eventHeap implements heap.Interface for a Queue.
+type eventHeap Queue
+
+func (h *eventHeap) Len() int {
+	return len(h.events)
+}
+
+func (h *eventHeap) Less(i, j int) bool {
+	a, b := h.events[i], h.events[j]
+	if h.less(a.Point, b.Point) {
+		return true
+	}
+	if h.less(b.Point, a.Point) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (h *eventHeap) Swap(i, j int) {
+	h.events[i], h.events[j] = h.events[j], h.events[i]
+	h.events[i].index = i
+	h.events[j].index = j
+}
+
+func (h *eventHeap) Push(x interface{}) {
+	e := x.(*Event)
+	e.index = len(h.events)
+	h.events = append(h.events, e)
+}
+
+func (h *eventHeap) Pop() interface{} {
+	n := len(h.events)
+	e := h.events[n-1]
+	h.events[n-1] = nil
+	h.events = h.events[:n-1]
+	e.index = -1
+	return e
+}
@@ -0,0 +1,51 @@
+package event
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueOrder(t *testing.T) {
+	rand.Seed(1)
+	q := New(Lexicographic(0, 1))
+	for i := 0; i < 200; i++ {
+		q.Push(geom.NewPoint(float64(rand.Intn(10)), float64(rand.Intn(10)), 0), i)
+	}
+	prev := q.Pop()
+	for q.Len() > 0 {
+		e := q.Pop()
+		assert.False(t, Lexicographic(0, 1)(e.Point, prev.Point))
+		if e.Point.Eq(prev.Point) {
+			// Equal points keep insertion order
+			assert.True(t, e.Val.(int) > prev.Val.(int))
+		}
+		assert.False(t, e.Queued())
+		prev = e
+	}
+	assert.Nil(t, q.Pop())
+	assert.Nil(t, q.Peek())
+}
+
+func TestQueueUpdate(t *testing.T) {
+	q := New(Lexicographic(1, 0))
+	a := q.Push(geom.NewPoint(0, 5, 0), "a")
+	b := q.Push(geom.NewPoint(0, 3, 0), "b")
+	c := q.Push(geom.NewPoint(0, 4, 0), "c")
+	assert.Equal(t, b, q.Peek())
+	assert.Nil(t, q.DecreaseKey(a, geom.NewPoint(0, 1, 0)))
+	assert.Equal(t, a, q.Peek())
+	assert.NotNil(t, q.DecreaseKey(c, geom.NewPoint(0, 9, 0)))
+	assert.Nil(t, q.Update(c, geom.NewPoint(0, 9, 0)))
+	assert.Nil(t, q.Remove(b))
+	assert.NotNil(t, q.Remove(b))
+	assert.Equal(t, a, q.Pop())
+	// Inserting events mid sweep
+	q.Push(geom.NewPoint(1, 7, 0), "d")
+	assert.Equal(t, "d", q.Pop().Val)
+	assert.Equal(t, c, q.Pop())
+	assert.NotNil(t, q.Update(c, geom.NewPoint(0, 0, 0)))
+	assert.Equal(t, 0, q.Len())
+}
@@ -0,0 +1,277 @@
+// package interval defines a dynamic interval tree, for finding
+// which of a set of one dimensional intervals contain a value
+// or overlap a range.
+
+package interval
+
+import (
+	"errors"
+
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// An Interval is a closed range of values. Intervals are
+// identified by equality on deletion, so they should be
+// comparable.
+type Interval interface {
+	Extent() (lo, hi float64)
+}
+
+// Span is a plain Interval from Lo to Hi.
+type Span struct {
+	Lo, Hi float64
+}
+
+// NewSpan returns a Span covering a and b, which do
+// not need to be ordered.
+func NewSpan(a, b float64) Span {
+	if b < a {
+		a, b = b, a
+	}
+	return Span{a, b}
+}
+
+// Extent returns Lo and Hi.
+func (s Span) Extent() (float64, float64) {
+	return s.Lo, s.Hi
+}
+
+// Spanning is an Interval over the extent of a
+// geom.Spanning type, like a geom.FullEdge, in
+// dimension D.
+type Spanning struct {
+	geom.Spanning
+	D int
+}
+
+// Of returns the Interval over s's extent in dimension d.
+func Of(s geom.Spanning, d int) Spanning {
+	return Spanning{s, d}
+}
+
+// Extent returns the lowest and highest values of
+// the spanning type's points in dimension D.
+func (s Spanning) Extent() (float64, float64) {
+	return s.Spanning.Low(s.D).Val(s.D), s.Spanning.High(s.D).Val(s.D)
+}
+
+// Tree is an interval tree: a balanced binary search tree
+// of intervals ordered by their low ends, where each node
+// knows the highest end beneath it. Stabbing and overlap
+// queries take O(log n + k) time.
+type Tree struct {
+	root *node
+	size int
+	seq  uint64
+}
+
+type node struct {
+	iv     Interval
+	lo, hi float64
+	// seq breaks ties between equal extents
+	seq         uint64
+	max         float64
+	height      int
+	left, right *node
+}
+
+// New returns an empty interval Tree.
+func New() *Tree {
+	return &Tree{}
+}
+
+// Size returns the number of intervals in t.
+func (t *Tree) Size() int {
+	return t.size
+}
+
+// Insert adds iv to t.
+func (t *Tree) Insert(iv Interval) {
+	lo, hi := iv.Extent()
+	t.seq++
+	t.root = insert(t.root, &node{
+		iv:     iv,
+		lo:     lo,
+		hi:     hi,
+		seq:    t.seq,
+		max:    hi,
+		height: 1,
+	})
+	t.size++
+}
+
+// Delete removes iv from t.
+func (t *Tree) Delete(iv Interval) error {
+	lo, hi := iv.Extent()
+	n := find(t.root, iv, lo, hi)
+	if n == nil {
+		return errors.New("Interval not found")
+	}
+	t.root = remove(t.root, n)
+	t.size--
+	return nil
+}
+
+// Stab returns every interval in t containing x.
+func (t *Tree) Stab(x float64) []Interval {
+	return t.Overlap(x, x)
+}
+
+// Overlap returns every interval in t which shares
+// any value with [lo, hi].
+func (t *Tree) Overlap(lo, hi float64) []Interval {
+	out := []Interval{}
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil || n.max < lo {
+			return
+		}
+		search(n.left)
+		// Everything right of n starts at or after n
+		if n.lo > hi {
+			return
+		}
+		if n.hi >= lo {
+			out = append(out, n.iv)
+		}
+		search(n.right)
+	}
+	search(t.root)
+	return out
+}
+
+// All returns every interval in t, ordered by their low ends.
+func (t *Tree) All() []Interval {
+	return t.Overlap(geom.NegInf, geom.Inf)
+}
+
+func (n *node) less(n2 *node) bool {
+	if n.lo != n2.lo {
+		return n.lo < n2.lo
+	}
+	if n.hi != n2.hi {
+		return n.hi < n2.hi
+	}
+	return n.seq < n2.seq
+}
+
+func height(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recalculates n's height and max from its children.
+func (n *node) update() {
+	n.height = height(n.left)
+	if h := height(n.right); h > n.height {
+		n.height = h
+	}
+	n.height++
+	n.max = n.hi
+	if n.left != nil && n.left.max > n.max {
+		n.max = n.left.max
+	}
+	if n.right != nil && n.right.max > n.max {
+		n.max = n.right.max
+	}
+}
+
+func rotateLeft(n *node) *node {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func rotateRight(n *node) *node {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// balance restores the AVL property at n.
+func balance(n *node) *node {
+	n.update()
+	bf := height(n.left) - height(n.right)
+	if bf > 1 {
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	}
+	if bf < -1 {
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
+
+func insert(n, in *node) *node {
+	if n == nil {
+		return in
+	}
+	if in.less(n) {
+		n.left = insert(n.left, in)
+	} else {
+		n.right = insert(n.right, in)
+	}
+	return balance(n)
+}
+
+// find returns the node holding iv, which has extent [lo, hi].
+func find(n *node, iv Interval, lo, hi float64) *node {
+	for n != nil {
+		if lo < n.lo || (lo == n.lo && hi < n.hi) {
+			n = n.left
+		} else if lo > n.lo || hi > n.hi {
+			n = n.right
+		} else {
+			// Equal extents may lie on either side
+			if n.iv == iv {
+				return n
+			}
+			if f := find(n.left, iv, lo, hi); f != nil {
+				return f
+			}
+			n = n.right
+		}
+	}
+	return nil
+}
+
+// remove removes target from the subtree at n.
+func remove(n, target *node) *node {
+	if n == nil {
+		return nil
+	}
+	if n == target {
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		m := n.right
+		for m.left != nil {
+			m = m.left
+		}
+		m.right = remove(n.right, m)
+		m.left = n.left
+		return balance(m)
+	}
+	if target.less(n) {
+		n.left = remove(n.left, target)
+	} else {
+		n.right = remove(n.right, target)
+	}
+	return balance(n)
+}
@@ -0,0 +1,100 @@
+package interval
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func randSpans(n int) []Interval {
+	ivs := make([]Interval, n)
+	for i := range ivs {
+		lo := float64(rand.Intn(100))
+		// Pointers, so that equal spans are distinct
+		ivs[i] = &Span{lo, lo + float64(rand.Intn(20))}
+	}
+	return ivs
+}
+
+func bruteOverlap(ivs []Interval, lo, hi float64) int {
+	ct := 0
+	for _, iv := range ivs {
+		a, b := iv.Extent()
+		if a <= hi && b >= lo {
+			ct++
+		}
+	}
+	return ct
+}
+
+// checkTree verifies t's order, balance and max values.
+func checkTree(t *testing.T, n *node) (int, float64) {
+	if n == nil {
+		return 0, geom.NegInf
+	}
+	lh, lm := checkTree(t, n.left)
+	rh, rm := checkTree(t, n.right)
+	if n.left != nil {
+		assert.True(t, n.left.less(n))
+	}
+	if n.right != nil {
+		assert.True(t, n.less(n.right))
+	}
+	assert.True(t, lh-rh <= 1 && rh-lh <= 1)
+	max := n.hi
+	if lm > max {
+		max = lm
+	}
+	if rm > max {
+		max = rm
+	}
+	assert.Equal(t, max, n.max)
+	h := lh
+	if rh > h {
+		h = rh
+	}
+	return h + 1, max
+}
+
+func TestIntervalTree(t *testing.T) {
+	rand.Seed(1)
+	ivs := randSpans(500)
+	tr := New()
+	for _, iv := range ivs {
+		tr.Insert(iv)
+	}
+	checkTree(t, tr.root)
+	assert.Equal(t, 500, tr.Size())
+	for i := 0; i < 50; i++ {
+		x := float64(rand.Intn(130))
+		assert.Equal(t, bruteOverlap(ivs, x, x), len(tr.Stab(x)))
+		y := x + float64(rand.Intn(10))
+		assert.Equal(t, bruteOverlap(ivs, x, y), len(tr.Overlap(x, y)))
+	}
+	for _, iv := range ivs[:300] {
+		assert.Nil(t, tr.Delete(iv))
+	}
+	checkTree(t, tr.root)
+	assert.NotNil(t, tr.Delete(ivs[0]))
+	rest := ivs[300:]
+	assert.Equal(t, len(rest), len(tr.All()))
+	for i := 0; i < 50; i++ {
+		x := float64(rand.Intn(130))
+		assert.Equal(t, bruteOverlap(rest, x, x), len(tr.Stab(x)))
+	}
+}
+
+func TestIntervalOf(t *testing.T) {
+	fe := geom.FullEdge{geom.NewPoint(4, 1, 0), geom.NewPoint(2, 3, 0)}
+	tr := New()
+	tr.Insert(Of(fe, 0))
+	tr.Insert(NewSpan(5, 3))
+	assert.Equal(t, 2, len(tr.Stab(3)))
+	res := tr.Stab(2)
+	assert.Equal(t, 1, len(res))
+	assert.Equal(t, fe, res[0].(Spanning).Spanning)
+	assert.Nil(t, tr.Delete(Of(fe, 0)))
+	assert.Equal(t, 1, tr.Size())
+}
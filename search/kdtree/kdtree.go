@@ -0,0 +1,204 @@
+// package kdtree defines a k-dimensional tree over geom.Dimensional
+// points, supporting nearest neighbor, radius and range queries.
+
+package kdtree
+
+import (
+	"errors"
+	"sort"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A KDTree splits space on one axis per level, cycling
+// through its dimensions. It stores geom.Dimensionals,
+// which will be returned as-is from queries, so callers
+// can store types like *dcel.Vertex and convert results
+// back.
+type KDTree struct {
+	root *node
+	d    int
+	size int
+}
+
+type node struct {
+	p           geom.Dimensional
+	axis        int
+	left, right *node
+}
+
+// New returns a KDTree over d dimensions, bulk built
+// from ps so that it starts balanced. If any of ps have
+// less than d dimensions, an InsufficientDimensionsError
+// is returned.
+func New(d int, ps []geom.Dimensional) (*KDTree, error) {
+	if d < 1 {
+		return nil, compgeo.BadDimensionError{}
+	}
+	for _, p := range ps {
+		if p.D() < d {
+			return nil, compgeo.InsufficientDimensionsError{}
+		}
+	}
+	kd := &KDTree{d: d, size: len(ps)}
+	cp := make([]geom.Dimensional, len(ps))
+	copy(cp, ps)
+	kd.root = kd.build(cp, 0)
+	return kd, nil
+}
+
+func (kd *KDTree) build(ps []geom.Dimensional, axis int) *node {
+	if len(ps) == 0 {
+		return nil
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Val(axis) < ps[j].Val(axis)
+	})
+	m := len(ps) / 2
+	// Points equal to the median on this axis must go right,
+	// so the median is the first point with its value.
+	for m > 0 && ps[m-1].Val(axis) == ps[m].Val(axis) {
+		m--
+	}
+	next := (axis + 1) % kd.d
+	return &node{
+		p:     ps[m],
+		axis:  axis,
+		left:  kd.build(ps[:m], next),
+		right: kd.build(ps[m+1:], next),
+	}
+}
+
+// D returns the number of dimensions kd splits on.
+func (kd *KDTree) D() int {
+	return kd.d
+}
+
+// Size returns the number of points in kd.
+func (kd *KDTree) Size() int {
+	return kd.size
+}
+
+// Insert adds p to kd. Inserting does not rebalance
+// the tree.
+func (kd *KDTree) Insert(p geom.Dimensional) error {
+	if p.D() < kd.d {
+		return compgeo.InsufficientDimensionsError{}
+	}
+	kd.size++
+	if kd.root == nil {
+		kd.root = &node{p: p}
+		return nil
+	}
+	n := kd.root
+	for {
+		next := (n.axis + 1) % kd.d
+		if p.Val(n.axis) < n.p.Val(n.axis) {
+			if n.left == nil {
+				n.left = &node{p: p, axis: next}
+				return nil
+			}
+			n = n.left
+		} else {
+			if n.right == nil {
+				n.right = &node{p: p, axis: next}
+				return nil
+			}
+			n = n.right
+		}
+	}
+}
+
+// Delete removes a point equal to p from kd.
+func (kd *KDTree) Delete(p geom.Dimensional) error {
+	if p.D() < kd.d {
+		return compgeo.InsufficientDimensionsError{}
+	}
+	var ok bool
+	kd.root, ok = kd.delete(kd.root, p, func(n *node) bool {
+		return n.p.Eq(p)
+	})
+	if !ok {
+		return errors.New("Point not found")
+	}
+	kd.size--
+	return nil
+}
+
+// delete removes the first node matching match on the path
+// to p from n, returning the new root of n's subtree.
+func (kd *KDTree) delete(n *node, p geom.Dimensional, match func(*node) bool) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+	var ok bool
+	if match(n) {
+		if n.right != nil {
+			m := kd.min(n.right, n.axis)
+			mp := m.p
+			n.right, _ = kd.delete(n.right, mp, isNode(m))
+			n.p = mp
+		} else if n.left != nil {
+			// Move the left subtree right, as points equal to
+			// its minimum on this axis need to be on the right.
+			m := kd.min(n.left, n.axis)
+			mp := m.p
+			n.right, _ = kd.delete(n.left, mp, isNode(m))
+			n.left = nil
+			n.p = mp
+		} else {
+			return nil, true
+		}
+		return n, true
+	}
+	if p.Val(n.axis) < n.p.Val(n.axis) {
+		n.left, ok = kd.delete(n.left, p, match)
+	} else {
+		n.right, ok = kd.delete(n.right, p, match)
+	}
+	return n, ok
+}
+
+func isNode(m *node) func(*node) bool {
+	return func(n *node) bool {
+		return n == m
+	}
+}
+
+// min returns the node under n with the lowest value on axis.
+func (kd *KDTree) min(n *node, axis int) *node {
+	if n == nil {
+		return nil
+	}
+	if n.axis == axis {
+		// Everything right of n is at least n's value
+		if n.left == nil {
+			return n
+		}
+		return kd.min(n.left, axis)
+	}
+	best := n
+	for _, c := range []*node{kd.min(n.left, axis), kd.min(n.right, axis)} {
+		if c != nil && c.p.Val(axis) < best.p.Val(axis) {
+			best = c
+		}
+	}
+	return best
+}
+
+// Points returns every point in kd, in no particular order.
+func (kd *KDTree) Points() []geom.Dimensional {
+	out := make([]geom.Dimensional, 0, kd.size)
+	var walk func(*node)
+	walk = func(n *node) {
+		if n == nil {
+			return
+		}
+		out = append(out, n.p)
+		walk(n.left)
+		walk(n.right)
+	}
+	walk(kd.root)
+	return out
+}
@@ -0,0 +1,116 @@
+package kdtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func randPoints(n, d int) []geom.Dimensional {
+	ps := make([]geom.Dimensional, n)
+	for i := range ps {
+		p := make(geom.PointN, d)
+		for j := range p {
+			// Small integer coordinates, to test ties
+			p[j] = float64(rand.Intn(20))
+		}
+		ps[i] = p
+	}
+	return ps
+}
+
+func bruteNearest(ps []geom.Dimensional, q geom.Dimensional, k int, m Metric) []float64 {
+	ds := make([]float64, len(ps))
+	for i, p := range ps {
+		ds[i] = m(q, p)
+	}
+	sort.Float64s(ds)
+	if k < len(ds) {
+		ds = ds[:k]
+	}
+	return ds
+}
+
+func dists(ns []Neighbor) []float64 {
+	ds := make([]float64, len(ns))
+	for i, n := range ns {
+		ds[i] = n.Dist
+	}
+	return ds
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	rand.Seed(1)
+	for _, d := range []int{1, 2, 3, 5} {
+		ps := randPoints(200, d)
+		kd, err := New(d, ps)
+		assert.Nil(t, err)
+		assert.Equal(t, 200, kd.Size())
+		for i := 0; i < 50; i++ {
+			q := randPoints(1, d)[0]
+			for _, m := range []Metric{Euclidean, Manhattan} {
+				assert.Equal(t, bruteNearest(ps, q, 5, m), dists(kd.Nearest(q, 5, m)))
+			}
+			r := float64(rand.Intn(6))
+			expected := []float64{}
+			for _, dist := range bruteNearest(ps, q, len(ps), Euclidean) {
+				if dist <= r {
+					expected = append(expected, dist)
+				}
+			}
+			assert.Equal(t, expected, dists(kd.Radius(q, r, Euclidean)))
+		}
+	}
+}
+
+func TestKDTreeRange(t *testing.T) {
+	rand.Seed(2)
+	ps := randPoints(300, 3)
+	kd, err := New(3, ps)
+	assert.Nil(t, err)
+	for i := 0; i < 50; i++ {
+		sp := geom.NewSpan(geom.NewPoint(float64(rand.Intn(20)), float64(rand.Intn(20)), float64(rand.Intn(20))),
+			geom.NewPoint(float64(rand.Intn(20)), float64(rand.Intn(20)), float64(rand.Intn(20))))
+		expected := 0
+		for _, p := range ps {
+			if geom.ToSpanN(sp).Contains(p) {
+				expected++
+			}
+		}
+		assert.Equal(t, expected, len(kd.Range(sp)))
+	}
+}
+
+func TestKDTreeInsertDelete(t *testing.T) {
+	rand.Seed(3)
+	ps := randPoints(100, 2)
+	kd, err := New(2, ps[:50])
+	assert.Nil(t, err)
+	for _, p := range ps[50:] {
+		assert.Nil(t, kd.Insert(p))
+	}
+	assert.Equal(t, 100, kd.Size())
+	for _, p := range ps[:70] {
+		assert.Nil(t, kd.Delete(p))
+	}
+	assert.Equal(t, 30, kd.Size())
+	assert.Equal(t, 30, len(kd.Points()))
+	rest := ps[70:]
+	for i := 0; i < 20; i++ {
+		q := randPoints(1, 2)[0]
+		assert.Equal(t, bruteNearest(rest, q, 3, Euclidean), dists(kd.Nearest(q, 3, Euclidean)))
+	}
+	assert.NotNil(t, kd.Delete(geom.NewPointN(100, 100)))
+	assert.NotNil(t, kd.Insert(geom.NewPointN(1)))
+	_, err = New(3, ps)
+	assert.NotNil(t, err)
+	empty, err := New(2, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, empty.NearestOne(geom.NewPoint(0, 0, 0), Euclidean))
+	assert.Nil(t, empty.Insert(geom.NewPoint(1, 1, 1)))
+	// Queries ignore dimensions beyond the tree's
+	assert.Equal(t, 0.0, empty.Nearest(geom.NewPoint(1, 1, 5), 1, Euclidean)[0].Dist)
+}
@@ -0,0 +1,183 @@
+package kdtree
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Metric measures the distance between two points. For
+// a KDTree to prune its searches correctly, a Metric's
+// distance between two points must be at least as large
+// as their difference on any single axis.
+type Metric func(d1, d2 geom.Dimensional) float64
+
+// Metrics
+var (
+	Euclidean Metric = geom.Distance
+	Manhattan Metric = geom.ManhattanDistance
+)
+
+// A Neighbor is a point found by a nearest neighbor
+// or radius query, alongside its distance from the
+// query point.
+type Neighbor struct {
+	geom.Dimensional
+	Dist float64
+}
+
+// Nearest returns the k points in kd nearest to q under m,
+// sorted from nearest to farthest. If kd holds less than k
+// points, every point is returned.
+func (kd *KDTree) Nearest(q geom.Dimensional, k int, m Metric) []Neighbor {
+	if k <= 0 {
+		return []Neighbor{}
+	}
+	q = kd.trim(q)
+	h := &neighborHeap{}
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil {
+			return
+		}
+		d := m(q, kd.trim(n.p))
+		if h.Len() < k {
+			heap.Push(h, Neighbor{n.p, d})
+		} else if d < (*h)[0].Dist {
+			(*h)[0] = Neighbor{n.p, d}
+			heap.Fix(h, 0)
+		}
+		diff := q.Val(n.axis) - n.p.Val(n.axis)
+		near, far := n.left, n.right
+		if diff >= 0 {
+			near, far = far, near
+		}
+		search(near)
+		if h.Len() < k || math.Abs(diff) < (*h)[0].Dist {
+			search(far)
+		}
+	}
+	search(kd.root)
+	out := make([]Neighbor, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(Neighbor)
+	}
+	return out
+}
+
+// NearestOne returns the point in kd nearest to q under m,
+// or nil if kd is empty.
+func (kd *KDTree) NearestOne(q geom.Dimensional, m Metric) geom.Dimensional {
+	ns := kd.Nearest(q, 1, m)
+	if len(ns) == 0 {
+		return nil
+	}
+	return ns[0].Dimensional
+}
+
+// Radius returns every point in kd within distance r of q
+// under m, sorted from nearest to farthest.
+func (kd *KDTree) Radius(q geom.Dimensional, r float64, m Metric) []Neighbor {
+	q = kd.trim(q)
+	out := []Neighbor{}
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil {
+			return
+		}
+		if d := m(q, kd.trim(n.p)); d <= r {
+			out = append(out, Neighbor{n.p, d})
+		}
+		diff := q.Val(n.axis) - n.p.Val(n.axis)
+		if diff <= r {
+			search(n.left)
+		}
+		if diff >= -r {
+			search(n.right)
+		}
+	}
+	search(kd.root)
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Dist < out[j].Dist
+	})
+	return out
+}
+
+// Range returns every point in kd which lies within sp on
+// each of kd's dimensions, inclusive. sp's Low and High
+// points give its bounds, so geom.Span and geom.SpanN both
+// work as ranges. Dimensions sp does not have are unbounded.
+func (kd *KDTree) Range(sp geom.Spanning) []geom.Dimensional {
+	out := []geom.Dimensional{}
+	d := kd.d
+	if sp.D() < d {
+		d = sp.D()
+	}
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil {
+			return
+		}
+		in := true
+		for i := 0; i < d; i++ {
+			v := n.p.Val(i)
+			if v < sp.Low(i).Val(i) || v > sp.High(i).Val(i) {
+				in = false
+				break
+			}
+		}
+		if in {
+			out = append(out, n.p)
+		}
+		if n.axis >= d {
+			search(n.left)
+			search(n.right)
+			return
+		}
+		v := n.p.Val(n.axis)
+		if sp.Low(n.axis).Val(n.axis) < v {
+			search(n.left)
+		}
+		if sp.High(n.axis).Val(n.axis) >= v {
+			search(n.right)
+		}
+	}
+	search(kd.root)
+	return out
+}
+
+// trim returns p restricted to kd's dimensions, so points
+// and queries holding more dimensions than kd are measured
+// in kd's space.
+func (kd *KDTree) trim(p geom.Dimensional) geom.Dimensional {
+	if p.D() == kd.d {
+		return p
+	}
+	pn := make(geom.PointN, kd.d)
+	for i := range pn {
+		pn[i] = p.Val(i)
+	}
+	return pn
+}
+
+type neighborHeap []Neighbor
+
+func (h neighborHeap) Len() int { return len(h) }
+
+// A max heap, so the farthest neighbor can be replaced
+func (h neighborHeap) Less(i, j int) bool { return h[i].Dist > h[j].Dist }
+func (h neighborHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *neighborHeap) Push(x interface{}) {
+	*h = append(*h, x.(Neighbor))
+}
+
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,124 @@
+// package pst defines a priority search tree, for three
+// sided range queries over two dimensional points.
+
+package pst
+
+import (
+	"sort"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// A Tree is a priority search tree: a heap on y values
+// which is also a search tree on x values. It answers
+// queries of the form x1 <= x <= x2, y >= y0 in
+// O(log n + k) time.
+type Tree struct {
+	root *node
+	size int
+}
+
+type node struct {
+	p geom.Dimensional
+	// split divides the x values of this node's subtrees.
+	// The left subtree holds points with x <= split, the
+	// right subtree points with x >= split.
+	split       float64
+	left, right *node
+}
+
+// New returns a priority search tree over ps. If any of ps
+// have less than two dimensions, an
+// InsufficientDimensionsError is returned.
+func New(ps []geom.Dimensional) (*Tree, error) {
+	for _, p := range ps {
+		if p.D() < 2 {
+			return nil, compgeo.InsufficientDimensionsError{}
+		}
+	}
+	sorted := make([]geom.Dimensional, len(ps))
+	copy(sorted, ps)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Val(0) < sorted[j].Val(0)
+	})
+	return &Tree{build(sorted), len(ps)}, nil
+}
+
+// build takes points sorted by x, takes out the one with the
+// highest y, and splits the rest in half.
+func build(ps []geom.Dimensional) *node {
+	if len(ps) == 0 {
+		return nil
+	}
+	top := 0
+	for i, p := range ps {
+		if p.Val(1) > ps[top].Val(1) {
+			top = i
+		}
+	}
+	n := &node{p: ps[top]}
+	rest := make([]geom.Dimensional, 0, len(ps)-1)
+	rest = append(rest, ps[:top]...)
+	rest = append(rest, ps[top+1:]...)
+	if len(rest) == 0 {
+		return n
+	}
+	mid := len(rest) / 2
+	n.split = rest[mid].Val(0)
+	n.left = build(rest[:mid])
+	n.right = build(rest[mid:])
+	return n
+}
+
+// Size returns the number of points in t.
+func (t *Tree) Size() int {
+	return t.size
+}
+
+// Query returns every point in t with x1 <= x <= x2 and y >= y0.
+func (t *Tree) Query(x1, x2, y0 float64) []geom.Dimensional {
+	out := []geom.Dimensional{}
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil || n.p.Val(1) < y0 {
+			return
+		}
+		if x := n.p.Val(0); x >= x1 && x <= x2 {
+			out = append(out, n.p)
+		}
+		if x1 <= n.split {
+			search(n.left)
+		}
+		if x2 >= n.split {
+			search(n.right)
+		}
+	}
+	search(t.root)
+	return out
+}
+
+// Max returns the point in t with x1 <= x <= x2 which has the
+// highest y value, or nil if there is no such point.
+func (t *Tree) Max(x1, x2 float64) geom.Dimensional {
+	var best geom.Dimensional
+	var search func(*node)
+	search = func(n *node) {
+		if n == nil || (best != nil && n.p.Val(1) <= best.Val(1)) {
+			return
+		}
+		if x := n.p.Val(0); x >= x1 && x <= x2 {
+			// Nothing below n is higher
+			best = n.p
+			return
+		}
+		if x1 <= n.split {
+			search(n.left)
+		}
+		if x2 >= n.split {
+			search(n.right)
+		}
+	}
+	search(t.root)
+	return best
+}
@@ -0,0 +1,50 @@
+package pst
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPST(t *testing.T) {
+	rand.Seed(1)
+	ps := make([]geom.Dimensional, 400)
+	for i := range ps {
+		ps[i] = geom.NewPoint(float64(rand.Intn(50)), float64(rand.Intn(50)), 0)
+	}
+	tr, err := New(ps)
+	assert.Nil(t, err)
+	assert.Equal(t, 400, tr.Size())
+	for i := 0; i < 100; i++ {
+		x1 := float64(rand.Intn(50))
+		x2 := x1 + float64(rand.Intn(20))
+		y0 := float64(rand.Intn(50))
+		expected := 0
+		var max geom.Dimensional
+		for _, p := range ps {
+			if p.Val(0) >= x1 && p.Val(0) <= x2 {
+				if p.Val(1) >= y0 {
+					expected++
+				}
+				if max == nil || p.Val(1) > max.Val(1) {
+					max = p
+				}
+			}
+		}
+		res := tr.Query(x1, x2, y0)
+		assert.Equal(t, expected, len(res))
+		for _, p := range res {
+			assert.True(t, p.Val(0) >= x1 && p.Val(0) <= x2 && p.Val(1) >= y0)
+		}
+		m := tr.Max(x1, x2)
+		if max == nil {
+			assert.Nil(t, m)
+		} else {
+			assert.Equal(t, max.Val(1), m.Val(1))
+		}
+	}
+	_, err = New([]geom.Dimensional{geom.NewPointN(1)})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,268 @@
+// package rangetree defines a static, multi-dimensional range tree
+// for orthogonal range reporting and counting.
+
+package rangetree
+
+import (
+	"sort"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/200sc/go-compgeo/search/tree/static"
+)
+
+// A RangeTree answers orthogonal range queries over a fixed
+// set of points. Each level of the tree is a balanced tree
+// over one dimension, laid out in an array as static.BST is,
+// where each node holds a tree over the next dimension for
+// the points beneath it. The last two dimensions are layered
+// with fractional cascading, so a query on d dimensions takes
+// O(log^(d-1) n + k) time, and a count takes O(log^(d-1) n).
+type RangeTree struct {
+	pts  []geom.Dimensional
+	d    int
+	root *level
+}
+
+// A level is a range tree over dimensions dim onward,
+// holding a subset of the tree's points.
+type level struct {
+	t   *RangeTree
+	dim int
+	// idx holds point indices sorted on dim.
+	idx []int
+	// leaves is the number of leaves in this level's
+	// tree, a power of two.
+	leaves int
+	// If this is the second to last dimension, each node
+	// holds its points sorted on the last dimension, and,
+	// for each prefix of those points, how many of them
+	// belong to its left child.
+	ys     [][]int
+	lCount [][]int
+	// Otherwise, if this is not the last dimension,
+	// each node holds a level on the next dimension.
+	assoc []*level
+}
+
+// New returns a RangeTree over the first d dimensions of ps.
+// If any of ps have less than d dimensions, an
+// InsufficientDimensionsError is returned.
+func New(d int, ps []geom.Dimensional) (*RangeTree, error) {
+	if d < 1 {
+		return nil, compgeo.BadDimensionError{}
+	}
+	for _, p := range ps {
+		if p.D() < d {
+			return nil, compgeo.InsufficientDimensionsError{}
+		}
+	}
+	rt := &RangeTree{d: d}
+	rt.pts = make([]geom.Dimensional, len(ps))
+	copy(rt.pts, ps)
+	idx := make([]int, len(ps))
+	for i := range idx {
+		idx[i] = i
+	}
+	rt.root = rt.newLevel(0, idx)
+	return rt, nil
+}
+
+// D returns the number of dimensions rt queries on.
+func (rt *RangeTree) D() int {
+	return rt.d
+}
+
+// Size returns the number of points in rt.
+func (rt *RangeTree) Size() int {
+	return len(rt.pts)
+}
+
+// less orders point indices on a dimension, breaking ties
+// on index so that every order is total.
+func (rt *RangeTree) less(dim, i, j int) bool {
+	vi, vj := rt.pts[i].Val(dim), rt.pts[j].Val(dim)
+	if vi != vj {
+		return vi < vj
+	}
+	return i < j
+}
+
+func (rt *RangeTree) newLevel(dim int, idx []int) *level {
+	l := &level{t: rt, dim: dim}
+	l.idx = make([]int, len(idx))
+	copy(l.idx, idx)
+	sort.Slice(l.idx, func(i, j int) bool {
+		return rt.less(dim, l.idx[i], l.idx[j])
+	})
+	if dim == rt.d-1 {
+		return l
+	}
+	l.leaves = 1
+	for l.leaves < len(l.idx) {
+		l.leaves *= 2
+	}
+	if dim == rt.d-2 {
+		l.buildLayered()
+	} else {
+		l.assoc = make([]*level, 2*l.leaves)
+		l.buildAssoc(1, 0, l.leaves)
+	}
+	return l
+}
+
+func (l *level) buildAssoc(i, lo, hi int) {
+	if lo >= len(l.idx) {
+		return
+	}
+	end := hi
+	if end > len(l.idx) {
+		end = len(l.idx)
+	}
+	l.assoc[i] = l.t.newLevel(l.dim+1, l.idx[lo:end])
+	if hi-lo > 1 {
+		mid := (lo + hi) / 2
+		l.buildAssoc(static.Left(i), lo, mid)
+		l.buildAssoc(static.Right(i), mid, hi)
+	}
+}
+
+// buildLayered fills ys and lCount bottom up, merging each
+// node's children.
+func (l *level) buildLayered() {
+	next := l.dim + 1
+	l.ys = make([][]int, 2*l.leaves)
+	l.lCount = make([][]int, 2*l.leaves)
+	for p, j := range l.idx {
+		l.ys[l.leaves+p] = []int{j}
+	}
+	for i := l.leaves - 1; i >= 1; i-- {
+		a, b := l.ys[static.Left(i)], l.ys[static.Right(i)]
+		merged := make([]int, 0, len(a)+len(b))
+		counts := make([]int, 1, len(a)+len(b)+1)
+		ai, bi := 0, 0
+		for ai < len(a) || bi < len(b) {
+			if bi == len(b) || (ai < len(a) && l.t.less(next, a[ai], b[bi])) {
+				merged = append(merged, a[ai])
+				ai++
+			} else {
+				merged = append(merged, b[bi])
+				bi++
+			}
+			counts = append(counts, ai)
+		}
+		l.ys[i] = merged
+		l.lCount[i] = counts
+	}
+}
+
+// bounds returns the low and high query values on each of
+// rt's dimensions from sp. Dimensions sp does not have are
+// unbounded.
+func (rt *RangeTree) bounds(sp geom.Spanning) ([]float64, []float64) {
+	lows := make([]float64, rt.d)
+	highs := make([]float64, rt.d)
+	for i := 0; i < rt.d; i++ {
+		if i < sp.D() {
+			lows[i] = sp.Low(i).Val(i)
+			highs[i] = sp.High(i).Val(i)
+		} else {
+			lows[i] = geom.NegInf
+			highs[i] = geom.Inf
+		}
+	}
+	return lows, highs
+}
+
+// Range returns every point in rt which lies within sp on
+// each of rt's dimensions, inclusive. sp's Low and High
+// points give its bounds, so geom.Span and geom.SpanN both
+// work as ranges.
+func (rt *RangeTree) Range(sp geom.Spanning) []geom.Dimensional {
+	lows, highs := rt.bounds(sp)
+	out := []geom.Dimensional{}
+	rt.root.query(lows, highs, func(is []int) {
+		for _, i := range is {
+			out = append(out, rt.pts[i])
+		}
+	})
+	return out
+}
+
+// Count returns the number of points Range would return,
+// without reporting them.
+func (rt *RangeTree) Count(sp geom.Spanning) int {
+	lows, highs := rt.bounds(sp)
+	ct := 0
+	rt.root.query(lows, highs, func(is []int) {
+		ct += len(is)
+	})
+	return ct
+}
+
+// span returns the positions in sorted, a list of point
+// indices ordered on dim, of the first point at least lo
+// and the first point greater than hi.
+func (rt *RangeTree) span(sorted []int, dim int, lo, hi float64) (int, int) {
+	a := sort.Search(len(sorted), func(i int) bool {
+		return rt.pts[sorted[i]].Val(dim) >= lo
+	})
+	b := sort.Search(len(sorted), func(i int) bool {
+		return rt.pts[sorted[i]].Val(dim) > hi
+	})
+	return a, b
+}
+
+// query reports every group of point indices on this
+// level within the query bounds.
+func (l *level) query(lows, highs []float64, report func([]int)) {
+	d := l.dim
+	lo, hi := l.t.span(l.idx, d, lows[d], highs[d])
+	if lo >= hi {
+		return
+	}
+	if l.ys == nil && l.assoc == nil {
+		report(l.idx[lo:hi])
+		return
+	}
+	if l.ys != nil {
+		ylo, yhi := l.t.span(l.ys[1], d+1, lows[d+1], highs[d+1])
+		l.layered(1, 0, l.leaves, lo, hi, ylo, yhi, report)
+		return
+	}
+	l.canonical(1, 0, l.leaves, lo, hi, func(i int) {
+		l.assoc[i].query(lows, highs, report)
+	})
+}
+
+// canonical calls fn on each node in the decomposition of
+// positions [lo, hi) under node i, which covers [nlo, nhi).
+func (l *level) canonical(i, nlo, nhi, lo, hi int, fn func(int)) {
+	if hi <= nlo || nhi <= lo {
+		return
+	}
+	if lo <= nlo && nhi <= hi {
+		fn(i)
+		return
+	}
+	mid := (nlo + nhi) / 2
+	l.canonical(static.Left(i), nlo, mid, lo, hi, fn)
+	l.canonical(static.Right(i), mid, nhi, lo, hi, fn)
+}
+
+// layered acts as canonical, carrying the range [ylo, yhi) in
+// node i's points down to its children through lCount
+// rather than searching for it again at each node.
+func (l *level) layered(i, nlo, nhi, lo, hi, ylo, yhi int, report func([]int)) {
+	if hi <= nlo || nhi <= lo || ylo >= yhi {
+		return
+	}
+	if lo <= nlo && nhi <= hi {
+		report(l.ys[i][ylo:yhi])
+		return
+	}
+	mid := (nlo + nhi) / 2
+	lc := l.lCount[i]
+	l.layered(static.Left(i), nlo, mid, lo, hi, lc[ylo], lc[yhi], report)
+	l.layered(static.Right(i), mid, nhi, lo, hi, ylo-lc[ylo], yhi-lc[yhi], report)
+}
@@ -0,0 +1,69 @@
+package rangetree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+func randPoints(n, d int) []geom.Dimensional {
+	ps := make([]geom.Dimensional, n)
+	for i := range ps {
+		p := make(geom.PointN, d)
+		for j := range p {
+			// Small values, so that ties are common
+			p[j] = float64(rand.Intn(30))
+		}
+		ps[i] = p
+	}
+	return ps
+}
+
+func TestRangeTree(t *testing.T) {
+	rand.Seed(1)
+	for _, d := range []int{1, 2, 3, 4} {
+		for _, n := range []int{0, 1, 7, 300} {
+			ps := randPoints(n, d)
+			rt, err := New(d, ps)
+			assert.Nil(t, err)
+			assert.Equal(t, n, rt.Size())
+			for i := 0; i < 30; i++ {
+				sp := geom.SpanNFrom(randPoints(1, d)[0], randPoints(1, d)[0])
+				expected := map[string]int{}
+				ct := 0
+				for _, p := range ps {
+					if sp.Contains(p) {
+						expected[p.(geom.PointN).String()]++
+						ct++
+					}
+				}
+				for _, p := range rt.Range(sp) {
+					expected[p.(geom.PointN).String()]--
+				}
+				for _, v := range expected {
+					assert.Equal(t, 0, v)
+				}
+				assert.Equal(t, ct, rt.Count(sp))
+			}
+		}
+	}
+}
+
+func TestRangeTreeSpan(t *testing.T) {
+	ps := []geom.Dimensional{
+		geom.NewPoint(1, 1, 0),
+		geom.NewPoint(2, 5, 0),
+		geom.NewPoint(5, 2, 0),
+		geom.NewPoint(3, 3, 0),
+	}
+	rt, err := New(2, ps)
+	assert.Nil(t, err)
+	sp := geom.NewSpan(geom.NewPoint(1, 1, 0), geom.NewPoint(3, 5, 0))
+	assert.Equal(t, 3, rt.Count(sp))
+	_, err = New(4, ps)
+	assert.NotNil(t, err)
+	_, err = New(0, ps)
+	assert.NotNil(t, err)
+}
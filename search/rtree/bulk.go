@@ -0,0 +1,87 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+)
+
+// BulkLoad returns an RTree as New would, filled with items
+// through Sort-Tile-Recursive packing. Bulk loading is much
+// faster than inserting items one at a time, and produces
+// nodes which are nearly full and which overlap very little.
+func BulkLoad(d, minChildren, maxChildren int, items []Spatial) (*RTree, error) {
+	rt, err := New(d, minChildren, maxChildren)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return rt, nil
+	}
+	es := make([]entry, len(items))
+	for i, s := range items {
+		bb, err := rt.bounds(s)
+		if err != nil {
+			return nil, err
+		}
+		es[i] = entry{bb: bb, item: s}
+	}
+	height := 0
+	for {
+		groups := rt.tile(es, 0)
+		nodes := make([]*node, len(groups))
+		for i, g := range groups {
+			n := &node{height: height, entries: g}
+			for _, e := range g {
+				if e.child != nil {
+					e.child.parent = n
+				}
+			}
+			nodes[i] = n
+		}
+		if len(nodes) == 1 {
+			rt.root = nodes[0]
+			break
+		}
+		es = make([]entry, len(nodes))
+		for i, n := range nodes {
+			es[i] = entry{bb: n.bounds(d), child: n}
+		}
+		height++
+	}
+	rt.size = len(items)
+	return rt, nil
+}
+
+// tile sorts es on axis by center, cuts them into slabs, and
+// recursively tiles each slab on the next axis, until the last
+// axis is cut into groups which each fit in a node.
+// Groups are sized evenly so that every group holds at least
+// rt.min entries, unless there is only one group.
+func (rt *RTree) tile(es []entry, axis int) [][]entry {
+	sort.Slice(es, func(i, j int) bool {
+		return es[i].bb.Min[axis]+es[i].bb.Max[axis] <
+			es[j].bb.Min[axis]+es[j].bb.Max[axis]
+	})
+	groupCt := int(math.Ceil(float64(len(es)) / float64(rt.max)))
+	if axis == rt.d-1 || groupCt <= 1 {
+		return split(es, groupCt)
+	}
+	slabCt := int(math.Ceil(math.Pow(float64(groupCt), 1/float64(rt.d-axis))))
+	out := [][]entry{}
+	for _, slab := range split(es, slabCt) {
+		out = append(out, rt.tile(slab, axis+1)...)
+	}
+	return out
+}
+
+// split divides es into ct slices of nearly equal length.
+func split(es []entry, ct int) [][]entry {
+	out := make([][]entry, ct)
+	start := 0
+	for i := 0; i < ct; i++ {
+		end := start + (len(es)-start)/(ct-i)
+		out[i] = es[start:end:end]
+		start = end
+	}
+	return out
+}
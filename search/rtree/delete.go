@@ -0,0 +1,89 @@
+package rtree
+
+import (
+	"errors"
+
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// Delete removes s from rt. Underfull nodes left behind are
+// removed, and their entries reinserted.
+func (rt *RTree) Delete(s Spatial) error {
+	bb, err := rt.bounds(s)
+	if err != nil {
+		return err
+	}
+	leaf, i := rt.find(rt.root, s, bb)
+	if leaf == nil {
+		return errors.New("Spatial not found")
+	}
+	leaf.entries = append(leaf.entries[:i], leaf.entries[i+1:]...)
+	rt.condense(leaf)
+	rt.size--
+	return nil
+}
+
+// find returns the leaf holding s, and s's index in it.
+func (rt *RTree) find(n *node, s Spatial, bb geom.SpanN) (*node, int) {
+	for i, e := range n.entries {
+		if !e.bb.Intersects(bb) {
+			continue
+		}
+		if e.child == nil {
+			if e.item == s {
+				return n, i
+			}
+			continue
+		}
+		if leaf, j := rt.find(e.child, s, bb); leaf != nil {
+			return leaf, j
+		}
+	}
+	return nil, -1
+}
+
+// condense walks up from n, removing underfull nodes and
+// updating boxes, then reinserts the items held under the
+// removed nodes.
+func (rt *RTree) condense(n *node) {
+	orphans := []entry{}
+	for n != rt.root {
+		p := n.parent
+		if len(n.entries) < rt.min {
+			i := n.entryIndex()
+			p.entries = append(p.entries[:i], p.entries[i+1:]...)
+			orphans = n.leafEntries(orphans)
+		} else {
+			rt.adjust(n)
+		}
+		n = p
+	}
+	if len(rt.root.entries) == 0 {
+		rt.root = &node{}
+	}
+	rt.shorten()
+	for _, e := range orphans {
+		rt.insert(e, 0, make(map[int]bool))
+	}
+	rt.shorten()
+}
+
+// shorten removes roots which have only one child.
+func (rt *RTree) shorten() {
+	for rt.root.height > 0 && len(rt.root.entries) == 1 {
+		rt.root = rt.root.entries[0].child
+		rt.root.parent = nil
+	}
+}
+
+// leafEntries appends every item entry under n to es.
+func (n *node) leafEntries(es []entry) []entry {
+	for _, e := range n.entries {
+		if e.child != nil {
+			es = e.child.leafEntries(es)
+		} else {
+			es = append(es, e)
+		}
+	}
+	return es
+}
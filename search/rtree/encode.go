@@ -0,0 +1,140 @@
+package rtree
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// maxHeight bounds the height of a decoded tree. A tree
+// with a fan-out of at least two this tall could not be
+// held in memory. maxDimensions likewise bounds the
+// dimensions of a decoded tree, so that malformed input
+// cannot cause huge allocations.
+const (
+	maxHeight     = 64
+	maxDimensions = 1 << 10
+)
+
+var byteorder = binary.LittleEndian
+
+// Encode writes rt to w in a binary format, which Decode
+// reads back. Items are written as the ids that id returns
+// for them. The format is rt's dimensions, fan-out and size,
+// and then each node in preorder, as its height, its number
+// of entries, and each entry's bounds followed by either an
+// item id or, for inner nodes, the child node.
+func (rt *RTree) Encode(w io.Writer, id func(Spatial) (uint32, error)) error {
+	hdr := [4]uint32{uint32(rt.d), uint32(rt.min), uint32(rt.max), uint32(rt.size)}
+	if err := binary.Write(w, byteorder, hdr); err != nil {
+		return err
+	}
+	return rt.root.encode(w, id)
+}
+
+func (n *node) encode(w io.Writer, id func(Spatial) (uint32, error)) error {
+	if err := binary.Write(w, byteorder, [2]uint32{uint32(n.height), uint32(len(n.entries))}); err != nil {
+		return err
+	}
+	for _, e := range n.entries {
+		if err := binary.Write(w, byteorder, []float64(e.bb.Min)); err != nil {
+			return err
+		}
+		if err := binary.Write(w, byteorder, []float64(e.bb.Max)); err != nil {
+			return err
+		}
+		if n.height == 0 {
+			i, err := id(e.item)
+			if err != nil {
+				return err
+			}
+			if err := binary.Write(w, byteorder, i); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.child.encode(w, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode reads an RTree from r, as written by Encode,
+// using item to find the Spatial for each id.
+func Decode(r io.Reader, item func(uint32) (Spatial, error)) (*RTree, error) {
+	hdr := [4]uint32{}
+	if err := binary.Read(r, byteorder, &hdr); err != nil {
+		return nil, err
+	}
+	if hdr[0] > maxDimensions {
+		return nil, compgeo.BadDimensionError{}
+	}
+	rt, err := New(int(hdr[0]), int(hdr[1]), int(hdr[2]))
+	if err != nil {
+		return nil, err
+	}
+	root, err := rt.decodeNode(r, item, -1)
+	if err != nil {
+		return nil, err
+	}
+	rt.root = root
+	rt.size = len(rt.All())
+	if rt.size != int(hdr[3]) {
+		return nil, errors.New("Invalid encoded size")
+	}
+	return rt, nil
+}
+
+// decodeNode reads a node from r. If height is not -1,
+// the node must have that height, and is not the root.
+func (rt *RTree) decodeNode(r io.Reader, item func(uint32) (Spatial, error), height int) (*node, error) {
+	nh := [2]uint32{}
+	if err := binary.Read(r, byteorder, &nh); err != nil {
+		return nil, err
+	}
+	if (height != -1 && int(nh[0]) != height) || nh[0] > maxHeight {
+		return nil, errors.New("Invalid encoded height")
+	}
+	if int(nh[1]) > rt.max || (height != -1 && nh[1] == 0) ||
+		(nh[0] > 0 && nh[1] == 0) {
+		return nil, errors.New("Invalid encoded fan-out")
+	}
+	n := &node{height: int(nh[0])}
+	// Entries are appended as they are read, so that short
+	// input fails before a large allocation.
+	for i := uint32(0); i < nh[1]; i++ {
+		e := entry{}
+		e.bb = geom.SpanN{Min: make(geom.PointN, rt.d), Max: make(geom.PointN, rt.d)}
+		if err := binary.Read(r, byteorder, []float64(e.bb.Min)); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteorder, []float64(e.bb.Max)); err != nil {
+			return nil, err
+		}
+		if n.height == 0 {
+			var id uint32
+			if err := binary.Read(r, byteorder, &id); err != nil {
+				return nil, err
+			}
+			s, err := item(id)
+			if err != nil {
+				return nil, err
+			}
+			e.item = s
+			n.entries = append(n.entries, e)
+			continue
+		}
+		c, err := rt.decodeNode(r, item, n.height-1)
+		if err != nil {
+			return nil, err
+		}
+		c.parent = n
+		e.child = c
+		n.entries = append(n.entries, e)
+	}
+	return n, nil
+}
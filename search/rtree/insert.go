@@ -0,0 +1,255 @@
+package rtree
+
+import (
+	"math"
+	"sort"
+
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// reinsertPortion is the fraction of an overflowing node's
+// entries which are reinserted, rather than split, the first
+// time a level overflows during an insertion.
+const reinsertPortion = .3
+
+// Insert adds s to rt.
+func (rt *RTree) Insert(s Spatial) error {
+	bb, err := rt.bounds(s)
+	if err != nil {
+		return err
+	}
+	rt.insert(entry{bb: bb, item: s}, 0, make(map[int]bool))
+	rt.size++
+	return nil
+}
+
+// insert places e into a node at the given height,
+// treating overflows as R* does. reinserted tracks which
+// heights have already had a forced reinsertion during
+// this top level insertion.
+func (rt *RTree) insert(e entry, height int, reinserted map[int]bool) {
+	n := rt.choose(e.bb, height)
+	n.entries = append(n.entries, e)
+	if e.child != nil {
+		e.child.parent = n
+	}
+	for n != nil {
+		if len(n.entries) <= rt.max {
+			rt.adjust(n)
+			n = n.parent
+			continue
+		}
+		if n != rt.root && !reinserted[n.height] {
+			reinserted[n.height] = true
+			rt.reinsert(n, reinserted)
+			return
+		}
+		n = rt.split(n)
+	}
+}
+
+// choose returns the node at the given height in which
+// an entry with bounds bb should be placed.
+func (rt *RTree) choose(bb geom.SpanN, height int) *node {
+	n := rt.root
+	for n.height > height {
+		best := 0
+		if n.height == 1 {
+			best = chooseLeast(n.entries, bb, overlapCost)
+		} else {
+			best = chooseLeast(n.entries, bb, enlargementCost)
+		}
+		n = n.entries[best].child
+	}
+	return n
+}
+
+// cost functions return a list of values to be compared
+// lexicographically, lowest first.
+type costFn func(es []entry, i int, bb geom.SpanN) [3]float64
+
+// overlapCost prefers the entry whose overlap with its
+// siblings grows least, then enlargementCost's preferences.
+func overlapCost(es []entry, i int, bb geom.SpanN) [3]float64 {
+	grown := es[i].bb.Union(bb)
+	overlap := 0.0
+	for j, e := range es {
+		if j == i {
+			continue
+		}
+		overlap += overlapVolume(grown, e.bb) - overlapVolume(es[i].bb, e.bb)
+	}
+	c := enlargementCost(es, i, bb)
+	return [3]float64{overlap, c[0], c[1]}
+}
+
+// enlargementCost prefers the entry whose volume grows
+// least, then whose volume is least, then, because degenerate
+// boxes all have zero volume, whose margin grows least.
+func enlargementCost(es []entry, i int, bb geom.SpanN) [3]float64 {
+	grown := es[i].bb.Union(bb)
+	v := es[i].bb.Volume()
+	return [3]float64{grown.Volume() - v, v, grown.Margin() - es[i].bb.Margin()}
+}
+
+func chooseLeast(es []entry, bb geom.SpanN, cost costFn) int {
+	best := 0
+	bestCost := cost(es, 0, bb)
+	for i := 1; i < len(es); i++ {
+		c := cost(es, i, bb)
+		if lessCost(c, bestCost) {
+			best = i
+			bestCost = c
+		}
+	}
+	return best
+}
+
+func lessCost(a, b [3]float64) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func overlapVolume(a, b geom.SpanN) float64 {
+	in, ok := a.Intersection(b)
+	if !ok {
+		return 0
+	}
+	return in.Volume()
+}
+
+// adjust recomputes the box of n's entry in its parent.
+func (rt *RTree) adjust(n *node) {
+	if n.parent == nil {
+		return
+	}
+	n.parent.entries[n.entryIndex()].bb = n.bounds(rt.d)
+}
+
+// reinsert removes the entries of n farthest from its center,
+// and inserts them again from the top of the tree.
+func (rt *RTree) reinsert(n *node, reinserted map[int]bool) {
+	center := n.bounds(rt.d).Center()
+	sort.Slice(n.entries, func(i, j int) bool {
+		return geom.DistanceSquared(n.entries[i].bb.Center(), center) <
+			geom.DistanceSquared(n.entries[j].bb.Center(), center)
+	})
+	p := int(math.Ceil(float64(len(n.entries)) * reinsertPortion))
+	keep := len(n.entries) - p
+	removed := make([]entry, p)
+	copy(removed, n.entries[keep:])
+	n.entries = n.entries[:keep]
+	for m := n; m != nil; m = m.parent {
+		rt.adjust(m)
+	}
+	// Closest entries are reinserted first
+	for _, e := range removed {
+		rt.insert(e, n.height, reinserted)
+	}
+}
+
+// split divides n into two nodes, returning n's parent,
+// which will have gained an entry and may now overflow.
+func (rt *RTree) split(n *node) *node {
+	g1, g2 := rt.splitEntries(n.entries)
+	n.entries = g1
+	nn := &node{height: n.height, entries: g2}
+	for _, e := range g2 {
+		if e.child != nil {
+			e.child.parent = nn
+		}
+	}
+	if n == rt.root {
+		rt.root = &node{
+			height: n.height + 1,
+			entries: []entry{
+				{bb: n.bounds(rt.d), child: n},
+				{bb: nn.bounds(rt.d), child: nn},
+			},
+		}
+		n.parent = rt.root
+		nn.parent = rt.root
+		return nil
+	}
+	nn.parent = n.parent
+	rt.adjust(n)
+	n.parent.entries = append(n.parent.entries, entry{bb: nn.bounds(rt.d), child: nn})
+	return n.parent
+}
+
+// splitEntries performs the R* split: it picks the axis
+// whose distributions have the least total margin, then
+// the distribution on that axis with the least overlap,
+// breaking ties on volume and then on margin.
+func (rt *RTree) splitEntries(es []entry) ([]entry, []entry) {
+	bestAxisMargin := math.Inf(1)
+	var bestSorts [2][]entry
+	for axis := 0; axis < rt.d; axis++ {
+		sorts := [2][]entry{
+			sortedBy(es, func(e entry) (float64, float64) {
+				return e.bb.Min[axis], e.bb.Max[axis]
+			}),
+			sortedBy(es, func(e entry) (float64, float64) {
+				return e.bb.Max[axis], e.bb.Min[axis]
+			}),
+		}
+		margin := 0.0
+		for _, s := range sorts {
+			for k := rt.min; k <= len(s)-rt.min; k++ {
+				b1, b2 := groupBounds(s[:k], rt.d), groupBounds(s[k:], rt.d)
+				margin += b1.Margin() + b2.Margin()
+			}
+		}
+		if margin < bestAxisMargin {
+			bestAxisMargin = margin
+			bestSorts = sorts
+		}
+	}
+	var bestCost [3]float64
+	var best1, best2 []entry
+	for _, s := range bestSorts {
+		for k := rt.min; k <= len(s)-rt.min; k++ {
+			b1, b2 := groupBounds(s[:k], rt.d), groupBounds(s[k:], rt.d)
+			c := [3]float64{
+				overlapVolume(b1, b2),
+				b1.Volume() + b2.Volume(),
+				b1.Margin() + b2.Margin(),
+			}
+			if best1 == nil || lessCost(c, bestCost) {
+				bestCost = c
+				best1, best2 = s[:k], s[k:]
+			}
+		}
+	}
+	g1 := make([]entry, len(best1), rt.max+1)
+	copy(g1, best1)
+	g2 := make([]entry, len(best2), rt.max+1)
+	copy(g2, best2)
+	return g1, g2
+}
+
+func sortedBy(es []entry, key func(entry) (float64, float64)) []entry {
+	s := make([]entry, len(es))
+	copy(s, es)
+	sort.SliceStable(s, func(i, j int) bool {
+		a1, a2 := key(s[i])
+		b1, b2 := key(s[j])
+		if a1 != b1 {
+			return a1 < b1
+		}
+		return a2 < b2
+	})
+	return s
+}
+
+func groupBounds(es []entry, d int) geom.SpanN {
+	sp := geom.NewSpanN(d)
+	for _, e := range es {
+		sp = sp.Union(e.bb)
+	}
+	return sp
+}
@@ -0,0 +1,227 @@
+// package rtree defines an R*-tree over axis aligned boxes
+// of any dimension.
+
+package rtree
+
+import (
+	"container/heap"
+	"errors"
+
+	compgeo "github.com/200sc/go-compgeo"
+	"github.com/200sc/go-compgeo/geom"
+)
+
+// Spatial types can be stored in an RTree. Spatials are
+// identified by equality on deletion, so they should be
+// comparable, e.g. pointers.
+type Spatial interface {
+	Bounds() geom.SpanN
+}
+
+// Default fan-out values.
+const (
+	DefaultMinChildren = 4
+	DefaultMaxChildren = 10
+)
+
+// An RTree is an R*-tree: a balanced tree of nested boxes
+// which uses the R* heuristics of minimizing overlap,
+// margin and area, and of reinserting entries on overflow,
+// to keep its boxes tight.
+// Boxes with zero extent in some dimensions, such as points
+// or axis aligned segments, are fully supported.
+type RTree struct {
+	root *node
+	d    int
+	min  int
+	max  int
+	size int
+}
+
+type node struct {
+	parent *node
+	// height is 0 for leaves
+	height  int
+	entries []entry
+}
+
+type entry struct {
+	bb    geom.SpanN
+	child *node
+	item  Spatial
+}
+
+// New returns an empty RTree over d dimensions, whose
+// nodes will hold between minChildren and maxChildren
+// entries. minChildren must be at least 1 and at most
+// half of maxChildren.
+func New(d, minChildren, maxChildren int) (*RTree, error) {
+	if d < 1 {
+		return nil, compgeo.BadDimensionError{}
+	}
+	if minChildren < 1 || maxChildren < 2*minChildren {
+		return nil, errors.New("Invalid fan-out")
+	}
+	return &RTree{
+		root: &node{},
+		d:    d,
+		min:  minChildren,
+		max:  maxChildren,
+	}, nil
+}
+
+// D returns the number of dimensions rt indexes.
+func (rt *RTree) D() int {
+	return rt.d
+}
+
+// Size returns the number of Spatials in rt.
+func (rt *RTree) Size() int {
+	return rt.size
+}
+
+// Height returns the number of levels in rt.
+func (rt *RTree) Height() int {
+	return rt.root.height + 1
+}
+
+// bounds returns s's bounds restricted to rt's dimensions,
+// or an error if s has too few dimensions.
+func (rt *RTree) bounds(s Spatial) (geom.SpanN, error) {
+	bb := s.Bounds()
+	if bb.D() < rt.d {
+		return bb, compgeo.InsufficientDimensionsError{}
+	}
+	if bb.D() > rt.d {
+		bb = geom.SpanN{Min: bb.Min[:rt.d], Max: bb.Max[:rt.d]}
+	}
+	return bb, nil
+}
+
+// query converts p into a box of zero extent in rt's dimensions.
+func (rt *RTree) query(p geom.Dimensional) (geom.SpanN, error) {
+	if p.D() < rt.d {
+		return geom.SpanN{}, compgeo.InsufficientDimensionsError{}
+	}
+	pn := make(geom.PointN, rt.d)
+	for i := range pn {
+		pn[i] = p.Val(i)
+	}
+	return geom.SpanN{Min: pn, Max: pn}, nil
+}
+
+// SearchIntersect returns every Spatial in rt whose bounds
+// intersect sp, including those which only touch it.
+func (rt *RTree) SearchIntersect(sp geom.SpanN) ([]Spatial, error) {
+	if sp.D() < rt.d {
+		return nil, compgeo.InsufficientDimensionsError{}
+	}
+	sp = geom.SpanN{Min: sp.Min[:rt.d], Max: sp.Max[:rt.d]}
+	out := []Spatial{}
+	var search func(*node)
+	search = func(n *node) {
+		for _, e := range n.entries {
+			if !e.bb.Intersects(sp) {
+				continue
+			}
+			if e.child != nil {
+				search(e.child)
+			} else {
+				out = append(out, e.item)
+			}
+		}
+	}
+	search(rt.root)
+	return out, nil
+}
+
+// SearchPoint returns every Spatial in rt whose bounds
+// contain p.
+func (rt *RTree) SearchPoint(p geom.Dimensional) ([]Spatial, error) {
+	q, err := rt.query(p)
+	if err != nil {
+		return nil, err
+	}
+	return rt.SearchIntersect(q)
+}
+
+// Nearest returns up to k Spatials in rt whose bounds are
+// nearest to p, sorted from nearest to farthest.
+func (rt *RTree) Nearest(p geom.Dimensional, k int) ([]Spatial, error) {
+	q, err := rt.query(p)
+	if err != nil {
+		return nil, err
+	}
+	out := []Spatial{}
+	h := &entryHeap{}
+	for _, e := range rt.root.entries {
+		heap.Push(h, distEntry{e, e.bb.DistanceSquared(q.Min)})
+	}
+	for h.Len() > 0 && len(out) < k {
+		de := heap.Pop(h).(distEntry)
+		if de.child == nil {
+			out = append(out, de.item)
+			continue
+		}
+		for _, e := range de.child.entries {
+			heap.Push(h, distEntry{e, e.bb.DistanceSquared(q.Min)})
+		}
+	}
+	return out, nil
+}
+
+// All returns every Spatial in rt.
+func (rt *RTree) All() []Spatial {
+	out := make([]Spatial, 0, rt.size)
+	var walk func(*node)
+	walk = func(n *node) {
+		for _, e := range n.entries {
+			if e.child != nil {
+				walk(e.child)
+			} else {
+				out = append(out, e.item)
+			}
+		}
+	}
+	walk(rt.root)
+	return out
+}
+
+// bounds returns the box covering all of n's entries.
+func (n *node) bounds(d int) geom.SpanN {
+	return groupBounds(n.entries, d)
+}
+
+// entryIndex returns the index in n's parent of
+// the entry pointing to n.
+func (n *node) entryIndex() int {
+	for i, e := range n.parent.entries {
+		if e.child == n {
+			return i
+		}
+	}
+	return -1
+}
+
+type distEntry struct {
+	entry
+	dist float64
+}
+
+type entryHeap []distEntry
+
+func (h entryHeap) Len() int           { return len(h) }
+func (h entryHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h entryHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *entryHeap) Push(x interface{}) {
+	*h = append(*h, x.(distEntry))
+}
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
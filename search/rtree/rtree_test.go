@@ -0,0 +1,177 @@
+package rtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/200sc/go-compgeo/geom"
+	"github.com/stretchr/testify/assert"
+)
+
+type box struct {
+	geom.SpanN
+}
+
+func (b *box) Bounds() geom.SpanN {
+	return b.SpanN
+}
+
+// randBoxes returns boxes on a small integer grid, a third
+// of which are points and a third of which are segments.
+func randBoxes(n, d int) []Spatial {
+	out := make([]Spatial, n)
+	for i := range out {
+		p1 := make(geom.PointN, d)
+		p2 := make(geom.PointN, d)
+		for j := range p1 {
+			p1[j] = float64(rand.Intn(100))
+			switch {
+			case i%3 == 0:
+				p2[j] = p1[j]
+			case i%3 == 1 && j > 0:
+				p2[j] = p1[j]
+			default:
+				p2[j] = p1[j] + float64(rand.Intn(10))
+			}
+		}
+		out[i] = &box{geom.SpanNFrom(p1, p2)}
+	}
+	return out
+}
+
+func bruteIntersect(items []Spatial, sp geom.SpanN) []Spatial {
+	out := []Spatial{}
+	for _, s := range items {
+		if s.Bounds().Intersects(sp) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func sameSet(t *testing.T, expected, actual []Spatial) {
+	m := make(map[Spatial]int)
+	for _, s := range expected {
+		m[s]++
+	}
+	for _, s := range actual {
+		m[s]--
+	}
+	for _, v := range m {
+		assert.Equal(t, 0, v)
+	}
+	assert.Equal(t, len(expected), len(actual))
+}
+
+// checkTree verifies every node's box, fill, height and parent.
+func checkTree(t *testing.T, rt *RTree) {
+	var check func(*node)
+	check = func(n *node) {
+		if n != rt.root {
+			assert.True(t, len(n.entries) >= rt.min)
+		}
+		assert.True(t, len(n.entries) <= rt.max)
+		for _, e := range n.entries {
+			if e.child == nil {
+				assert.Equal(t, 0, n.height)
+				continue
+			}
+			assert.Equal(t, n, e.child.parent)
+			assert.Equal(t, n.height-1, e.child.height)
+			assert.True(t, e.bb.Eq(e.child.bounds(rt.d)))
+			check(e.child)
+		}
+	}
+	check(rt.root)
+	assert.Equal(t, rt.size, len(rt.All()))
+}
+
+func TestRTreeInsertSearch(t *testing.T) {
+	rand.Seed(1)
+	for _, d := range []int{1, 2, 3} {
+		items := randBoxes(500, d)
+		rt, err := New(d, 2, 6)
+		assert.Nil(t, err)
+		for _, s := range items {
+			assert.Nil(t, rt.Insert(s))
+		}
+		checkTree(t, rt)
+		bulk, err := BulkLoad(d, 2, 6, items)
+		assert.Nil(t, err)
+		checkTree(t, bulk)
+		for i := 0; i < 50; i++ {
+			q := randBoxes(3, d)[i%3].Bounds()
+			expected := bruteIntersect(items, q)
+			res, err := rt.SearchIntersect(q)
+			assert.Nil(t, err)
+			sameSet(t, expected, res)
+			res, err = bulk.SearchIntersect(q)
+			assert.Nil(t, err)
+			sameSet(t, expected, res)
+		}
+	}
+}
+
+func TestRTreeDelete(t *testing.T) {
+	rand.Seed(2)
+	items := randBoxes(400, 2)
+	rt, err := BulkLoad(2, 3, 8, items)
+	assert.Nil(t, err)
+	rand.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	for i, s := range items[:300] {
+		assert.Nil(t, rt.Delete(s))
+		if i%50 == 0 {
+			checkTree(t, rt)
+		}
+	}
+	checkTree(t, rt)
+	assert.NotNil(t, rt.Delete(items[0]))
+	rest := items[300:]
+	q := geom.SpanNFrom(geom.NewPointN(20, 20), geom.NewPointN(60, 70))
+	res, err := rt.SearchIntersect(q)
+	assert.Nil(t, err)
+	sameSet(t, bruteIntersect(rest, q), res)
+	for _, s := range rest {
+		assert.Nil(t, rt.Delete(s))
+	}
+	assert.Equal(t, 0, rt.Size())
+	assert.Equal(t, 1, rt.Height())
+}
+
+func TestRTreeNearest(t *testing.T) {
+	rand.Seed(3)
+	items := randBoxes(300, 2)
+	rt, err := BulkLoad(2, DefaultMinChildren, DefaultMaxChildren, items)
+	assert.Nil(t, err)
+	for i := 0; i < 30; i++ {
+		p := geom.NewPointN(float64(rand.Intn(100)), float64(rand.Intn(100)))
+		res, err := rt.Nearest(p, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(res))
+		ds := make([]float64, len(items))
+		for j, s := range items {
+			ds[j] = s.Bounds().DistanceSquared(p)
+		}
+		sort.Float64s(ds)
+		for j, s := range res {
+			assert.Equal(t, ds[j], s.Bounds().DistanceSquared(p))
+		}
+	}
+}
+
+func TestRTreeErrors(t *testing.T) {
+	_, err := New(0, 2, 4)
+	assert.NotNil(t, err)
+	_, err = New(2, 3, 4)
+	assert.NotNil(t, err)
+	rt, _ := New(3, 2, 4)
+	assert.NotNil(t, rt.Insert(&box{geom.SpanNFrom(geom.NewPointN(1), geom.NewPointN(2))}))
+	_, err = rt.SearchPoint(geom.NewPointN(1, 2))
+	assert.NotNil(t, err)
+	res, err := rt.SearchPoint(geom.NewPoint(1, 2, 3))
+	assert.Nil(t, err)
+	assert.Empty(t, res)
+}
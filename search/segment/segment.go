@@ -0,0 +1,129 @@
+// package segment defines a static segment tree, for finding
+// which of a fixed set of one dimensional intervals contain
+// a value.
+
+package segment
+
+import (
+	"sort"
+
+	"github.com/200sc/go-compgeo/search/interval"
+	"github.com/200sc/go-compgeo/search/tree/static"
+)
+
+// A Tree is a segment tree. Its leaves are the elementary
+// intervals formed by its input intervals' endpoints: each
+// endpoint itself, and the open gaps between consecutive
+// endpoints. Each input interval is stored on the O(log n)
+// nodes which exactly cover its leaves, so a stabbing query
+// takes O(log n + k) time. Nodes are laid out in an array
+// as static.BST is.
+type Tree struct {
+	points []float64
+	leaves int
+	nodes  [][]interval.Interval
+	size   int
+}
+
+// New returns a segment Tree over ivs.
+func New(ivs []interval.Interval) *Tree {
+	t := &Tree{size: len(ivs)}
+	pts := make([]float64, 0, 2*len(ivs))
+	for _, iv := range ivs {
+		lo, hi := iv.Extent()
+		pts = append(pts, lo, hi)
+	}
+	sort.Float64s(pts)
+	for i, p := range pts {
+		if i == 0 || p != pts[i-1] {
+			t.points = append(t.points, p)
+		}
+	}
+	// Leaf 2k is points[k], leaf 2k+1 is the gap after it.
+	elementary := 2*len(t.points) - 1
+	t.leaves = 1
+	for t.leaves < elementary {
+		t.leaves *= 2
+	}
+	t.nodes = make([][]interval.Interval, 2*t.leaves)
+	for _, iv := range ivs {
+		lo, hi := iv.Extent()
+		if hi < lo {
+			continue
+		}
+		a := 2 * sort.SearchFloat64s(t.points, lo)
+		b := 2 * sort.SearchFloat64s(t.points, hi)
+		t.insert(1, 0, t.leaves, a, b+1, iv)
+	}
+	return t
+}
+
+// insert stores iv on the nodes under i, which covers leaves
+// [nlo, nhi), exactly covering leaves [lo, hi).
+func (t *Tree) insert(i, nlo, nhi, lo, hi int, iv interval.Interval) {
+	if hi <= nlo || nhi <= lo {
+		return
+	}
+	if lo <= nlo && nhi <= hi {
+		t.nodes[i] = append(t.nodes[i], iv)
+		return
+	}
+	mid := (nlo + nhi) / 2
+	t.insert(static.Left(i), nlo, mid, lo, hi, iv)
+	t.insert(static.Right(i), mid, nhi, lo, hi, iv)
+}
+
+// Size returns the number of intervals in t.
+func (t *Tree) Size() int {
+	return t.size
+}
+
+// Stab returns every interval in t containing x.
+func (t *Tree) Stab(x float64) []interval.Interval {
+	out := []interval.Interval{}
+	t.walk(x, func(ivs []interval.Interval) {
+		out = append(out, ivs...)
+	})
+	return out
+}
+
+// Count returns the number of intervals in t containing x,
+// in O(log n) time.
+func (t *Tree) Count(x float64) int {
+	ct := 0
+	t.walk(x, func(ivs []interval.Interval) {
+		ct += len(ivs)
+	})
+	return ct
+}
+
+// walk calls fn on the intervals stored at each node from
+// the root to the leaf holding x.
+func (t *Tree) walk(x float64, fn func([]interval.Interval)) {
+	if len(t.points) == 0 {
+		return
+	}
+	k := sort.SearchFloat64s(t.points, x)
+	var leaf int
+	switch {
+	case k < len(t.points) && t.points[k] == x:
+		leaf = 2 * k
+	case k == 0 || k == len(t.points):
+		return
+	default:
+		leaf = 2*k - 1
+	}
+	i, nlo, nhi := 1, 0, t.leaves
+	for {
+		fn(t.nodes[i])
+		if nhi-nlo == 1 {
+			return
+		}
+		mid := (nlo + nhi) / 2
+		if leaf < mid {
+			i, nhi = static.Left(i), mid
+		} else {
+			i, nlo = static.Right(i), mid
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package segment
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search/interval"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentTree(t *testing.T) {
+	rand.Seed(1)
+	ivs := make([]interval.Interval, 300)
+	for i := range ivs {
+		lo := float64(rand.Intn(100))
+		ivs[i] = interval.NewSpan(lo, lo+float64(rand.Intn(20)))
+	}
+	tr := New(ivs)
+	assert.Equal(t, 300, tr.Size())
+	for i := 0; i < 200; i++ {
+		x := float64(rand.Intn(260)-20) / 2
+		expected := 0
+		for _, iv := range ivs {
+			lo, hi := iv.Extent()
+			if lo <= x && x <= hi {
+				expected++
+			}
+		}
+		assert.Equal(t, expected, len(tr.Stab(x)))
+		assert.Equal(t, expected, tr.Count(x))
+	}
+	empty := New(nil)
+	assert.Empty(t, empty.Stab(1))
+	single := New([]interval.Interval{interval.NewSpan(1, 1)})
+	assert.Equal(t, 1, single.Count(1))
+	assert.Equal(t, 0, single.Count(1.5))
+}
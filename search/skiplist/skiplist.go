@@ -0,0 +1,336 @@
+// package skiplist defines a probabilistic skip list which
+// satisfies search.Dynamic.
+
+package skiplist
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/200sc/go-compgeo/printutil"
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree/fullCopy"
+	"github.com/200sc/go-compgeo/search/tree/static"
+)
+
+const (
+	// MaxLevel is the most forward pointers any node
+	// in a SkipList will have.
+	MaxLevel = 32
+	// P is the chance that a node at some level
+	// will also appear at the next level.
+	P = 0.25
+)
+
+// A SkipList is a sorted linked list with additional,
+// randomly chosen forward links which skip over increasingly
+// many nodes. Searches, insertions and deletions take expected
+// O(log n) time, without any rebalancing.
+//
+// As with tree.BST, all values inserted with the same key are
+// stored on one node, and searches return the first of them.
+type SkipList struct {
+	head  *node
+	tail  *node
+	level int
+	size  int
+	rng   *rand.Rand
+	// seed is the seed of rng, from which copies seed their
+	// own sources without drawing from rng. copies counts them.
+	seed   int64
+	copies int64
+	// update is scratch space for Insert and Delete.
+	update []*node
+}
+
+type node struct {
+	key  search.Comparable
+	val  []search.Equalable
+	prev *node
+	next []*node
+}
+
+// Key returns n's key.
+func (n *node) Key() search.Comparable {
+	return n.key
+}
+
+// Val returns the first value stored at n.
+func (n *node) Val() search.Equalable {
+	return n.val[0]
+}
+
+// New returns an empty SkipList. Its levels are drawn
+// from a source seeded by math/rand.
+func New() *SkipList {
+	return NewSeeded(rand.Int63())
+}
+
+// NewSeeded returns an empty SkipList whose levels are
+// drawn from a source with the given seed.
+func NewSeeded(seed int64) *SkipList {
+	return &SkipList{
+		head:   &node{next: make([]*node, MaxLevel)},
+		level:  1,
+		rng:    rand.New(rand.NewSource(seed)),
+		seed:   seed,
+		update: make([]*node, MaxLevel),
+	}
+}
+
+func (sl *SkipList) randomLevel() int {
+	lvl := 1
+	for lvl < MaxLevel && sl.rng.Float64() < P {
+		lvl++
+	}
+	return lvl
+}
+
+// Size returns the number of values in sl.
+func (sl *SkipList) Size() int {
+	return sl.size
+}
+
+// find returns, for each level, the last node whose key is
+// less than key, and the node following that on the bottom
+// level.
+func (sl *SkipList) find(key interface{}, update []*node) *node {
+	n := sl.head
+	for i := sl.level - 1; i >= 0; i-- {
+		for n.next[i] != nil && less(n.next[i].key, key) {
+			n = n.next[i]
+		}
+		if update != nil {
+			update[i] = n
+		}
+	}
+	return n.next[0]
+}
+
+func less(k search.Comparable, key interface{}) bool {
+	r := k.Compare(key)
+	if r == search.Invalid {
+		panic("Invalid types for SkipList operations")
+	}
+	return r == search.Less
+}
+
+func equal(n *node, key interface{}) bool {
+	return n != nil && n.key.Compare(key) == search.Equal
+}
+
+// Insert adds inNode's key and value to sl.
+func (sl *SkipList) Insert(inNode search.Node) error {
+	update := sl.update
+	k := inNode.Key()
+	n := sl.find(k, update)
+	if equal(n, k) {
+		// All values of the same key are stored at the same node
+		n.val = append(n.val, inNode.Val())
+		sl.size++
+		return nil
+	}
+	lvl := sl.randomLevel()
+	if lvl > sl.level {
+		for i := sl.level; i < lvl; i++ {
+			update[i] = sl.head
+		}
+		sl.level = lvl
+	}
+	n = &node{
+		key:  k,
+		val:  []search.Equalable{inNode.Val()},
+		next: make([]*node, lvl),
+	}
+	sl.link(n, update)
+	sl.size++
+	return nil
+}
+
+// link places n after update on each of n's levels.
+func (sl *SkipList) link(n *node, update []*node) {
+	for i := range n.next {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	if update[0] != sl.head {
+		n.prev = update[0]
+	}
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		sl.tail = n
+	}
+}
+
+// Delete removes a value from sl at n's key. As with tree.BST,
+// if multiple values are stored at that key, the first value
+// which Equals n's value is removed, and if there is only one
+// value it is removed outright.
+func (sl *SkipList) Delete(n search.Node) error {
+	update := sl.update
+	k := n.Key()
+	v := n.Val()
+	cur := sl.find(k, update)
+	if !equal(cur, k) {
+		return errors.New("Key not found")
+	}
+	if len(cur.val) != 1 {
+		for vi := 0; vi < len(cur.val); vi++ {
+			if v.Equals(cur.val[vi]) {
+				cur.val = append(cur.val[:vi], cur.val[vi+1:]...)
+				sl.size--
+				return nil
+			}
+		}
+		return errors.New("Value not found")
+	}
+	for i := range cur.next {
+		update[i].next[i] = cur.next[i]
+	}
+	if cur.next[0] != nil {
+		cur.next[0].prev = cur.prev
+	} else {
+		sl.tail = cur.prev
+	}
+	for sl.level > 1 && sl.head.next[sl.level-1] == nil {
+		sl.level--
+	}
+	sl.size--
+	return nil
+}
+
+// Search returns whether key exists in sl, and if it does
+// the first value stored at it.
+func (sl *SkipList) Search(key interface{}) (bool, interface{}) {
+	n := sl.find(key, nil)
+	if !equal(n, key) {
+		return false, nil
+	}
+	return true, n.val[0]
+}
+
+// SearchUp performs a search, and rounds up to the nearest
+// existing key if no node of the query key exists, then
+// steps up to that many successors further. If there is
+// no key at least the query key, the greatest key is used.
+func (sl *SkipList) SearchUp(key interface{}, up int) (search.Comparable, interface{}) {
+	if sl.size == 0 {
+		return nil, nil
+	}
+	n := sl.find(key, nil)
+	if n == nil {
+		n = sl.tail
+	}
+	for i := 0; i < up && n.next[0] != nil; i++ {
+		n = n.next[0]
+	}
+	return n.key, n.val[0]
+}
+
+// SearchDown acts as SearchUp, but rounds down.
+func (sl *SkipList) SearchDown(key interface{}, down int) (search.Comparable, interface{}) {
+	if sl.size == 0 {
+		return nil, nil
+	}
+	n := sl.find(key, nil)
+	if !equal(n, key) {
+		if n == nil {
+			n = sl.tail
+		} else if n.prev != nil {
+			n = n.prev
+		}
+	}
+	for i := 0; i < down && n.prev != nil; i++ {
+		n = n.prev
+	}
+	return n.key, n.val[0]
+}
+
+// InOrderTraverse returns the nodes of sl in ascending order.
+func (sl *SkipList) InOrderTraverse() []search.Node {
+	out := make([]search.Node, 0, sl.size)
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		out = append(out, n)
+	}
+	return out
+}
+
+// ToStatic returns a balanced static.BST holding the first
+// value at each of sl's keys.
+func (sl *SkipList) ToStatic() search.Static {
+	ns := sl.InOrderTraverse()
+	max := 0
+	m := make(map[int]*static.Node, len(ns))
+	var build func(i, lo, hi int)
+	build = func(i, lo, hi int) {
+		if lo >= hi {
+			return
+		}
+		mid := (lo + hi) / 2
+		m[i] = static.NewNode(ns[mid].Key(), ns[mid].Val())
+		if i > max {
+			max = i
+		}
+		build(static.Left(i), lo, mid)
+		build(static.Right(i), mid+1, hi)
+	}
+	build(1, 0, len(ns))
+	staticBst := make(static.BST, max+1)
+	for k, v := range m {
+		staticBst[k] = v
+	}
+	return &staticBst
+}
+
+// ToPersistent converts sl into a fully copying persistent
+// structure, as tree.BST does.
+func (sl *SkipList) ToPersistent() search.DynamicPersistent {
+	return fullCopy.NewFullPersistentBST(sl)
+}
+
+// Copy returns a deep copy of sl, with the same levels on
+// each node. The copy draws its levels from its own source,
+// seeded from sl's seed, so copying does not change the
+// levels sl goes on to choose.
+func (sl *SkipList) Copy() interface{} {
+	sl.copies++
+	seed := sl.seed + sl.copies
+	cp := &SkipList{
+		head:   &node{next: make([]*node, MaxLevel)},
+		level:  sl.level,
+		size:   sl.size,
+		rng:    rand.New(rand.NewSource(seed)),
+		seed:   seed,
+		update: make([]*node, MaxLevel),
+	}
+	update := make([]*node, MaxLevel)
+	for i := range update {
+		update[i] = cp.head
+	}
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		n2 := &node{
+			key:  n.key,
+			val:  make([]search.Equalable, len(n.val)),
+			next: make([]*node, len(n.next)),
+		}
+		copy(n2.val, n.val)
+		cp.link(n2, update)
+		for i := range n2.next {
+			update[i] = n2
+		}
+	}
+	return cp
+}
+
+func (sl *SkipList) String() string {
+	if sl.size == 0 {
+		return "<Empty SkipList>\n"
+	}
+	s := ""
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		s += fmt.Sprintf("%d:%s%v\n", len(n.next), printutil.String(n.key), n.val)
+	}
+	return s
+}
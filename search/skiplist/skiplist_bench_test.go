@@ -0,0 +1,80 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree"
+)
+
+var j int
+
+func BenchmarkSkipListInsert(b *testing.B) {
+	benchmarkInsert(b, func() search.Dynamic { return New() })
+}
+func BenchmarkRBInsert(b *testing.B) {
+	benchmarkInsert(b, func() search.Dynamic { return tree.New(tree.RedBlack) })
+}
+func BenchmarkSkipListChurn(b *testing.B) {
+	benchmarkChurn(b, New())
+}
+func BenchmarkRBChurn(b *testing.B) {
+	benchmarkChurn(b, tree.New(tree.RedBlack))
+}
+func BenchmarkSkipListSearch(b *testing.B) {
+	benchmarkSearch(b, New())
+}
+func BenchmarkRBSearch(b *testing.B) {
+	benchmarkSearch(b, tree.New(tree.RedBlack))
+}
+
+func randomInput() []testNode {
+	randomInput := make([]testNode, randomInputCt)
+	for i := range randomInput {
+		randomInput[i] = testNode{
+			compFloat(rand.Intn(randomInputRange)),
+			compFloat(rand.Intn(randomInputRange)),
+		}
+	}
+	return randomInput
+}
+
+// benchmarkInsert fills a new structure from scratch each iteration.
+func benchmarkInsert(b *testing.B, newFn func() search.Dynamic) {
+	input := randomInput()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d := newFn()
+		for _, v := range input {
+			d.Insert(v)
+		}
+	}
+}
+
+// benchmarkChurn inserts and deletes one key each iteration,
+// as a sweep's status structure does.
+func benchmarkChurn(b *testing.B, d search.Dynamic) {
+	input := randomInput()
+	for _, v := range input {
+		d.Insert(v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v := input[i%len(input)]
+		d.Delete(v)
+		d.Insert(v)
+	}
+}
+
+func benchmarkSearch(b *testing.B, d search.Dynamic) {
+	for _, v := range randomInput() {
+		d.Insert(v)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, _ := d.Search(float64(rand.Intn(randomInputRange))); ok {
+			j++
+		}
+	}
+}
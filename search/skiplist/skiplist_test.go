@@ -0,0 +1,168 @@
+package skiplist
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree"
+	"github.com/stretchr/testify/assert"
+)
+
+type compFloat float64
+
+func (f compFloat) Compare(i interface{}) search.CompareResult {
+	var f3 compFloat
+	switch f2 := i.(type) {
+	case float64:
+		f3 = compFloat(f2)
+	case compFloat:
+		f3 = f2
+	default:
+		return search.Invalid
+	}
+	if f == f3 {
+		return search.Equal
+	} else if f < f3 {
+		return search.Less
+	}
+	return search.Greater
+}
+
+func (f compFloat) Equals(e search.Equalable) bool {
+	switch f2 := e.(type) {
+	case compFloat:
+		return f == f2
+	}
+	return false
+}
+
+type testNode struct {
+	key compFloat
+	val compFloat
+}
+
+func (t testNode) Key() search.Comparable {
+	return t.key
+}
+
+func (t testNode) Val() search.Equalable {
+	return t.val
+}
+
+const (
+	randomInputCt    = 5000
+	randomInputRange = 2000
+)
+
+var _ search.Persistable = New()
+
+func TestSkipListDefinedInput(t *testing.T) {
+	sl := NewSeeded(1)
+	for i := 1; i <= 10; i++ {
+		sl.Insert(testNode{compFloat(i), compFloat(11 - i)})
+	}
+	assert.Equal(t, 10, sl.Size())
+	inOrder := sl.InOrderTraverse()
+	for i := range inOrder {
+		assert.Equal(t, compFloat(i+1), inOrder[i].Key())
+	}
+	_, v := sl.SearchUp(9.5, 0)
+	assert.Equal(t, compFloat(1), v)
+	_, v = sl.SearchDown(9.5, 0)
+	assert.Equal(t, compFloat(2), v)
+	_, v = sl.SearchUp(3.0, 2)
+	assert.Equal(t, compFloat(6), v)
+	_, v = sl.SearchDown(3.0, 5)
+	assert.Equal(t, compFloat(10), v)
+
+	// Duplicate keys share a node
+	sl.Insert(testNode{5, 100})
+	assert.Equal(t, 11, sl.Size())
+	assert.Equal(t, 10, len(sl.InOrderTraverse()))
+	assert.NotNil(t, sl.Delete(testNode{5, 99}))
+	assert.Nil(t, sl.Delete(testNode{5, 100}))
+	_, v = sl.Search(5.0)
+	assert.Equal(t, compFloat(6), v)
+
+	for i := 1; i <= 10; i++ {
+		assert.Nil(t, sl.Delete(testNode{compFloat(i), 0}))
+		b, _ := sl.Search(float64(i))
+		assert.False(t, b)
+	}
+	assert.NotNil(t, sl.Delete(testNode{1, 0}))
+	k, v := sl.SearchUp(1.0, 0)
+	assert.Nil(t, k)
+	assert.Nil(t, v)
+}
+
+func TestSkipListMatchesRB(t *testing.T) {
+	rand.Seed(2)
+	sl := New()
+	rb := tree.New(tree.RedBlack)
+	for i := 0; i < randomInputCt; i++ {
+		n := testNode{
+			compFloat(rand.Intn(randomInputRange)),
+			compFloat(rand.Intn(randomInputRange)),
+		}
+		sl.Insert(n)
+		rb.Insert(n)
+	}
+	cp := sl.Copy().(*SkipList)
+	for i := 0; i < randomInputCt; i++ {
+		n := testNode{compFloat(rand.Intn(randomInputRange)), 0}
+		assert.Equal(t, rb.Delete(n) == nil, sl.Delete(n) == nil)
+	}
+	assert.Equal(t, rb.Size(), sl.Size())
+	assert.Equal(t, randomInputCt, cp.Size())
+	for i := 0; i < randomInputCt; i++ {
+		q := rand.Float64() * randomInputRange
+		b1, v1 := rb.Search(q)
+		b2, v2 := sl.Search(q)
+		assert.Equal(t, b1, b2)
+		assert.Equal(t, v1, v2)
+		k1, _ := rb.SearchUp(q, i%3)
+		k2, _ := sl.SearchUp(q, i%3)
+		assert.Equal(t, k1, k2)
+		k1, _ = rb.SearchDown(q, i%3)
+		k2, _ = sl.SearchDown(q, i%3)
+		assert.Equal(t, k1, k2)
+	}
+	st := sl.ToStatic()
+	for _, n := range sl.InOrderTraverse() {
+		b, v := st.Search(n.Key())
+		assert.True(t, b)
+		assert.Equal(t, n.Val(), v)
+	}
+}
+
+func levels(sl *SkipList) []int {
+	lvls := []int{}
+	for n := sl.head.next[0]; n != nil; n = n.next[0] {
+		lvls = append(lvls, len(n.next))
+	}
+	return lvls
+}
+
+func TestSkipListCopySeeded(t *testing.T) {
+	sl, sl2, sl3 := NewSeeded(3), NewSeeded(3), NewSeeded(3)
+	for i := 0; i < 200; i++ {
+		n := testNode{compFloat(i), compFloat(i)}
+		sl.Insert(n)
+		sl2.Insert(n)
+		sl3.Insert(n)
+		if i%50 == 0 {
+			cp, cp2 := sl.Copy().(*SkipList), sl2.Copy().(*SkipList)
+			assert.Equal(t, levels(sl), levels(cp))
+			// Copies of lists with the same seed and history
+			// choose the same levels
+			for j := 0; j < 20; j++ {
+				cp.Insert(testNode{compFloat(-j - 1), 0})
+				cp2.Insert(testNode{compFloat(-j - 1), 0})
+			}
+			assert.Equal(t, levels(cp2), levels(cp))
+		}
+	}
+	// Copying does not change the levels a list chooses
+	assert.Equal(t, levels(sl3), levels(sl))
+}
@@ -0,0 +1,132 @@
+package tree
+
+import "github.com/200sc/go-compgeo/search"
+
+// A Monoid describes an aggregate to maintain over every
+// subtree of a BST: Measure gives the value of a single node,
+// from its key and all of its values, and Combine joins the
+// aggregates of two adjacent runs of keys, left then right.
+// Combine must be associative and have Identity as its
+// identity, but need not be commutative.
+//
+// For example, the greatest y extent of a set of edges is
+// kept by measuring each node's edges, combining with max,
+// and using negative infinity as the identity.
+type Monoid struct {
+	Identity interface{}
+	Measure  func(search.Comparable, []search.Equalable) interface{}
+	Combine  func(a, b interface{}) interface{}
+}
+
+// Count is a Monoid counting the values in each subtree,
+// as an order statistic tree needs.
+var Count = &Monoid{
+	Identity: 0,
+	Measure: func(_ search.Comparable, vs []search.Equalable) interface{} {
+		return len(vs)
+	},
+	Combine: func(a, b interface{}) interface{} {
+		return a.(int) + b.(int)
+	},
+}
+
+// An augment is the aggregate of a subtree under a monoid.
+type augment struct {
+	m *Monoid
+	v interface{}
+}
+
+// NewAugmented returns an empty tree of the given type
+// which maintains m's aggregate over each of its subtrees.
+// As with New, every type currently builds a RedBlack tree.
+func NewAugmented(typ Type, m *Monoid) *BST {
+	bst := New(typ).(*BST)
+	bst.monoid = m
+	return bst
+}
+
+func (n *node) aggregate(m *Monoid) interface{} {
+	if n == nil {
+		return m.Identity
+	}
+	return n.aug.v
+}
+
+// updateAug recalculates n's aggregate from its children's.
+func (n *node) updateAug() {
+	if n == nil || n.aug == nil {
+		return
+	}
+	m := n.aug.m
+	n.aug.v = m.Combine(
+		m.Combine(n.left.aggregate(m), m.Measure(n.key, n.val)),
+		n.right.aggregate(m))
+}
+
+// fixPath recalculates the aggregates of n and each of
+// its ancestors.
+func (n *node) fixPath() {
+	for ; n != nil && n.aug != nil; n = n.parent {
+		n.updateAug()
+	}
+}
+
+// Monoid returns the monoid bst maintains, if any.
+func (bst *BST) Monoid() *Monoid {
+	return bst.monoid
+}
+
+// Aggregate returns the aggregate of every node in bst, or
+// nil if bst maintains no monoid.
+func (bst *BST) Aggregate() interface{} {
+	if bst.monoid == nil {
+		return nil
+	}
+	return bst.root.aggregate(bst.monoid)
+}
+
+// AggregateRange returns the aggregate of the nodes in bst
+// with keys from lo to hi inclusive, in O(log n) time, or
+// nil if bst maintains no monoid.
+func (bst *BST) AggregateRange(lo, hi interface{}) interface{} {
+	m := bst.monoid
+	if m == nil {
+		return nil
+	}
+	n := bst.root
+	// Find the highest node within the range
+	for n != nil {
+		if n.key.Compare(lo) == search.Less {
+			n = n.right
+		} else if n.key.Compare(hi) == search.Greater {
+			n = n.left
+		} else {
+			break
+		}
+	}
+	if n == nil {
+		return m.Identity
+	}
+	// Everything in range is now either in n's left subtree
+	// and at least lo, n itself, or in n's right subtree and
+	// at most hi.
+	left := m.Identity
+	for l := n.left; l != nil; {
+		if l.key.Compare(lo) == search.Less {
+			l = l.right
+			continue
+		}
+		left = m.Combine(m.Combine(m.Measure(l.key, l.val), l.right.aggregate(m)), left)
+		l = l.left
+	}
+	right := m.Identity
+	for r := n.right; r != nil; {
+		if r.key.Compare(hi) == search.Greater {
+			r = r.left
+			continue
+		}
+		right = m.Combine(right, m.Combine(r.left.aggregate(m), m.Measure(r.key, r.val)))
+		r = r.right
+	}
+	return m.Combine(m.Combine(left, m.Measure(n.key, n.val)), right)
+}
@@ -0,0 +1,110 @@
+package tree
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sumMonoid = &Monoid{
+		Identity: 0.0,
+		Measure: func(k search.Comparable, vs []search.Equalable) interface{} {
+			sum := 0.0
+			for _, v := range vs {
+				sum += float64(v.(compFloat))
+			}
+			return sum
+		},
+		Combine: func(a, b interface{}) interface{} {
+			return a.(float64) + b.(float64)
+		},
+	}
+	// concatMonoid is not commutative, so it checks that
+	// aggregates combine in key order.
+	concatMonoid = &Monoid{
+		Identity: "",
+		Measure: func(k search.Comparable, vs []search.Equalable) interface{} {
+			return strconv.Itoa(int(k.(compFloat))) + ","
+		},
+		Combine: func(a, b interface{}) interface{} {
+			return a.(string) + b.(string)
+		},
+	}
+)
+
+func bruteAggregate(m *Monoid, ns []*node, lo, hi float64) interface{} {
+	agg := m.Identity
+	for _, n := range ns {
+		if n.key.Compare(lo) != search.Less && n.key.Compare(hi) != search.Greater {
+			agg = m.Combine(agg, m.Measure(n.key, n.val))
+		}
+	}
+	return agg
+}
+
+func checkAggregates(t *testing.T, bst *BST) {
+	var check func(*node)
+	check = func(n *node) {
+		if n == nil {
+			return
+		}
+		check(n.left)
+		check(n.right)
+		v := n.aug.v
+		n.updateAug()
+		assert.Equal(t, n.aug.v, v)
+	}
+	check(bst.root)
+	ns := inOrderNodes(bst.root)
+	for i := 0; i < 20; i++ {
+		lo := float64(rand.Intn(120) - 10)
+		hi := lo + float64(rand.Intn(50))
+		assert.Equal(t, bruteAggregate(bst.monoid, ns, lo, hi), bst.AggregateRange(lo, hi))
+	}
+	assert.Equal(t, bruteAggregate(bst.monoid, ns, -1, 1000), bst.Aggregate())
+}
+
+func TestAugmentedRB(t *testing.T) {
+	rand.Seed(6)
+	for _, m := range []*Monoid{sumMonoid, concatMonoid, Count} {
+		bst := NewAugmented(RedBlack, m)
+		for i := 0; i < 300; i++ {
+			bst.Insert(testNode{
+				compFloat(rand.Intn(100)),
+				compFloat(rand.Intn(100)),
+			})
+			if i%20 == 0 {
+				checkAggregates(t, bst)
+			}
+		}
+		checkAggregates(t, bst)
+		for i := 0; i < 300; i++ {
+			bst.Delete(nilValNode{compFloat(rand.Intn(100))})
+			if i%20 == 0 {
+				checkAggregates(t, bst)
+			}
+		}
+		checkAggregates(t, bst)
+
+		cp := bst.Copy().(*BST)
+		checkAggregates(t, cp)
+
+		l, r := bst.Split(50.0)
+		checkAggregates(t, l.(*BST))
+		checkAggregates(t, r.(*BST))
+		j, err := Join(l, r)
+		assert.Nil(t, err)
+		checkAggregates(t, j.(*BST))
+		assert.Equal(t, cp.Aggregate(), j.(*BST).Aggregate())
+		if m == Count {
+			assert.Equal(t, j.Size(), j.(*BST).Aggregate())
+		}
+	}
+	_, err := Join(NewAugmented(RedBlack, sumMonoid), New(RedBlack))
+	assert.NotNil(t, err)
+	assert.Nil(t, New(RedBlack).(*BST).Aggregate())
+}
@@ -22,8 +22,12 @@ type BST struct {
 	root *node
 	// Because the size of a bst is something someone might want
 	// to query quickly, we raise it to the top instead of making
-	// it a tree-wide count-up.
+	// it a tree-wide count-up. After a Split, size is negative
+	// until it is next asked for, as counting the halves would
+	// take linear time.
 	size int
+	// monoid, if set, is aggregated over every subtree.
+	monoid *Monoid
 }
 
 func (bst *BST) isValid() bool {
@@ -55,9 +59,19 @@ func (bst *BST) ToStatic() search.Static {
 
 // Size :
 func (bst *BST) Size() int {
+	if bst.size < 0 {
+		bst.size = bst.calcSize()
+	}
 	return bst.size
 }
 
+// grow adjusts bst's size by d, if its size is known.
+func (bst *BST) grow(d int) {
+	if bst.size >= 0 {
+		bst.size += d
+	}
+}
+
 func (bst *BST) calcSize() int {
 	return bst.root.calcSize()
 }
@@ -67,6 +81,9 @@ func (bst *BST) Insert(inNode search.Node) error {
 	n := new(node)
 	n.key = inNode.Key()
 	n.val = []search.Equalable{inNode.Val()}
+	if bst.monoid != nil {
+		n.aug = &augment{m: bst.monoid}
+	}
 	// We can't do this once we have more than RB trees wow
 	n.payload = red
 	var parent *node
@@ -84,7 +101,8 @@ func (bst *BST) Insert(inNode search.Node) error {
 		} else if r == search.Equal {
 			// All values of the same key are stored at the same node
 			curNode.val = append(curNode.val, inNode.Val())
-			bst.size++
+			curNode.fixPath()
+			bst.grow(1)
 			return nil
 		} else {
 			panic("Invalid types for BST operations")
@@ -107,7 +125,10 @@ func (bst *BST) Insert(inNode search.Node) error {
 		bst.root = n
 	}
 
-	bst.size++
+	bst.grow(1)
+	// Aggregates are made consistent before rebalancing,
+	// which rotations then preserve.
+	n.fixPath()
 	bst.updateRoot(bst.InsertFn(n))
 	return nil
 }
@@ -135,24 +156,50 @@ func (bst *BST) Delete(n search.Node) error {
 		for vi := 0; vi < len(curNode.val); vi++ {
 			if v.Equals(curNode.val[vi]) {
 				curNode.val = append(curNode.val[:vi], curNode.val[vi+1:]...)
-				bst.size--
+				curNode.fixPath()
+				bst.grow(-1)
 				return nil
 			}
 		}
 		return errors.New("Value not found")
 	}
-	bst.size--
-	bst.updateRoot(bst.DeleteFn(curNode))
+	bst.removeNode(curNode)
 	return nil
 }
 
-// Search :
+// removeNode removes n and all of its values from bst.
+func (bst *BST) removeNode(n *node) {
+	bst.grow(-len(n.val))
+	if n == bst.root && n.left == nil && n.right == nil {
+		bst.root = nil
+		return
+	}
+	// The deepest node whose subtree will change is the parent
+	// of the node physically removed: n itself, or n's successor
+	// if n has two children. Every node rebalancing leaves with
+	// an outdated aggregate is then an ancestor of that node.
+	start := n.parent
+	if n.left != nil && n.right != nil {
+		start = n.right.minKey()
+		if start.parent != n {
+			start = start.parent
+		}
+	}
+	bst.updateRoot(bst.DeleteFn(n))
+	start.fixPath()
+}
+
+// Search returns whether key exists in bst, and if it does
+// its first value. Search only modifies a splay tree, so
+// other trees are safe for concurrent searches.
 func (bst *BST) Search(key interface{}) (bool, interface{}) {
 	curNode, isReal := bst.search(key)
 	if !isReal {
 		return false, nil
 	}
-	bst.updateRoot(bst.SearchFn(curNode))
+	if n := bst.SearchFn(curNode); n != nil {
+		bst.updateRoot(n)
+	}
 	return true, curNode.val[0]
 }
 
@@ -264,6 +311,7 @@ func (bst *BST) Copy() interface{} {
 	newBst.root = bst.root.copy()
 	newBst.FnSet = bst.FnSet
 	newBst.size = bst.size
+	newBst.monoid = bst.monoid
 	return newBst
 }
 
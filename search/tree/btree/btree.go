@@ -0,0 +1,551 @@
+// package btree defines an in-memory B+-tree, which satisfies
+// search.Dynamic, for very large sets of keys.
+
+package btree
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/200sc/go-compgeo/printutil"
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree/fullCopy"
+)
+
+// DefaultOrder is a reasonable order for a BTree holding
+// interface keys, keeping each node within a few cache lines.
+const DefaultOrder = 32
+
+// A BTree is a B+-tree: every key and value is held in its
+// leaves, which are linked to one another in order, and its
+// internal nodes only route searches. Each node holds up to
+// order-1 keys, so the tree is only about log_order(n) deep
+// and a search touches few nodes.
+//
+// As with tree.BST, all values inserted with the same key are
+// stored together, and searches return the first of them.
+type BTree struct {
+	root  *node
+	order int
+	size  int
+}
+
+type node struct {
+	keys []search.Comparable
+	// children is nil on leaves. children[i+1] holds the
+	// keys at least keys[i].
+	children []*node
+	// vals and the neighbor links are only set on leaves.
+	vals       [][]search.Equalable
+	prev, next *node
+}
+
+func (n *node) isLeaf() bool {
+	return n.children == nil
+}
+
+// New returns an empty BTree where each node has at most
+// order children. order must be at least 3.
+func New(order int) (*BTree, error) {
+	if order < 3 {
+		return nil, errors.New("Invalid order")
+	}
+	return &BTree{
+		root:  &node{},
+		order: order,
+	}, nil
+}
+
+// Order returns the most children any node in bt may have.
+func (bt *BTree) Order() int {
+	return bt.order
+}
+
+// Size returns the number of values in bt.
+func (bt *BTree) Size() int {
+	return bt.size
+}
+
+// Height returns the number of levels in bt.
+func (bt *BTree) Height() int {
+	h := 1
+	for n := bt.root; !n.isLeaf(); n = n.children[0] {
+		h++
+	}
+	return h
+}
+
+func (bt *BTree) maxKeys() int {
+	return bt.order - 1
+}
+
+func (bt *BTree) minKeys(n *node) int {
+	if n.isLeaf() {
+		return bt.order / 2
+	}
+	return (bt.order+1)/2 - 1
+}
+
+func compare(k search.Comparable, key interface{}) search.CompareResult {
+	r := k.Compare(key)
+	if r == search.Invalid {
+		panic("Invalid types for BTree operations")
+	}
+	return r
+}
+
+// lowerBound returns the index of the first of keys not
+// less than key.
+func lowerBound(keys []search.Comparable, key interface{}) int {
+	lo, hi := 0, len(keys)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if compare(keys[mid], key) == search.Less {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// upperBound returns the index of the first of keys
+// greater than key.
+func upperBound(keys []search.Comparable, key interface{}) int {
+	lo, hi := 0, len(keys)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if compare(keys[mid], key) != search.Greater {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// leaf returns the leaf which would hold key.
+func (bt *BTree) leaf(key interface{}) *node {
+	n := bt.root
+	for !n.isLeaf() {
+		n = n.children[upperBound(n.keys, key)]
+	}
+	return n
+}
+
+// Insert adds inNode's key and value to bt.
+func (bt *BTree) Insert(inNode search.Node) error {
+	sep, right := bt.insert(bt.root, inNode.Key(), inNode.Val())
+	if right != nil {
+		bt.root = &node{
+			keys:     []search.Comparable{sep},
+			children: []*node{bt.root, right},
+		}
+	}
+	bt.size++
+	return nil
+}
+
+// insert adds k and v beneath n. If n splits, the new right
+// node and the least key beneath it are returned.
+func (bt *BTree) insert(n *node, k search.Comparable, v search.Equalable) (search.Comparable, *node) {
+	if n.isLeaf() {
+		i := lowerBound(n.keys, k)
+		if i < len(n.keys) && compare(n.keys[i], k) == search.Equal {
+			n.vals[i] = append(n.vals[i], v)
+			return nil, nil
+		}
+		n.keys = append(n.keys, nil)
+		copy(n.keys[i+1:], n.keys[i:])
+		n.keys[i] = k
+		n.vals = append(n.vals, nil)
+		copy(n.vals[i+1:], n.vals[i:])
+		n.vals[i] = []search.Equalable{v}
+		if len(n.keys) <= bt.maxKeys() {
+			return nil, nil
+		}
+		return bt.splitLeaf(n)
+	}
+	i := upperBound(n.keys, k)
+	sep, right := bt.insert(n.children[i], k, v)
+	if right == nil {
+		return nil, nil
+	}
+	n.keys = append(n.keys, nil)
+	copy(n.keys[i+1:], n.keys[i:])
+	n.keys[i] = sep
+	n.children = append(n.children, nil)
+	copy(n.children[i+2:], n.children[i+1:])
+	n.children[i+1] = right
+	if len(n.children) <= bt.order {
+		return nil, nil
+	}
+	return bt.splitInternal(n)
+}
+
+func (bt *BTree) splitLeaf(n *node) (search.Comparable, *node) {
+	mid := len(n.keys) / 2
+	right := &node{
+		keys: append([]search.Comparable{}, n.keys[mid:]...),
+		vals: append([][]search.Equalable{}, n.vals[mid:]...),
+		prev: n,
+		next: n.next,
+	}
+	n.keys = n.keys[:mid:mid]
+	n.vals = n.vals[:mid:mid]
+	if n.next != nil {
+		n.next.prev = right
+	}
+	n.next = right
+	return right.keys[0], right
+}
+
+func (bt *BTree) splitInternal(n *node) (search.Comparable, *node) {
+	mid := len(n.keys) / 2
+	sep := n.keys[mid]
+	right := &node{
+		keys:     append([]search.Comparable{}, n.keys[mid+1:]...),
+		children: append([]*node{}, n.children[mid+1:]...),
+	}
+	n.keys = n.keys[:mid:mid]
+	n.children = n.children[: mid+1 : mid+1]
+	return sep, right
+}
+
+// Delete removes a value from bt at n's key. As with tree.BST,
+// if multiple values are stored at that key, the first value
+// which Equals n's value is removed, and if there is only one
+// value it is removed outright.
+func (bt *BTree) Delete(n search.Node) error {
+	err := bt.delete(bt.root, n.Key(), n.Val())
+	if err != nil {
+		return err
+	}
+	if !bt.root.isLeaf() && len(bt.root.keys) == 0 {
+		bt.root = bt.root.children[0]
+	}
+	bt.size--
+	return nil
+}
+
+func (bt *BTree) delete(n *node, k search.Comparable, v search.Equalable) error {
+	if n.isLeaf() {
+		i := lowerBound(n.keys, k)
+		if i == len(n.keys) || compare(n.keys[i], k) != search.Equal {
+			return errors.New("Key not found")
+		}
+		if len(n.vals[i]) != 1 {
+			for vi, v2 := range n.vals[i] {
+				if v.Equals(v2) {
+					n.vals[i] = append(n.vals[i][:vi], n.vals[i][vi+1:]...)
+					return nil
+				}
+			}
+			return errors.New("Value not found")
+		}
+		n.keys = append(n.keys[:i], n.keys[i+1:]...)
+		n.vals = append(n.vals[:i], n.vals[i+1:]...)
+		return nil
+	}
+	i := upperBound(n.keys, k)
+	if err := bt.delete(n.children[i], k, v); err != nil {
+		return err
+	}
+	bt.rebalance(n, i)
+	return nil
+}
+
+// rebalance restores the minimum size of n's ith child, if
+// it has fallen below it, by borrowing from or merging with
+// a sibling.
+func (bt *BTree) rebalance(n *node, i int) {
+	c := n.children[i]
+	if len(c.keys) >= bt.minKeys(c) {
+		return
+	}
+	if i > 0 {
+		l := n.children[i-1]
+		if len(l.keys) > bt.minKeys(l) {
+			last := len(l.keys) - 1
+			if c.isLeaf() {
+				c.keys = append([]search.Comparable{l.keys[last]}, c.keys...)
+				c.vals = append([][]search.Equalable{l.vals[last]}, c.vals...)
+				l.vals = l.vals[:last]
+				n.keys[i-1] = c.keys[0]
+			} else {
+				c.keys = append([]search.Comparable{n.keys[i-1]}, c.keys...)
+				c.children = append([]*node{l.children[last+1]}, c.children...)
+				l.children = l.children[:last+1]
+				n.keys[i-1] = l.keys[last]
+			}
+			l.keys = l.keys[:last]
+			return
+		}
+	}
+	if i < len(n.children)-1 {
+		r := n.children[i+1]
+		if len(r.keys) > bt.minKeys(r) {
+			if c.isLeaf() {
+				c.keys = append(c.keys, r.keys[0])
+				c.vals = append(c.vals, r.vals[0])
+				r.keys = r.keys[1:]
+				r.vals = r.vals[1:]
+				n.keys[i] = r.keys[0]
+			} else {
+				c.keys = append(c.keys, n.keys[i])
+				c.children = append(c.children, r.children[0])
+				n.keys[i] = r.keys[0]
+				r.keys = r.keys[1:]
+				r.children = r.children[1:]
+			}
+			return
+		}
+		bt.merge(n, i)
+		return
+	}
+	bt.merge(n, i-1)
+}
+
+// merge joins n's i+1th child into its ith child.
+func (bt *BTree) merge(n *node, i int) {
+	l, r := n.children[i], n.children[i+1]
+	if l.isLeaf() {
+		l.keys = append(l.keys, r.keys...)
+		l.vals = append(l.vals, r.vals...)
+		l.next = r.next
+		if r.next != nil {
+			r.next.prev = l
+		}
+	} else {
+		l.keys = append(append(l.keys, n.keys[i]), r.keys...)
+		l.children = append(l.children, r.children...)
+	}
+	n.keys = append(n.keys[:i], n.keys[i+1:]...)
+	n.children = append(n.children[:i+1], n.children[i+2:]...)
+}
+
+// Search returns whether key exists in bt, and if it does
+// the first value stored at it.
+func (bt *BTree) Search(key interface{}) (bool, interface{}) {
+	n := bt.leaf(key)
+	i := lowerBound(n.keys, key)
+	if i == len(n.keys) || compare(n.keys[i], key) != search.Equal {
+		return false, nil
+	}
+	return true, n.vals[i][0]
+}
+
+// A cursor is a position in bt's leaves.
+type cursor struct {
+	n *node
+	i int
+}
+
+func (c *cursor) valid() bool {
+	return c.n != nil && c.i < len(c.n.keys)
+}
+
+// forward moves c to the next key, returning false and
+// leaving c alone if there is none.
+func (c *cursor) forward() bool {
+	n, i := c.n, c.i+1
+	for n != nil && i >= len(n.keys) {
+		n, i = n.next, 0
+	}
+	if n == nil {
+		return false
+	}
+	c.n, c.i = n, i
+	return true
+}
+
+// back moves c to the previous key, returning false and
+// leaving c alone if there is none.
+func (c *cursor) back() bool {
+	n, i := c.n, c.i-1
+	for n != nil && i < 0 {
+		n = n.prev
+		if n != nil {
+			i = len(n.keys) - 1
+		}
+	}
+	if n == nil {
+		return false
+	}
+	c.n, c.i = n, i
+	return true
+}
+
+// ceiling returns a cursor at the first key not less than
+// key. The cursor is invalid if there is no such key.
+func (bt *BTree) ceiling(key interface{}) cursor {
+	n := bt.leaf(key)
+	c := cursor{n, lowerBound(n.keys, key)}
+	if c.i == len(n.keys) {
+		c.i--
+		if !c.forward() {
+			c.i++
+		}
+	}
+	return c
+}
+
+// last returns a cursor at the greatest key in bt.
+func (bt *BTree) last() cursor {
+	n := bt.root
+	for !n.isLeaf() {
+		n = n.children[len(n.children)-1]
+	}
+	c := cursor{n, len(n.keys)}
+	c.back()
+	return c
+}
+
+// SearchUp performs a search, and rounds up to the nearest
+// existing key if no node of the query key exists, then
+// steps up to that many successors further. If there is
+// no key at least the query key, the greatest key is used.
+func (bt *BTree) SearchUp(key interface{}, up int) (search.Comparable, interface{}) {
+	if bt.size == 0 {
+		return nil, nil
+	}
+	c := bt.ceiling(key)
+	if !c.valid() {
+		c = bt.last()
+	}
+	for i := 0; i < up && c.forward(); i++ {
+	}
+	return c.n.keys[c.i], c.n.vals[c.i][0]
+}
+
+// SearchDown acts as SearchUp, but rounds down.
+func (bt *BTree) SearchDown(key interface{}, down int) (search.Comparable, interface{}) {
+	if bt.size == 0 {
+		return nil, nil
+	}
+	c := bt.ceiling(key)
+	if !c.valid() {
+		c = bt.last()
+	} else if compare(c.n.keys[c.i], key) != search.Equal {
+		c.back()
+	}
+	for i := 0; i < down && c.back(); i++ {
+	}
+	return c.n.keys[c.i], c.n.vals[c.i][0]
+}
+
+// Range returns every key in bt from lo to hi inclusive,
+// in order, by scanning across bt's leaves.
+func (bt *BTree) Range(lo, hi interface{}) []search.Node {
+	out := []search.Node{}
+	if bt.size == 0 {
+		return out
+	}
+	c := bt.ceiling(lo)
+	for c.valid() && compare(c.n.keys[c.i], hi) != search.Greater {
+		out = append(out, entry{c.n.keys[c.i], c.n.vals[c.i]})
+		if !c.forward() {
+			break
+		}
+	}
+	return out
+}
+
+// InOrderTraverse returns one node for each key in bt, in
+// ascending order.
+func (bt *BTree) InOrderTraverse() []search.Node {
+	out := make([]search.Node, 0, bt.size)
+	n := bt.root
+	for !n.isLeaf() {
+		n = n.children[0]
+	}
+	for ; n != nil; n = n.next {
+		for i, k := range n.keys {
+			out = append(out, entry{k, n.vals[i]})
+		}
+	}
+	return out
+}
+
+// ToStatic returns a Packed copy of bt.
+func (bt *BTree) ToStatic() search.Static {
+	return NewPacked(bt.InOrderTraverse())
+}
+
+// ToPersistent converts bt into a fully copying persistent
+// structure, as tree.BST does.
+func (bt *BTree) ToPersistent() search.DynamicPersistent {
+	return fullCopy.NewFullPersistentBST(bt)
+}
+
+// Copy returns a deep copy of bt.
+func (bt *BTree) Copy() interface{} {
+	var prev *node
+	var cp func(*node) *node
+	cp = func(n *node) *node {
+		n2 := &node{
+			keys: append([]search.Comparable{}, n.keys...),
+		}
+		if n.isLeaf() {
+			n2.vals = make([][]search.Equalable, len(n.vals))
+			for i, vs := range n.vals {
+				n2.vals[i] = append([]search.Equalable{}, vs...)
+			}
+			n2.prev = prev
+			if prev != nil {
+				prev.next = n2
+			}
+			prev = n2
+			return n2
+		}
+		n2.children = make([]*node, len(n.children))
+		for i, c := range n.children {
+			n2.children[i] = cp(c)
+		}
+		return n2
+	}
+	return &BTree{
+		root:  cp(bt.root),
+		order: bt.order,
+		size:  bt.size,
+	}
+}
+
+func (bt *BTree) String() string {
+	if bt.size == 0 {
+		return "<Empty BTree>\n"
+	}
+	return bt.root.string("")
+}
+
+func (n *node) string(prefix string) string {
+	s := prefix + "["
+	for i, k := range n.keys {
+		if i > 0 {
+			s += " "
+		}
+		s += printutil.String(k)
+		if n.isLeaf() {
+			s += fmt.Sprintf("%v", n.vals[i])
+		}
+	}
+	s += "]\n"
+	for _, c := range n.children {
+		s += c.string(prefix + "    ")
+	}
+	return s
+}
+
+// An entry is a key and all of its values.
+type entry struct {
+	key  search.Comparable
+	vals []search.Equalable
+}
+
+func (e entry) Key() search.Comparable {
+	return e.key
+}
+
+func (e entry) Val() search.Equalable {
+	return e.vals[0]
+}
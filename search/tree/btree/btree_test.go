@@ -0,0 +1,183 @@
+package btree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/skiplist"
+	"github.com/stretchr/testify/assert"
+)
+
+type compFloat float64
+
+func (f compFloat) Compare(i interface{}) search.CompareResult {
+	var f3 compFloat
+	switch f2 := i.(type) {
+	case float64:
+		f3 = compFloat(f2)
+	case compFloat:
+		f3 = f2
+	default:
+		return search.Invalid
+	}
+	if f == f3 {
+		return search.Equal
+	} else if f < f3 {
+		return search.Less
+	}
+	return search.Greater
+}
+
+func (f compFloat) Equals(e search.Equalable) bool {
+	switch f2 := e.(type) {
+	case compFloat:
+		return f == f2
+	}
+	return false
+}
+
+type testNode struct {
+	key compFloat
+	val compFloat
+}
+
+func (t testNode) Key() search.Comparable {
+	return t.key
+}
+
+func (t testNode) Val() search.Equalable {
+	return t.val
+}
+
+const (
+	randomInputCt    = 3000
+	randomInputRange = 1000
+)
+
+var _ search.Persistable = &BTree{}
+
+// valid checks that every leaf of bt is at the same depth,
+// every node is within its size bounds, and every key lies
+// between its ancestors' separators.
+func valid(t *testing.T, bt *BTree) {
+	depth := -1
+	var check func(n *node, d int, lo, hi search.Comparable)
+	check = func(n *node, d int, lo, hi search.Comparable) {
+		if n != bt.root {
+			assert.True(t, len(n.keys) >= bt.minKeys(n))
+		}
+		assert.True(t, len(n.keys) <= bt.maxKeys())
+		for i, k := range n.keys {
+			if lo != nil {
+				assert.NotEqual(t, search.Less, k.Compare(lo))
+			}
+			if hi != nil {
+				assert.Equal(t, search.Less, k.Compare(hi))
+			}
+			if i > 0 {
+				assert.Equal(t, search.Greater, k.Compare(n.keys[i-1]))
+			}
+		}
+		if n.isLeaf() {
+			if depth == -1 {
+				depth = d
+			}
+			assert.Equal(t, depth, d)
+			return
+		}
+		assert.Equal(t, len(n.keys)+1, len(n.children))
+		for i, c := range n.children {
+			clo, chi := lo, hi
+			if i > 0 {
+				clo = n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				chi = n.keys[i]
+			}
+			check(c, d+1, clo, chi)
+		}
+	}
+	check(bt.root, 0, nil, nil)
+}
+
+func TestBTreeInvalidOrder(t *testing.T) {
+	_, err := New(2)
+	assert.NotNil(t, err)
+}
+
+func TestBTreeMatchesSkipList(t *testing.T) {
+	rand.Seed(4)
+	for _, order := range []int{3, 4, 5, 16} {
+		bt, err := New(order)
+		assert.Nil(t, err)
+		sl := skiplist.NewSeeded(1)
+		for i := 0; i < randomInputCt; i++ {
+			n := testNode{
+				compFloat(rand.Intn(randomInputRange)),
+				compFloat(rand.Intn(randomInputRange)),
+			}
+			bt.Insert(n)
+			sl.Insert(n)
+		}
+		valid(t, bt)
+		cp := bt.Copy().(*BTree)
+		for i := 0; i < randomInputCt; i++ {
+			n := testNode{compFloat(rand.Intn(randomInputRange)), 0}
+			assert.Equal(t, sl.Delete(n) == nil, bt.Delete(n) == nil)
+		}
+		valid(t, bt)
+		valid(t, cp)
+		assert.Equal(t, sl.Size(), bt.Size())
+		assert.Equal(t, randomInputCt, cp.Size())
+		assert.Equal(t, len(sl.InOrderTraverse()), len(bt.InOrderTraverse()))
+		st := bt.ToStatic()
+		for i := 0; i < 1000; i++ {
+			q := rand.Float64() * randomInputRange
+			b1, v1 := sl.Search(q)
+			b2, v2 := bt.Search(q)
+			assert.Equal(t, b1, b2)
+			assert.Equal(t, v1, v2)
+			b2, v2 = st.Search(q)
+			assert.Equal(t, b1, b2)
+			assert.Equal(t, v1, v2)
+			k1, _ := sl.SearchUp(q, i%3)
+			k2, _ := bt.SearchUp(q, i%3)
+			k3, _ := st.SearchUp(q, i%3)
+			assert.Equal(t, k1, k2)
+			assert.Equal(t, k1, k3)
+			k1, _ = sl.SearchDown(q, i%3)
+			k2, _ = bt.SearchDown(q, i%3)
+			k3, _ = st.SearchDown(q, i%3)
+			assert.Equal(t, k1, k2)
+			assert.Equal(t, k1, k3)
+		}
+		// Emptying the tree leaves a valid, empty root
+		for cp.Size() > 0 {
+			k, v := cp.SearchUp(rand.Float64()*randomInputRange, 0)
+			assert.Nil(t, cp.Delete(testNode{k.(compFloat), v.(compFloat)}))
+			if cp.Size()%100 == 0 {
+				valid(t, cp)
+			}
+		}
+		assert.Equal(t, 0, cp.Size())
+		assert.Equal(t, 1, cp.Height())
+		k, _ := cp.SearchUp(1.0, 0)
+		assert.Nil(t, k)
+	}
+}
+
+func TestBTreeRange(t *testing.T) {
+	bt, _ := New(4)
+	for i := 0; i < 100; i++ {
+		bt.Insert(testNode{compFloat(i * 2), compFloat(i)})
+	}
+	ns := bt.Range(9.0, 21.0)
+	assert.Equal(t, 6, len(ns))
+	for i, n := range ns {
+		assert.Equal(t, compFloat(10+2*i), n.Key())
+	}
+	assert.Equal(t, 0, len(bt.Range(300.0, 400.0)))
+	assert.Equal(t, 100, len(bt.Range(-1.0, 400.0)))
+	assert.True(t, bt.Height() > 2)
+}
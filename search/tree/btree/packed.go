@@ -0,0 +1,90 @@
+package btree
+
+import (
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree/static"
+)
+
+// Packed is a static search structure holding keys and their
+// first values in two sorted, contiguous arrays, so that a
+// search is a binary search touching no pointers but the keys
+// themselves.
+type Packed struct {
+	keys []search.Comparable
+	vals []search.Equalable
+}
+
+// NewPacked returns a Packed structure over ns, which
+// must be sorted by key with no duplicate keys, as
+// InOrderTraverse returns.
+func NewPacked(ns []search.Node) *Packed {
+	p := &Packed{
+		keys: make([]search.Comparable, len(ns)),
+		vals: make([]search.Equalable, len(ns)),
+	}
+	for i, n := range ns {
+		p.keys[i] = n.Key()
+		p.vals[i] = n.Val()
+	}
+	return p
+}
+
+// Size returns the number of keys in p.
+func (p *Packed) Size() int {
+	return len(p.keys)
+}
+
+// Search returns whether key exists in p, and if it does
+// its value.
+func (p *Packed) Search(key interface{}) (bool, interface{}) {
+	i := lowerBound(p.keys, key)
+	if i == len(p.keys) || compare(p.keys[i], key) != search.Equal {
+		return false, nil
+	}
+	return true, p.vals[i]
+}
+
+// SearchUp acts as BTree.SearchUp.
+func (p *Packed) SearchUp(key interface{}, up int) (search.Comparable, interface{}) {
+	if len(p.keys) == 0 {
+		return nil, nil
+	}
+	i := lowerBound(p.keys, key) + up
+	if i >= len(p.keys) {
+		i = len(p.keys) - 1
+	}
+	return p.keys[i], p.vals[i]
+}
+
+// SearchDown acts as BTree.SearchDown.
+func (p *Packed) SearchDown(key interface{}, down int) (search.Comparable, interface{}) {
+	if len(p.keys) == 0 {
+		return nil, nil
+	}
+	i := lowerBound(p.keys, key)
+	if i == len(p.keys) || compare(p.keys[i], key) != search.Equal {
+		i--
+	}
+	i -= down
+	if i < 0 {
+		i = 0
+	}
+	return p.keys[i], p.vals[i]
+}
+
+// InOrderTraverse returns the keys and values in p in order.
+func (p *Packed) InOrderTraverse() []search.Node {
+	out := make([]search.Node, len(p.keys))
+	for i, k := range p.keys {
+		out[i] = static.NewNode(k, p.vals[i])
+	}
+	return out
+}
+
+// Copy returns a copy of p.
+func (p *Packed) Copy() interface{} {
+	return &Packed{
+		keys: append([]search.Comparable{}, p.keys...),
+		vals: append([]search.Equalable{}, p.vals...),
+	}
+}
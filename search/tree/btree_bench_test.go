@@ -0,0 +1,58 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/200sc/go-compgeo/search/tree/btree"
+)
+
+// These benchmarks compare trees at sizes where pointer
+// chasing dominates. They take a long time to set up.
+
+func BenchmarkRBDynamic1e6(b *testing.B) {
+	benchmarkLarge(b, New(RedBlack), 1e6, false)
+}
+func BenchmarkRBStatic1e6(b *testing.B) {
+	benchmarkLarge(b, New(RedBlack), 1e6, true)
+}
+func BenchmarkBTreeDynamic1e6(b *testing.B) {
+	bt, _ := btree.New(btree.DefaultOrder)
+	benchmarkLarge(b, bt, 1e6, false)
+}
+func BenchmarkBTreeStatic1e6(b *testing.B) {
+	bt, _ := btree.New(btree.DefaultOrder)
+	benchmarkLarge(b, bt, 1e6, true)
+}
+func BenchmarkRBDynamic1e7(b *testing.B) {
+	benchmarkLarge(b, New(RedBlack), 1e7, false)
+}
+func BenchmarkRBStatic1e7(b *testing.B) {
+	benchmarkLarge(b, New(RedBlack), 1e7, true)
+}
+func BenchmarkBTreeDynamic1e7(b *testing.B) {
+	bt, _ := btree.New(btree.DefaultOrder)
+	benchmarkLarge(b, bt, 1e7, false)
+}
+func BenchmarkBTreeStatic1e7(b *testing.B) {
+	bt, _ := btree.New(btree.DefaultOrder)
+	benchmarkLarge(b, bt, 1e7, true)
+}
+
+func benchmarkLarge(b *testing.B, d search.Dynamic, n int, toStatic bool) {
+	for _, i := range rand.Perm(n) {
+		d.Insert(testNode{compFloat(i), compFloat(i)})
+	}
+	var s search.Static = d
+	if toStatic {
+		s = d.ToStatic()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ok, _ := s.Search(float64(rand.Intn(2 * n)))
+		if ok {
+			j++
+		}
+	}
+}
@@ -13,6 +13,10 @@ import (
 // FullPersistentBST is an implementation of a persistent
 // binary search tree using full copies, with each
 // instant represented by a separate BST.
+//
+// Once no more instants will be set, AtInstant is safe to
+// call concurrently, so long as the BSTs it returns are only
+// searched. SetInstant, Insert and Delete are not.
 type FullPersistentBST struct {
 	instant float64
 	index   int
@@ -97,6 +101,22 @@ func (pbst *FullPersistentBST) SetInstant(ins float64) {
 	pbst.index++
 }
 
+// AppendInstant adds dyn as pbst's search tree from instant
+// ins onward, as SetInstant would with a copy of the tree at
+// the latest instant. This lets a persistent tree be rebuilt
+// from trees saved at each of its instants.
+func (pbst *FullPersistentBST) AppendInstant(ins float64, dyn search.Dynamic) {
+	if ins < pbst.instant {
+		panic("Decreasing instants is not yet supported")
+	} else if ins == pbst.instant {
+		pbst.instants[pbst.index].Dynamic = dyn
+		return
+	}
+	pbst.instants = append(pbst.instants, BSTInstant{Dynamic: dyn, instant: ins})
+	pbst.instant = ins
+	pbst.index++
+}
+
 // Insert peforms Insert on the current set instant's search tree.
 func (pbst *FullPersistentBST) Insert(n search.Node) error {
 	return pbst.AtInstant(pbst.instant).Insert(n)
@@ -16,6 +16,9 @@ type node struct {
 	// Each tree type might have a different payload on each node
 	// a good example of this is RED or BLACK on RBtrees.
 	payload interface{}
+	// aug holds this node's subtree aggregate, if its
+	// tree maintains a Monoid.
+	aug *augment
 
 	left, right, parent *node
 }
@@ -80,6 +83,9 @@ func (n *node) copy() *node {
 		cp.right.parent = cp
 	}
 	cp.payload = n.payload
+	if n.aug != nil {
+		cp.aug = &augment{n.aug.m, n.aug.v}
+	}
 
 	return cp
 }
@@ -203,6 +209,8 @@ func (n *node) leftRotate() (newRoot *node) {
 	}
 	r.left = n
 	n.parent = r
+	n.updateAug()
+	r.updateAug()
 	return
 }
 
@@ -224,6 +232,8 @@ func (n *node) rightRotate() (newRoot *node) {
 	}
 	l.right = n
 	n.parent = l
+	n.updateAug()
+	l.updateAug()
 	return
 }
 
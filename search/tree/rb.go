@@ -86,11 +86,20 @@ func (n *node) RBValid(mustBeBlack bool) (bool, int, error) {
 	return true, 1, nil
 }
 
-func rbInsert(n *node) (newRoot *node) {
+func rbInsert(n *node) *node {
+	newRoot, _ := rbInsertFixup(n)
+	return newRoot
+}
+
+// rbInsertFixup restores the RB properties above the red node n,
+// and also reports whether the tree's black height grew, which
+// happens exactly when the root had to be turned black.
+func rbInsertFixup(n *node) (newRoot *node, grew bool) {
 	for {
 		p := n.parent
 		if p == nil {
 			n.payload = black
+			grew = true
 			return
 		}
 		// i's parent must exist, as i is not the root ---
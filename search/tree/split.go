@@ -0,0 +1,254 @@
+package tree
+
+import (
+	"errors"
+
+	"github.com/200sc/go-compgeo/search"
+)
+
+// Split divides bst into two trees of its type, the first
+// holding every key less than key and the second every key
+// at least key. All values at a key stay together. bst is
+// emptied by the split, as its nodes are reused.
+//
+// On red black trees, Split takes O(log n) time. Other tree
+// types are rebuilt by reinsertion.
+func (bst *BST) Split(key interface{}) (search.Dynamic, search.Dynamic) {
+	left := &BST{FnSet: bst.FnSet, monoid: bst.monoid}
+	right := &BST{FnSet: bst.FnSet, monoid: bst.monoid}
+	if bst.FnSet != RbFnSet {
+		for _, n := range inOrderNodes(bst.root) {
+			dst := right
+			if n.key.Compare(key) == search.Less {
+				dst = left
+			}
+			for _, v := range n.val {
+				dst.Insert(valNode{n.key, v})
+			}
+		}
+	} else if bst.root != nil {
+		left.root, _, right.root, _ = rbSplit(bst.root, blackHeight(bst.root), key)
+		left.size = -1
+		right.size = -1
+	}
+	bst.root = nil
+	bst.size = 0
+	return left, right
+}
+
+// Join combines left and right, two trees of the same type
+// where every key in left is no greater than every key in
+// right, into one tree. If left's greatest key is right's
+// least key, their values are stored together. left and
+// right are emptied by the join, as their nodes are reused.
+//
+// On red black trees, Join takes O(log n) time. Other tree
+// types are rebuilt by reinsertion.
+func Join(left, right search.Dynamic) (search.Dynamic, error) {
+	l, ok := left.(*BST)
+	if !ok {
+		return nil, errors.New("Join requires BSTs")
+	}
+	r, ok := right.(*BST)
+	if !ok {
+		return nil, errors.New("Join requires BSTs")
+	}
+	if l.FnSet != r.FnSet || l.monoid != r.monoid {
+		return nil, errors.New("Join requires trees of the same type")
+	}
+	if l.root == nil || r.root == nil {
+		out := &BST{FnSet: l.FnSet, root: l.root, size: l.size, monoid: l.monoid}
+		if l.root == nil {
+			out.root, out.size = r.root, r.size
+		}
+		l.root, l.size = nil, 0
+		r.root, r.size = nil, 0
+		return out, nil
+	}
+	lMax := l.root.maxKey()
+	rMin := r.root.minKey()
+	switch lMax.key.Compare(rMin.key) {
+	case search.Greater:
+		return nil, errors.New("Trees to join overlap")
+	case search.Equal:
+		// All values of the same key are stored at the same node
+		r.removeNode(rMin)
+		lMax.val = append(lMax.val, rMin.val...)
+		lMax.fixPath()
+		l.grow(len(rMin.val))
+	}
+	out := &BST{FnSet: l.FnSet, size: -1, monoid: l.monoid}
+	if l.size >= 0 && r.size >= 0 {
+		out.size = l.size + r.size
+	}
+	if l.FnSet != RbFnSet {
+		out.root, out.size = l.root, l.size
+		for _, n := range inOrderNodes(r.root) {
+			for _, v := range n.val {
+				out.Insert(valNode{n.key, v})
+			}
+		}
+	} else if r.root != nil {
+		// The least key of right pivots the join
+		pivot := r.root.minKey()
+		r.removeNode(pivot)
+		out.root, _ = rbJoin(l.root, blackHeight(l.root), pivot, r.root, blackHeight(r.root))
+	} else {
+		out.root = l.root
+	}
+	l.root, l.size = nil, 0
+	r.root, r.size = nil, 0
+	return out, nil
+}
+
+// valNode is a key and one of its values, for reinserting
+// values from a node holding many.
+type valNode struct {
+	key search.Comparable
+	val search.Equalable
+}
+
+func (vn valNode) Key() search.Comparable {
+	return vn.key
+}
+
+func (vn valNode) Val() search.Equalable {
+	return vn.val
+}
+
+func inOrderNodes(n *node) []*node {
+	if n == nil {
+		return []*node{}
+	}
+	return append(append(inOrderNodes(n.left), n), inOrderNodes(n.right)...)
+}
+
+// blackHeight returns the number of black nodes on any path
+// from n down to a leaf, including n.
+func blackHeight(n *node) int {
+	h := 0
+	for ; n != nil; n = n.left {
+		if n.isBlack() {
+			h++
+		}
+	}
+	return h
+}
+
+// detach cuts n from its parent and makes it a black root,
+// returning its new black height given its old one, h.
+func detach(n *node, h int) (*node, int) {
+	if n == nil {
+		return nil, 0
+	}
+	n.parent = nil
+	if n.isRed() {
+		n.payload = black
+		h++
+	}
+	return n, h
+}
+
+// rbSplit splits the red black subtree at n, of black height h,
+// into the trees of keys less than key and of keys at least key,
+// returning each tree's root and black height. Each join made
+// while unwinding costs the difference in black height of its
+// trees, which sums to O(log n) over the whole split.
+func rbSplit(n *node, h int, key interface{}) (*node, int, *node, int) {
+	if n == nil {
+		return nil, 0, nil, 0
+	}
+	ch := h
+	if n.isBlack() {
+		ch--
+	}
+	l, lh := detach(n.left, ch)
+	r, rh := detach(n.right, ch)
+	if n.key.Compare(key) == search.Less {
+		rl, rlh, rr, rrh := rbSplit(r, rh, key)
+		l, lh = rbJoin(l, lh, n, rl, rlh)
+		return l, lh, rr, rrh
+	}
+	ll, llh, lr, lrh := rbSplit(l, lh, key)
+	r, rh = rbJoin(lr, lrh, n, r, rh)
+	return ll, llh, r, rh
+}
+
+// rbJoin joins the red black trees at a and b, of black
+// heights ah and bh, with k between them, where every key
+// in a is less than k's and every key in b is greater. It
+// returns the joined tree's root and black height in time
+// proportional to the difference in ah and bh.
+func rbJoin(a *node, ah int, k *node, b *node, bh int) (*node, int) {
+	k.parent = nil
+	k.left = nil
+	k.right = nil
+	k.payload = red
+	if ah == bh {
+		k.left = a
+		k.right = b
+		if a != nil {
+			a.parent = k
+		}
+		if b != nil {
+			b.parent = k
+		}
+		k.payload = black
+		k.updateAug()
+		return k, ah + 1
+	}
+	h := ah
+	if bh > ah {
+		h = bh
+	}
+	// Descend the inner spine of the taller tree to a black
+	// node whose black height matches the shorter tree.
+	var p *node
+	c, ch := a, ah
+	if bh > ah {
+		c, ch = b, bh
+	}
+	target := ah
+	if bh < ah {
+		target = bh
+	}
+	for !(c.isBlack() && ch == target) {
+		if c.isBlack() {
+			ch--
+		}
+		p = c
+		if bh > ah {
+			c = c.left
+		} else {
+			c = c.right
+		}
+	}
+	k.parent = p
+	if bh > ah {
+		k.left = a
+		k.right = c
+		p.left = k
+		if a != nil {
+			a.parent = k
+		}
+	} else {
+		k.left = c
+		k.right = b
+		p.right = k
+		if b != nil {
+			b.parent = k
+		}
+	}
+	if c != nil {
+		c.parent = k
+	}
+	k.fixPath()
+	_, grew := rbInsertFixup(k)
+	if grew {
+		h++
+	}
+	for k.parent != nil {
+		k = k.parent
+	}
+	return k, h
+}
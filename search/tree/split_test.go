@@ -0,0 +1,85 @@
+package tree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/200sc/go-compgeo/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBSplitJoin(t *testing.T) {
+	rand.Seed(5)
+	for trial := 0; trial < 50; trial++ {
+		tree := New(RedBlack)
+		ct := rand.Intn(500)
+		for i := 0; i < ct; i++ {
+			tree.Insert(testNode{
+				compFloat(float64(rand.Intn(300))),
+				compFloat(float64(i)),
+			})
+		}
+		keys := tree.InOrderTraverse()
+		splitKey := float64(rand.Intn(320) - 10)
+		l, r := tree.(*BST).Split(splitKey)
+		assert.Equal(t, 0, tree.Size())
+		for _, d := range []search.Dynamic{l, r} {
+			valid, err := RBValid(d.(*BST))
+			assert.True(t, valid)
+			assert.Nil(t, err)
+			assert.True(t, d.(*BST).isValid())
+		}
+		assert.Equal(t, ct, l.Size()+r.Size())
+		for _, n := range l.InOrderTraverse() {
+			assert.Equal(t, search.Less, n.Key().Compare(splitKey))
+		}
+		for _, n := range r.InOrderTraverse() {
+			assert.NotEqual(t, search.Less, n.Key().Compare(splitKey))
+		}
+		// Inserting into and deleting from split halves
+		// keeps them valid
+		l.Insert(testNode{-1, -1})
+		assert.Nil(t, l.Delete(testNode{-1, -1}))
+		valid, err := RBValid(l.(*BST))
+		assert.True(t, valid)
+		assert.Nil(t, err)
+
+		j, err := Join(l, r)
+		assert.Nil(t, err)
+		valid, err = RBValid(j.(*BST))
+		assert.True(t, valid)
+		assert.Nil(t, err)
+		assert.Equal(t, ct, j.Size())
+		assert.Equal(t, keys, j.InOrderTraverse())
+	}
+}
+
+func TestRBJoinEqualKeys(t *testing.T) {
+	l := New(RedBlack)
+	r := New(RedBlack)
+	for i := 0; i < 10; i++ {
+		l.Insert(testNode{compFloat(i), 1})
+		r.Insert(testNode{compFloat(i + 9), 2})
+	}
+	_, err := Join(r, l)
+	assert.NotNil(t, err)
+	j, err := Join(l, r)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, j.Size())
+	assert.Equal(t, 19, len(j.InOrderTraverse()))
+	valid, err := RBValid(j.(*BST))
+	assert.True(t, valid)
+	assert.Nil(t, err)
+	// Both values at 9 are kept, on one node
+	assert.Nil(t, j.Delete(testNode{9, 2}))
+	assert.Nil(t, j.Delete(testNode{9, 1}))
+	b, _ := j.Search(9.0)
+	assert.False(t, b)
+
+	l2, r2 := j.(*BST).Split(100.0)
+	assert.Equal(t, 18, l2.Size())
+	assert.Equal(t, 0, r2.Size())
+	j, err = Join(r2, l2)
+	assert.Nil(t, err)
+	assert.Equal(t, 18, j.Size())
+}
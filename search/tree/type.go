@@ -36,6 +36,8 @@ type FnSet struct {
 // New returns a tree as defined by the input type.
 // Hypothetically, this is the only exported function in this package
 // not on a tree structure.
+// AVL and Splay trees are not finished, so every type
+// currently builds a RedBlack tree.
 func New(typ Type) search.Persistable {
 	bst := new(BST)
 	switch typ {